@@ -0,0 +1,611 @@
+package gochat
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// REST/JSON接口，供后端服务不经micro client调用，挂载在APIPrefix下
+// 请求头 Authorization: Bearer <token>，token通过APIToken配置
+// 成功返回 {"data": ..., "next": "..."}，列表有下一页时next为下次请求的cursor
+// 失败返回 {"error": {"code": "...", "message": "..."}}
+
+const APIPrefix = "/api/v1"
+
+var (
+	// 一次最多查询在线状态的用户数
+	MaxPresence = 200
+)
+
+// APIError 接口错误，Status为http状态码
+type APIError struct {
+	Status     int     `json:"-"`
+	Code       string  `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after,omitempty"` // 秒，仅rate_limited
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// APIResponse 成功返回的内容
+type APIResponse struct {
+	Data interface{} `json:"data"`
+	Next string      `json:"next,omitempty"`
+}
+
+// 已知错误对应的状态码，其余为400
+var apiErrors = map[string]*APIError{
+	ErrNotFound.Error():     {Status: http.StatusNotFound, Code: "not_found"},
+	ErrNotMember.Error():    {Status: http.StatusForbidden, Code: "forbidden"},
+	ErrNotManager.Error():   {Status: http.StatusForbidden, Code: "forbidden"},
+	ErrMentionAll.Error():   {Status: http.StatusForbidden, Code: "forbidden"},
+	ErrScheduleType.Error(): {Status: http.StatusBadRequest, Code: "invalid_request"},
+}
+
+// apiError 将rpc错误转换为接口错误
+func apiError(err error) *APIError {
+	if e, ok := err.(*APIError); ok {
+		return e
+	}
+	if rl, ok := ParseRateLimitError(err); ok {
+		return &APIError{Status: http.StatusTooManyRequests, Code: "rate_limited", Message: rl.Scope, RetryAfter: rl.RetryAfter}
+	}
+	msg := errorDetail(err)
+	if known, ok := apiErrors[msg]; ok {
+		return &APIError{Status: known.Status, Code: known.Code, Message: msg}
+	}
+	return &APIError{Status: http.StatusBadRequest, Code: "bad_request", Message: msg}
+}
+
+func badRequest(msg string) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Code: "invalid_request", Message: msg}
+}
+
+type APIOption func(*apiServer)
+
+// APIToken 允许访问的token，name用于日志
+func APIToken(name, token string) APIOption {
+	return func(a *apiServer) {
+		sum := sha256.Sum256([]byte(token))
+		a.tokens = append(a.tokens, apiToken{name: name, hash: sum[:]})
+	}
+}
+
+// APILogger 设置日志
+func APILogger(l Logger) APIOption {
+	return func(a *apiServer) {
+		a.log = l
+	}
+}
+
+type apiToken struct {
+	name string
+	hash []byte
+}
+
+type apiServer struct {
+	cli    proto.ChatService
+	log    Logger
+	tokens []apiToken
+	routes []*apiRoute
+}
+
+// apiRequest 路由匹配后的请求
+type apiRequest struct {
+	*http.Request
+	params map[string]string
+	client string // token的名称
+}
+
+func (r *apiRequest) query(name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// decode 解析json请求体
+func (r *apiRequest) decode(v interface{}) error {
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxMessageSize)).Decode(v); err != nil {
+		return badRequest(err.Error())
+	}
+	return nil
+}
+
+// apiRoute 接口定义，同时用于生成OpenAPI文档
+type apiRoute struct {
+	method  string
+	path    string // 参数写作{name}
+	summary string
+	query   []apiParam
+	body    interface{} // 请求体的类型
+	result  interface{} // data的类型
+	list    bool        // 分页列表，支持limit及cursor参数
+	handle  func(ctx context.Context, r *apiRequest) (interface{}, string, error)
+}
+
+type apiParam struct {
+	name        string
+	kind        string // string、integer、boolean
+	description string
+}
+
+// NewAPIHandler REST接口，需挂载在APIPrefix下，如 mux.Handle(APIPrefix+"/", NewAPIHandler(cli))
+// 未配置token时拒绝所有请求，GET APIPrefix/openapi.json 返回接口文档且不需要认证
+func NewAPIHandler(cli proto.ChatService, opts ...APIOption) http.Handler {
+	a := &apiServer{cli: cli}
+	for _, o := range opts {
+		o(a)
+	}
+	a.log = loggerOrDefault(a.log)
+	a.routes = a.newRoutes()
+	return a
+}
+
+func (a *apiServer) newRoutes() []*apiRoute {
+	cli := a.cli
+	return []*apiRoute{
+		{
+			method: "GET", path: "/users", summary: "用户列表",
+			result: []*proto.User{}, list: true,
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				rsp, err := cli.Users(ctx, &proto.UsersRequest{})
+				if err != nil {
+					return nil, "", err
+				}
+				start, end, next, err := paginate(r, len(rsp.Users))
+				if err != nil {
+					return nil, "", err
+				}
+				return rsp.Users[start:end], next, nil
+			},
+		},
+		{
+			method: "POST", path: "/users", summary: "注册用户，已存在时忽略",
+			body: &proto.User{}, result: &proto.User{},
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				user := &proto.User{}
+				if err := r.decode(user); err != nil {
+					return nil, "", err
+				}
+				if len(user.Id) == 0 {
+					return nil, "", badRequest("id is required")
+				}
+				_, err := cli.Register(ctx, &proto.RegisterRequest{User: user})
+				return user, "", err
+			},
+		},
+		{
+			method: "DELETE", path: "/users/{id}", summary: "注销用户，所有平台下线",
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				_, err := cli.Unregister(ctx, &proto.UnregisterRequest{Id: r.params["id"]})
+				return nil, "", err
+			},
+		},
+		{
+			method: "GET", path: "/users/{id}/rooms", summary: "用户加入的房间",
+			result: []*proto.Room{}, list: true,
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				rsp, err := cli.Rooms(ctx, &proto.RoomsRequest{Id: r.params["id"]})
+				if err != nil {
+					return nil, "", err
+				}
+				start, end, next, err := paginate(r, len(rsp.Rooms))
+				if err != nil {
+					return nil, "", err
+				}
+				return rsp.Rooms[start:end], next, nil
+			},
+		},
+		{
+			method: "GET", path: "/rooms/{id}/members", summary: "房间成员",
+			query:  []apiParam{{"managers", "boolean", "只返回管理员"}},
+			result: []*proto.User{}, list: true,
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				rsp, err := cli.Members(ctx, &proto.MembersRequest{
+					RoomId:   r.params["id"],
+					Managers: r.query("managers") == "true",
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				start, end, next, err := paginate(r, len(rsp.Users))
+				if err != nil {
+					return nil, "", err
+				}
+				return rsp.Users[start:end], next, nil
+			},
+		},
+		{
+			method: "POST", path: "/rooms/{id}/members", summary: "加入房间",
+			body: &apiMember{},
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				m := &apiMember{}
+				if err := r.decode(m); err != nil {
+					return nil, "", err
+				}
+				if len(m.UserId) == 0 {
+					return nil, "", badRequest("user_id is required")
+				}
+				_, err := cli.Join(ctx, &proto.JoinRequest{Id: m.UserId, RoomId: r.params["id"]})
+				return nil, "", err
+			},
+		},
+		{
+			method: "DELETE", path: "/rooms/{id}/members/{user_id}", summary: "退出房间",
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				_, err := cli.Out(ctx, &proto.OutRequest{Id: r.params["user_id"], RoomId: r.params["id"]})
+				return nil, "", err
+			},
+		},
+		{
+			method: "POST", path: "/messages", summary: "以event.from的身份发送消息，send_at大于当前时间时定时发送",
+			body: &proto.SendRequest{}, result: &proto.SendResponse{},
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				req := &proto.SendRequest{}
+				if err := r.decode(req); err != nil {
+					return nil, "", err
+				}
+				if req.Event == nil || len(req.Event.From) == 0 {
+					return nil, "", badRequest("event.from is required")
+				}
+				if len(req.Event.Type) == 0 {
+					req.Event.Type = "message"
+				}
+				return wrap(cli.Send(ctx, req))
+			},
+		},
+		{
+			method: "GET", path: "/history", summary: "会话的历史消息，按时间正序，next为更早一页的cursor",
+			query: []apiParam{
+				{"user_id", "string", "查询者"},
+				{"to", "string", `房间为"房间id/"，私聊为对方id`},
+			},
+			result: []*proto.Message{}, list: true,
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				limit := historyLimit(int64(atoi(r.query("limit"))))
+				rsp, err := cli.History(ctx, &proto.HistoryRequest{
+					Id:     r.query("user_id"),
+					To:     r.query("to"),
+					Before: r.query("cursor"),
+					Limit:  int64(limit),
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				next := ""
+				if len(rsp.Messages) == limit {
+					next = rsp.Messages[0].Event.Id
+				}
+				return rsp.Messages, next, nil
+			},
+		},
+		{
+			method: "GET", path: "/presence", summary: "用户是否在线",
+			query:  []apiParam{{"ids", "string", "用户id，以逗号分隔"}},
+			result: []*proto.Presence{},
+			handle: func(ctx context.Context, r *apiRequest) (interface{}, string, error) {
+				ids := splitIds(r.query("ids"))
+				if len(ids) == 0 {
+					return nil, "", badRequest("ids is required")
+				}
+				rsp, err := cli.Presence(ctx, &proto.PresenceRequest{Ids: ids})
+				if err != nil {
+					return nil, "", err
+				}
+				return rsp.Presences, "", nil
+			},
+		},
+	}
+}
+
+// apiMember 加入房间的请求体
+type apiMember struct {
+	UserId string `json:"user_id"`
+}
+
+// wrap 只返回data
+func wrap(data interface{}, err error) (interface{}, string, error) {
+	return data, "", err
+}
+
+// paginate 按limit及cursor(偏移量)计算列表的范围
+func paginate(r *apiRequest, total int) (int, int, string, error) {
+	start := 0
+	if cursor := r.query("cursor"); len(cursor) > 0 {
+		n, err := strconv.Atoi(cursor)
+		if err != nil || n < 0 {
+			return 0, 0, "", badRequest("invalid cursor")
+		}
+		start = n
+	}
+	if start > total {
+		start = total
+	}
+	end := start + historyLimit(int64(atoi(r.query("limit"))))
+	if end >= total {
+		return start, total, "", nil
+	}
+	return start, end, strconv.Itoa(end), nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func (a *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, APIPrefix)
+	if r.Method == "GET" && path == "/openapi.json" {
+		writeAPI(w, http.StatusOK, a.openapi())
+		return
+	}
+
+	client, ok := a.authorize(r)
+	if !ok {
+		writeAPIError(w, &APIError{Status: http.StatusUnauthorized, Code: "unauthorized", Message: "invalid token"})
+		return
+	}
+
+	route, params, allowed := a.match(r.Method, path)
+	if route == nil {
+		if allowed {
+			writeAPIError(w, &APIError{Status: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "method not allowed"})
+		} else {
+			writeAPIError(w, &APIError{Status: http.StatusNotFound, Code: "not_found", Message: "not found"})
+		}
+		return
+	}
+
+	data, next, err := route.handle(r.Context(), &apiRequest{Request: r, params: params, client: client})
+	if err != nil {
+		e := apiError(err)
+		a.log.Warn("api request failed", F("client", client), F("method", r.Method), F("path", path), ErrField(err))
+		writeAPIError(w, e)
+		return
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeAPI(w, http.StatusOK, &APIResponse{Data: data, Next: next})
+}
+
+// authorize 校验bearer token，返回token的名称
+func (a *apiServer) authorize(r *http.Request) (string, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if len(token) == 0 {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(token))
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(sum[:], t.hash) == 1 {
+			return t.name, true
+		}
+	}
+	return "", false
+}
+
+// match 按方法及路径查找路由，allowed表示路径存在但方法不匹配
+func (a *apiServer) match(method, path string) (*apiRoute, map[string]string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	allowed := false
+	for _, route := range a.routes {
+		params, ok := matchPath(route.path, parts)
+		if !ok {
+			continue
+		}
+		if route.method == method {
+			return route, params, false
+		}
+		allowed = true
+	}
+	return nil, nil, allowed
+}
+
+func matchPath(pattern string, parts []string) (map[string]string, bool) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(segments) != len(parts) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if len(parts[i]) == 0 {
+				return nil, false
+			}
+			params[s[1:len(s)-1]] = parts[i]
+		} else if s != parts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func writeAPI(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, e *APIError) {
+	if e.Status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", strconv.Itoa(int(e.RetryAfter+0.999)))
+	}
+	writeAPI(w, e.Status, map[string]*APIError{"error": e})
+}
+
+// openapi 由路由表生成OpenAPI 3文档，schema由请求及返回类型的json标签生成
+func (a *apiServer) openapi() map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":        map[string]interface{}{"type": "string"},
+				"message":     map[string]interface{}{"type": "string"},
+				"retry_after": map[string]interface{}{"type": "number"},
+			},
+		},
+	}
+	errorResponse := map[string]interface{}{
+		"description": "错误",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"error": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+					},
+				},
+			},
+		},
+	}
+
+	paths := map[string]interface{}{}
+	for _, route := range a.routes {
+		params := []interface{}{}
+		for _, s := range strings.Split(route.path, "/") {
+			if strings.HasPrefix(s, "{") {
+				params = append(params, map[string]interface{}{
+					"name": s[1 : len(s)-1], "in": "path", "required": true,
+					"schema": map[string]interface{}{"type": "string"},
+				})
+			}
+		}
+		query := route.query
+		if route.list {
+			query = append(query,
+				apiParam{"limit", "integer", "每页条数"},
+				apiParam{"cursor", "string", "上一页返回的next"},
+			)
+		}
+		for _, q := range query {
+			params = append(params, map[string]interface{}{
+				"name": q.name, "in": "query", "description": q.description,
+				"schema": map[string]interface{}{"type": q.kind},
+			})
+		}
+
+		properties := map[string]interface{}{}
+		if route.result != nil {
+			properties["data"] = jsonSchema(reflect.TypeOf(route.result), schemas)
+		}
+		if route.list {
+			properties["next"] = map[string]interface{}{"type": "string"}
+		}
+		op := map[string]interface{}{
+			"summary":     route.summary,
+			"operationId": operationId(route),
+			"parameters":  params,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "成功",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{"type": "object", "properties": properties},
+						},
+					},
+				},
+				"default": errorResponse,
+			},
+		}
+		if route.body != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": jsonSchema(reflect.TypeOf(route.body), schemas),
+					},
+				},
+			}
+		}
+
+		item, ok := paths[APIPrefix+route.path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[APIPrefix+route.path] = item
+		}
+		item[strings.ToLower(route.method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": "go-chat", "version": "v1"},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearer": []interface{}{}}},
+	}
+}
+
+// operationId 如 GET /rooms/{id}/members 为getRoomsMembers
+func operationId(route *apiRoute) string {
+	id := strings.ToLower(route.method)
+	for _, s := range strings.Split(route.path, "/") {
+		if len(s) == 0 || strings.HasPrefix(s, "{") {
+			continue
+		}
+		id += strings.ToUpper(s[:1]) + s[1:]
+	}
+	return id
+}
+
+// jsonSchema 按json标签生成类型的schema，结构体放入schemas并返回引用
+func jsonSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int32, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": jsonSchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		// 先占位，处理自引用
+		schemas[name] = nil
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			if f.PkgPath != "" || tag == "-" || strings.HasPrefix(f.Name, "XXX_") {
+				continue
+			}
+			if len(tag) == 0 {
+				tag = f.Name
+			}
+			properties[tag] = jsonSchema(f.Type, schemas)
+		}
+		schemas[name] = map[string]interface{}{"type": "object", "properties": properties}
+		return ref
+	}
+	return map[string]interface{}{}
+}
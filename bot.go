@@ -0,0 +1,248 @@
+package gochat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var (
+	ErrBotToken  = errors.New("无效的机器人token")
+	ErrBotExists = errors.New("用户已存在，不能创建为机器人")
+)
+
+const (
+	// 机器人连接网关使用的平台，AuthBody.Password为机器人token
+	BotPlatform = "bot"
+	// 斜杠命令前缀
+	CommandPrefix = "/"
+	// 路由给机器人的命令事件类型
+	CommandEvent = "command"
+)
+
+// botSendKey BotSend发送时标记在context上，已按token验证机器人身份
+type botSendKey struct{}
+
+// Bot 机器人，同时也是一个普通用户
+type Bot struct {
+	Id          string    `db:"id"`
+	Name        string    `db:"name"`
+	Passthrough bool      `db:"passthrough"`
+	Created     time.Time `db:"created"`
+	Commands    []string  `db:"-"`
+}
+
+func (b *Bot) ToProto() *proto.Bot {
+	return &proto.Bot{
+		Id:          b.Id,
+		Name:        b.Name,
+		Commands:    b.Commands,
+		Passthrough: b.Passthrough,
+		Created:     b.Created.Unix(),
+	}
+}
+
+type BotRepository interface {
+	// 创建机器人并注册命令
+	CreateBot(bot *Bot, tokenHash string) error
+	// 删除机器人及其命令
+	DeleteBot(id string) error
+	// 机器人列表
+	Bots() ([]*Bot, error)
+	// 按token查询机器人
+	BotByToken(tokenHash string) (*Bot, error)
+	// 按命令查询机器人，未注册时返回nil
+	BotByCommand(command string) (*Bot, error)
+	// 按id查询机器人，不是机器人时返回nil
+	BotById(id string) (*Bot, error)
+}
+
+func NewBotRepo(db *sqlx.DB) *botRepo {
+	return &botRepo{
+		db: db,
+	}
+}
+
+type botRepo struct {
+	db *sqlx.DB
+}
+
+func (r *botRepo) CreateBot(bot *Bot, tokenHash string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(tx.Rebind(`
+		INSERT INTO bots (id, token_hash, passthrough) VALUES (?, ?, ?)
+		`), bot.Id, tokenHash, bot.Passthrough); err != nil {
+		return err
+	}
+	for _, c := range bot.Commands {
+		if _, err := tx.Exec(tx.Rebind(`
+			INSERT INTO bot_commands (bot_id, command) VALUES (?, ?)
+			`), bot.Id, c); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *botRepo) DeleteBot(id string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(tx.Rebind(`DELETE FROM bot_commands WHERE bot_id = ?`), id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(tx.Rebind(`DELETE FROM bots WHERE id = ?`), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *botRepo) Bots() ([]*Bot, error) {
+	bots := []*Bot{}
+	if err := r.db.Select(&bots, r.db.Rebind(`
+		SELECT b.id, u.name, b.passthrough, b.created FROM bots AS b JOIN users AS u ON u.id = b.id
+		`)); err != nil {
+		return bots, err
+	}
+	for _, bot := range bots {
+		if err := r.commands(bot); err != nil {
+			return bots, err
+		}
+	}
+	return bots, nil
+}
+
+func (r *botRepo) BotByToken(tokenHash string) (*Bot, error) {
+	bot := &Bot{}
+	if err := r.db.Get(bot, r.db.Rebind(`
+		SELECT b.id, u.name, b.passthrough, b.created FROM bots AS b JOIN users AS u ON u.id = b.id
+		WHERE b.token_hash = ?
+		`), tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrBotToken
+		}
+		return nil, err
+	}
+	return bot, r.commands(bot)
+}
+
+func (r *botRepo) BotByCommand(command string) (*Bot, error) {
+	bot := &Bot{}
+	if err := r.db.Get(bot, r.db.Rebind(`
+		SELECT b.id, u.name, b.passthrough, b.created FROM bot_commands AS c
+		JOIN bots AS b ON b.id = c.bot_id JOIN users AS u ON u.id = b.id
+		WHERE c.command = ?
+		`), command); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bot, r.commands(bot)
+}
+
+func (r *botRepo) BotById(id string) (*Bot, error) {
+	bot := &Bot{}
+	if err := r.db.Get(bot, r.db.Rebind(`
+		SELECT b.id, u.name, b.passthrough, b.created FROM bots AS b JOIN users AS u ON u.id = b.id
+		WHERE b.id = ?
+		`), id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bot, r.commands(bot)
+}
+
+func (r *botRepo) commands(bot *Bot) error {
+	bot.Commands = []string{}
+	return r.db.Select(&bot.Commands, r.db.Rebind(`SELECT command FROM bot_commands WHERE bot_id = ?`), bot.Id)
+}
+
+// NewBotToken 生成机器人api token，返回token及其存储用的hash
+func NewBotToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, HashBotToken(token), nil
+}
+
+// HashBotToken 数据库只保存token的hash
+func HashBotToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseCommand 解析"/command args"格式的消息，非命令时ok为false
+func ParseCommand(body string) (command, args string, ok bool) {
+	if !strings.HasPrefix(body, CommandPrefix) || len(body) == len(CommandPrefix) {
+		return "", "", false
+	}
+	sp := strings.SplitN(strings.TrimPrefix(body, CommandPrefix), " ", 2)
+	if len(sp[0]) == 0 {
+		return "", "", false
+	}
+	if len(sp) > 1 {
+		args = strings.TrimSpace(sp[1])
+	}
+	return sp[0], args, true
+}
+
+// NewBotHandler 机器人发送消息的http入口
+// 请求头 Authorization: Bearer <token>，请求体为Event的json
+func NewBotHandler(cli proto.ChatService) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if len(token) == 0 {
+			http.Error(w, "token is required", http.StatusUnauthorized)
+			return
+		}
+
+		event := &proto.Event{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(event.Type) == 0 {
+			event.Type = "message"
+		}
+
+		rsp, err := cli.BotSend(context.Background(), &proto.BotSendRequest{
+			Token: token,
+			Event: event,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": rsp.Id})
+	}
+}
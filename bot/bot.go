@@ -0,0 +1,147 @@
+// Package bot 基于gochat.Client编写聊天机器人的简易SDK
+//
+//	b, err := bot.New("weather", token)
+//	b.Handle("weather", func(ctx *bot.Context) error {
+//		return ctx.Reply("晴 " + ctx.Args)
+//	})
+//	b.Run()
+package bot
+
+import (
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// 机器人登录使用的平台
+const Platform = gochat.BotPlatform
+
+type HandlerFunc func(*Context) error
+
+type Options struct {
+	Host   string
+	Path   string
+	Logger gochat.Logger
+}
+
+type Option func(*Options)
+
+// Host 设置websocket网关地址
+func Host(host string) Option {
+	return func(o *Options) {
+		o.Host = host
+	}
+}
+
+// Logger 设置日志，默认为gochat.DefaultLogger
+func Logger(l gochat.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// Path 设置websocket路径
+func Path(path string) Option {
+	return func(o *Options) {
+		o.Path = path
+	}
+}
+
+type Bot struct {
+	log      gochat.Logger
+	client   *gochat.Client
+	commands map[string]HandlerFunc
+	message  HandlerFunc
+}
+
+// New 使用机器人id及创建时返回的token连接网关
+func New(id, token string, opts ...Option) (*Bot, error) {
+	o := Options{
+		Host:   "localhost:8082",
+		Path:   "/chat/stream",
+		Logger: gochat.DefaultLogger,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	client, err := gochat.NewPlatformClient(id, token, Platform, o.Host, o.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &Bot{
+		log:      o.Logger.With(gochat.UserField(id)),
+		client:   client,
+		commands: make(map[string]HandlerFunc),
+	}, nil
+}
+
+// Handle 注册斜杠命令处理函数，command不含"/"
+func (b *Bot) Handle(command string, h HandlerFunc) {
+	b.commands[command] = h
+}
+
+// HandleMessage 注册普通消息(如私聊)处理函数
+func (b *Bot) HandleMessage(h HandlerFunc) {
+	b.message = h
+}
+
+// Run 阻塞处理消息直到连接断开
+func (b *Bot) Run() error {
+	for m := range b.client.Messages() {
+		ctx := &Context{bot: b, Message: m}
+
+		var h HandlerFunc
+		if m.Type == gochat.CommandEvent {
+			command, args, ok := gochat.ParseCommand(m.Body)
+			if !ok {
+				continue
+			}
+			ctx.Command = command
+			ctx.Args = args
+			h = b.commands[command]
+		} else if m.From != b.client.Id {
+			h = b.message
+		}
+		if h == nil {
+			continue
+		}
+
+		if err := h(ctx); err != nil {
+			b.log.Warn("bot handle failed", gochat.F("command", ctx.Command), gochat.ErrField(err))
+		}
+	}
+	return nil
+}
+
+func (b *Bot) Close() error {
+	return b.client.Close()
+}
+
+// Client 返回底层的gochat.Client
+func (b *Bot) Client() *gochat.Client {
+	return b.client
+}
+
+// Send 以机器人身份发送消息，to为用户id或"roomId/"
+func (b *Bot) Send(to, body string) error {
+	return b.client.Send(&proto.Event{
+		Type: "message",
+		To:   to,
+		Body: body,
+	})
+}
+
+type Context struct {
+	bot     *Bot
+	Message *gochat.Message
+	Command string
+	Args    string
+}
+
+// Reply 回复到消息来源: 房间消息回复到房间，私聊回复给发送者
+func (c *Context) Reply(body string) error {
+	if len(c.Message.Room) > 0 {
+		return c.bot.Send(c.Message.Room+"/", body)
+	}
+	return c.bot.Send(c.Message.From, body)
+}
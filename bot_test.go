@@ -0,0 +1,100 @@
+package gochat_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// newDBServer 使用sqlite内存库的嵌入服务，启用机器人、端到端加密等
+func newDBServer(t *testing.T) (*gochat.Server, func()) {
+	db := sqlx.MustConnect("sqlite", ":memory:")
+	db.SetMaxOpenConns(1)
+	srv, err := gochat.NewServer(gochat.ServerDB(db))
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	return srv, func() {
+		srv.Close()
+		db.Close()
+	}
+}
+
+// 机器人只能通过BotSend以token发送，不能直接以机器人id调用Send
+func TestBotSendRequiresToken(t *testing.T) {
+	srv, closeFn := newDBServer(t)
+	defer closeFn()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	if _, err := cli.Register(ctx, &proto.RegisterRequest{User: &proto.User{Id: "alice", Name: "alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	created, err := cli.CreateBot(ctx, &proto.CreateBotRequest{Bot: &proto.Bot{Id: "weather", Name: "weather"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := func() *proto.Event {
+		return &proto.Event{Type: "message", From: "weather", To: "alice", Body: "hi"}
+	}
+	if _, err := cli.Send(ctx, &proto.SendRequest{Event: event()}); err == nil {
+		t.Fatal("Send as bot without token accepted")
+	}
+	if _, err := cli.BotSend(ctx, &proto.BotSendRequest{Token: "wrong", Event: event()}); err == nil {
+		t.Fatal("BotSend with wrong token accepted")
+	}
+	if _, err := cli.BotSend(ctx, &proto.BotSendRequest{Token: created.Token, Event: event()}); err != nil {
+		t.Fatalf("BotSend: %v", err)
+	}
+	// 普通用户不受影响
+	if _, err := cli.Send(ctx, &proto.SendRequest{
+		Event: &proto.Event{Type: "message", From: "alice", To: "weather", Body: "hi"},
+	}); err != nil {
+		t.Fatalf("Send as user: %v", err)
+	}
+}
+
+// failingBots 第一次CreateBot失败
+type failingBots struct {
+	gochat.BotRepository
+	failed bool
+}
+
+func (b *failingBots) CreateBot(bot *gochat.Bot, tokenHash string) error {
+	if !b.failed {
+		b.failed = true
+		return errors.New("insert failed")
+	}
+	return b.BotRepository.CreateBot(bot, tokenHash)
+}
+
+// 创建机器人失败时回滚已注册的用户，重试可以成功
+func TestCreateBotRollback(t *testing.T) {
+	db := sqlx.MustConnect("sqlite", ":memory:")
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	srv, err := gochat.NewServer(
+		gochat.ServerDB(db),
+		gochat.ServerHandlerOptions(gochat.WithBots(&failingBots{BotRepository: gochat.NewBotRepo(db)})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	req := &proto.CreateBotRequest{Bot: &proto.Bot{Id: "weather", Name: "weather"}}
+	if _, err := cli.CreateBot(ctx, req); err == nil {
+		t.Fatal("CreateBot: want error")
+	}
+	if _, err := cli.CreateBot(ctx, req); err != nil {
+		t.Fatalf("CreateBot retry: %v", err)
+	}
+}
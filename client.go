@@ -1,15 +1,19 @@
 package gochat
 
 import (
+	"encoding/json"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	proto "github.com/laoqiu/go-chat/proto"
 )
 
 const (
-	defaultHost = "localhost:8082"
-	defaultPath = "/chat/stream"
+	defaultHost     = "localhost:8082"
+	defaultPath     = "/chat/stream"
+	defaultPlatform = "client"
 )
 
 // A Client represents the connection between the application to the HipChat
@@ -18,22 +22,34 @@ type Client struct {
 	Id          string
 	MentionName string
 	Password    string
+	Platform    string
 
 	// private
 	connection      *websocket.Conn
+	codec           frameCodec
+	mu              sync.Mutex
 	receivedUsers   chan []*User
 	receivedRooms   chan []*Room
 	receivedMessage chan *Message
 	host            string
+	log             Logger
+
+	// 端到端加密
+	keys    *KeyStore
+	pmu     sync.Mutex
+	pending map[string]chan *proto.Event
 }
 
 // A Message represents a message received from HipChat.
 type Message struct {
+	Id          string
+	Room        string
 	From        string
 	To          string
 	Body        string
 	Type        string
 	MentionName string
+	Encrypted   bool
 }
 
 // A User represents a member of the HipChat service.
@@ -55,12 +71,22 @@ func NewClient(id, pass string) (*Client, error) {
 }
 
 func NewClientWithServerInfo(id, pass, host, path string) (*Client, error) {
+	return NewPlatformClient(id, pass, defaultPlatform, host, path)
+}
+
+// NewPlatformClient 以指定平台登录，同一用户每个平台只允许一个连接
+func NewPlatformClient(id, pass, platform, host, path string) (*Client, error) {
 	u := url.URL{Scheme: "ws", Host: host, Path: path}
-	connection, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	// 服务端支持时使用protobuf二进制帧及压缩
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = Subprotocols
+	dialer.EnableCompression = true
+	connection, _, err := dialer.Dial(u.String(), nil)
 
 	c := &Client{
 		Id:       id,
 		Password: pass,
+		Platform: platform,
 
 		// private
 		connection:      connection,
@@ -68,11 +94,14 @@ func NewClientWithServerInfo(id, pass, host, path string) (*Client, error) {
 		receivedRooms:   make(chan []*Room),
 		receivedMessage: make(chan *Message),
 		host:            host,
+		log:             DefaultLogger.With(UserField(id), PlatformField(platform)),
+		pending:         make(map[string]chan *proto.Event),
 	}
 
 	if err != nil {
 		return c, err
 	}
+	c.codec = codecFor(connection.Subprotocol())
 
 	err = c.authenticate()
 	if err != nil {
@@ -83,6 +112,11 @@ func NewClientWithServerInfo(id, pass, host, path string) (*Client, error) {
 	return c, nil
 }
 
+// SetLogger 设置日志，默认为DefaultLogger，应在创建后立即调用
+func (c *Client) SetLogger(l Logger) {
+	c.log = loggerOrDefault(l).With(UserField(c.Id), PlatformField(c.Platform))
+}
+
 func (c *Client) Close() error {
 	return c.connection.Close()
 }
@@ -104,21 +138,21 @@ func (c *Client) Users() <-chan []*User {
 }
 
 func (c *Client) Join(roomId string) error {
-	return c.connection.WriteJSON(&proto.Event{
+	return c.Send(&proto.Event{
 		Type: "join",
 		To:   roomId,
 	})
 }
 
 func (c *Client) Out(roomId string) error {
-	return c.connection.WriteJSON(&proto.Event{
+	return c.Send(&proto.Event{
 		Type: "out",
 		To:   roomId,
 	})
 }
 
 func (c *Client) Say(roomId, name, body string) error {
-	return c.connection.WriteJSON(&proto.Event{
+	return c.Send(&proto.Event{
 		Type: "message",
 		To:   name + "/" + roomId,
 		Body: body,
@@ -126,23 +160,88 @@ func (c *Client) Say(roomId, name, body string) error {
 }
 
 func (c *Client) RequestRooms() error {
-	return c.connection.WriteJSON(&proto.Event{
+	return c.Send(&proto.Event{
 		Type: "rooms",
 	})
 }
 
 func (c *Client) RequestUsers() error {
-	return c.connection.WriteJSON(&proto.Event{
+	return c.Send(&proto.Event{
 		Type: "users",
 	})
 }
 
+// Send 发送原始事件，可并发调用
+func (c *Client) Send(event *proto.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	messageType, data, err := c.codec.marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.connection.WriteMessage(messageType, data)
+}
+
 func (c *Client) authenticate() error {
-	return c.connection.WriteJSON(&proto.Event{
+	body, _ := json.Marshal(&AuthBody{
+		Id:       c.Id,
+		Password: c.Password,
+		Platform: c.Platform,
+		Start:    time.Now().Unix(),
+	})
+	return c.Send(&proto.Event{
 		Type: "auth",
 		From: c.Id,
+		Body: string(body),
 	})
 }
 
 func (c *Client) listen() {
+	defer close(c.receivedMessage)
+	for {
+		event := &proto.Event{}
+		_, data, err := c.connection.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := c.codec.unmarshal(data, event); err != nil {
+			c.log.Warn("client decode failed", ErrField(err))
+			continue
+		}
+		// 请求的响应
+		if c.resolve(event) {
+			continue
+		}
+		switch event.Type {
+		case "users":
+			users := []*User{}
+			if err := json.Unmarshal([]byte(event.Body), &users); err == nil {
+				c.receivedUsers <- users
+			}
+		case "rooms":
+			rooms := []*Room{}
+			if err := json.Unmarshal([]byte(event.Body), &rooms); err == nil {
+				c.receivedRooms <- rooms
+			}
+		case "message", CommandEvent:
+			roomId, _ := splitDest(event.To)
+			c.receivedMessage <- &Message{
+				Id:   event.Id,
+				Room: roomId,
+				From: event.From,
+				To:   event.To,
+				Body: event.Body,
+				Type: event.Type,
+			}
+		case EncryptedEvent:
+			m, err := c.decrypt(event)
+			if err != nil {
+				c.log.Warn("client decrypt failed", EventIdField(event.Id), ErrField(err))
+				continue
+			}
+			if m != nil {
+				c.receivedMessage <- m
+			}
+		}
+	}
 }
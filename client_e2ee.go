@@ -0,0 +1,615 @@
+package gochat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+	"golang.org/x/crypto/curve25519"
+)
+
+const requestTimeout = 10 * time.Second
+
+var (
+	ErrE2EENotEnabled   = errors.New("未开启端到端加密")
+	ErrIdentityChanged  = errors.New("对方身份密钥已变更")
+	ErrUnknownSenderKey = errors.New("未收到对方的sender key")
+)
+
+// plaintext 加密前的消息内容
+type plaintext struct {
+	Kind  string `json:"kind"` // message | sender_key
+	Body  string `json:"body,omitempty"`
+	Room  string `json:"room,omitempty"`
+	KeyId string `json:"key_id,omitempty"`
+	Key   []byte `json:"key,omitempty"`
+}
+
+type session struct {
+	key       []byte
+	ephemeral []byte
+	prekeyId  string
+}
+
+type senderKey struct {
+	id  string
+	key []byte
+}
+
+// KeyStore 客户端的端到端加密密钥，通过Export导出后由调用方保存，重启后LoadKeyStore恢复
+//
+// 私聊: 首次发送时从服务端取得对方的身份公钥及一个一次性prekey，
+// 与自己的身份密钥、临时密钥做三次X25519后经HKDF导出会话密钥。
+// 群聊: 每个成员为房间生成sender key，通过私聊加密分发给其他成员，
+// 房间消息只用sender key加密一次。
+type KeyStore struct {
+	mu sync.Mutex
+
+	identity    []byte
+	IdentityKey []byte
+
+	prekeys     map[string][]byte     // prekey id -> 私钥
+	identities  map[string][]byte     // 对方用户id -> 身份公钥(首次使用时信任)
+	outbound    map[string]*session   // 对方用户id -> 会话
+	inbound     map[string][]byte     // 对方用户id/临时公钥 -> 会话密钥
+	senderKeys  map[string]*senderKey // 房间id -> 自己的sender key
+	peerKeys    map[string][]byte     // 房间id/用户id/key id -> 对方的sender key
+	distributed map[string]string     // 房间id/用户id -> 已分发的sender key id
+}
+
+func NewKeyStore() (*KeyStore, error) {
+	priv, pub, err := newKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return &KeyStore{
+		identity:    priv,
+		IdentityKey: pub,
+		prekeys:     make(map[string][]byte),
+		identities:  make(map[string][]byte),
+		outbound:    make(map[string]*session),
+		inbound:     make(map[string][]byte),
+		senderKeys:  make(map[string]*senderKey),
+		peerKeys:    make(map[string][]byte),
+		distributed: make(map[string]string),
+	}, nil
+}
+
+// keyStoreState KeyStore导出的内容，包含私钥
+type keyStoreState struct {
+	Identity    []byte                     `json:"identity"`
+	IdentityKey []byte                     `json:"identity_key"`
+	Prekeys     map[string][]byte          `json:"prekeys"`
+	Identities  map[string][]byte          `json:"identities"`
+	Outbound    map[string]*sessionState   `json:"outbound"`
+	Inbound     map[string][]byte          `json:"inbound"`
+	SenderKeys  map[string]*senderKeyState `json:"sender_keys"`
+	PeerKeys    map[string][]byte          `json:"peer_keys"`
+	Distributed map[string]string          `json:"distributed"`
+}
+
+type sessionState struct {
+	Key       []byte `json:"key"`
+	Ephemeral []byte `json:"ephemeral"`
+	PrekeyId  string `json:"prekey_id,omitempty"`
+}
+
+type senderKeyState struct {
+	Id  string `json:"id"`
+	Key []byte `json:"key"`
+}
+
+// Export 导出身份密钥、未用的prekey私钥、已信任的身份及会话，内容包含私钥，需加密保存
+func (ks *KeyStore) Export() ([]byte, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	state := &keyStoreState{
+		Identity:    ks.identity,
+		IdentityKey: ks.IdentityKey,
+		Prekeys:     ks.prekeys,
+		Identities:  ks.identities,
+		Outbound:    make(map[string]*sessionState),
+		Inbound:     ks.inbound,
+		SenderKeys:  make(map[string]*senderKeyState),
+		PeerKeys:    ks.peerKeys,
+		Distributed: ks.distributed,
+	}
+	for uid, s := range ks.outbound {
+		state.Outbound[uid] = &sessionState{Key: s.key, Ephemeral: s.ephemeral, PrekeyId: s.prekeyId}
+	}
+	for roomId, sk := range ks.senderKeys {
+		state.SenderKeys[roomId] = &senderKeyState{Id: sk.id, Key: sk.key}
+	}
+	return json.Marshal(state)
+}
+
+// LoadKeyStore 恢复Export导出的KeyStore，重新上传时身份公钥不变，对方无需重新信任
+func LoadKeyStore(data []byte) (*KeyStore, error) {
+	state := &keyStoreState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if len(state.Identity) != curve25519.ScalarSize || len(state.IdentityKey) != curve25519.PointSize {
+		return nil, errors.New("invalid identity key")
+	}
+	ks := &KeyStore{
+		identity:    state.Identity,
+		IdentityKey: state.IdentityKey,
+		prekeys:     make(map[string][]byte),
+		identities:  make(map[string][]byte),
+		outbound:    make(map[string]*session),
+		inbound:     make(map[string][]byte),
+		senderKeys:  make(map[string]*senderKey),
+		peerKeys:    make(map[string][]byte),
+		distributed: make(map[string]string),
+	}
+	for k, v := range state.Prekeys {
+		ks.prekeys[k] = v
+	}
+	for k, v := range state.Identities {
+		ks.identities[k] = v
+	}
+	for uid, s := range state.Outbound {
+		ks.outbound[uid] = &session{key: s.Key, ephemeral: s.Ephemeral, prekeyId: s.PrekeyId}
+	}
+	for k, v := range state.Inbound {
+		ks.inbound[k] = v
+	}
+	for roomId, sk := range state.SenderKeys {
+		ks.senderKeys[roomId] = &senderKey{id: sk.Id, key: sk.Key}
+	}
+	for k, v := range state.PeerKeys {
+		ks.peerKeys[k] = v
+	}
+	for k, v := range state.Distributed {
+		ks.distributed[k] = v
+	}
+	return ks, nil
+}
+
+// Trust 重新信任对方的身份公钥，用于对方更换设备后(收到ErrIdentityChanged)经其他渠道核实
+// key为空时忘记对方的身份，下次取得或收到的身份公钥视为首次使用
+// 与对方的会话随之重建，自己的sender key在下次发送房间消息时重新分发给对方
+func (ks *KeyStore) Trust(uid string, key []byte) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if len(key) == 0 {
+		delete(ks.identities, uid)
+	} else {
+		ks.identities[uid] = key
+	}
+	delete(ks.outbound, uid)
+	for k := range ks.inbound {
+		if strings.HasPrefix(k, uid+"/") {
+			delete(ks.inbound, k)
+		}
+	}
+	for k := range ks.distributed {
+		if strings.HasSuffix(k, "/"+uid) {
+			delete(ks.distributed, k)
+		}
+	}
+}
+
+// NewPreKeys 生成n个一次性prekey，私钥保存在本地
+func (ks *KeyStore) NewPreKeys(n int) ([]*proto.PreKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	prekeys := []*proto.PreKey{}
+	for i := 0; i < n; i++ {
+		priv, pub, err := newKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		id := newEventId()
+		ks.prekeys[id] = priv
+		prekeys = append(prekeys, &proto.PreKey{Id: id, PublicKey: pub})
+	}
+	return prekeys, nil
+}
+
+// trust 首次使用时信任对方身份公钥，之后变更则拒绝
+func (ks *KeyStore) trust(uid string, key []byte) error {
+	if known, ok := ks.identities[uid]; ok {
+		if string(known) != string(key) {
+			return ErrIdentityChanged
+		}
+		return nil
+	}
+	ks.identities[uid] = key
+	return nil
+}
+
+// newSession 根据对方的公钥包建立发送会话
+func (ks *KeyStore) newSession(bundle *proto.KeyBundle) (*session, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ks.trust(bundle.UserId, bundle.IdentityKey); err != nil {
+		return nil, err
+	}
+	ephPriv, ephPub, err := newKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	dhs := [][]byte{}
+	s := &session{ephemeral: ephPub}
+	if bundle.Prekey != nil {
+		s.prekeyId = bundle.Prekey.Id
+		for _, pair := range [][2][]byte{
+			{ks.identity, bundle.Prekey.PublicKey},
+			{ephPriv, bundle.IdentityKey},
+			{ephPriv, bundle.Prekey.PublicKey},
+		} {
+			dh, err := curve25519.X25519(pair[0], pair[1])
+			if err != nil {
+				return nil, err
+			}
+			dhs = append(dhs, dh)
+		}
+	} else {
+		// prekey已用完，退化为身份密钥+临时密钥
+		for _, pair := range [][2][]byte{
+			{ks.identity, bundle.IdentityKey},
+			{ephPriv, bundle.IdentityKey},
+		} {
+			dh, err := curve25519.X25519(pair[0], pair[1])
+			if err != nil {
+				return nil, err
+			}
+			dhs = append(dhs, dh)
+		}
+	}
+
+	s.key, err = deriveKey(sessionInfo(ks.IdentityKey, bundle.IdentityKey), dhs...)
+	if err != nil {
+		return nil, err
+	}
+	ks.outbound[bundle.UserId] = s
+	return s, nil
+}
+
+func (ks *KeyStore) session(uid string) *session {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return ks.outbound[uid]
+}
+
+// sealPairwise 私聊加密
+func (ks *KeyStore) sealPairwise(s *session, from, to string, pt []byte) (*Envelope, error) {
+	nonce, ct, err := seal(s.key, pt, []byte(from+">"+to))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Version:    e2eeVersion,
+		Sender:     ks.IdentityKey,
+		Ephemeral:  s.ephemeral,
+		PreKeyId:   s.prekeyId,
+		Nonce:      nonce,
+		Ciphertext: ct,
+	}, nil
+}
+
+// openPairwise 私聊解密，首次收到某个临时公钥时导出并缓存会话密钥
+func (ks *KeyStore) openPairwise(env *Envelope, from, to string) ([]byte, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if err := ks.trust(from, env.Sender); err != nil {
+		return nil, err
+	}
+
+	cacheKey := from + "/" + base64.StdEncoding.EncodeToString(env.Ephemeral)
+	key, ok := ks.inbound[cacheKey]
+	if !ok {
+		pairs := [][2][]byte{}
+		if len(env.PreKeyId) > 0 {
+			opk, ok := ks.prekeys[env.PreKeyId]
+			if !ok {
+				return nil, errors.New("unknown prekey " + env.PreKeyId)
+			}
+			pairs = append(pairs,
+				[2][]byte{opk, env.Sender},
+				[2][]byte{ks.identity, env.Ephemeral},
+				[2][]byte{opk, env.Ephemeral},
+			)
+		} else {
+			pairs = append(pairs,
+				[2][]byte{ks.identity, env.Sender},
+				[2][]byte{ks.identity, env.Ephemeral},
+			)
+		}
+		dhs := [][]byte{}
+		for _, pair := range pairs {
+			dh, err := curve25519.X25519(pair[0], pair[1])
+			if err != nil {
+				return nil, err
+			}
+			dhs = append(dhs, dh)
+		}
+		var err error
+		key, err = deriveKey(sessionInfo(env.Sender, ks.IdentityKey), dhs...)
+		if err != nil {
+			return nil, err
+		}
+		ks.inbound[cacheKey] = key
+		// 一次性prekey用后即删
+		delete(ks.prekeys, env.PreKeyId)
+	}
+	return unseal(key, env.Nonce, env.Ciphertext, []byte(from+">"+to))
+}
+
+// senderKey 取得自己在房间的sender key，不存在时生成
+func (ks *KeyStore) senderKey(roomId, uid string) (*senderKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if sk, ok := ks.senderKeys[roomId]; ok {
+		return sk, nil
+	}
+	key, _, err := newKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	sk := &senderKey{id: newEventId(), key: key}
+	ks.senderKeys[roomId] = sk
+	// 自己发出的房间消息也会收到
+	ks.peerKeys[roomId+"/"+uid+"/"+sk.id] = sk.key
+	return sk, nil
+}
+
+// RotateSenderKey 房间成员变动后更换sender key，下次发送时重新分发
+func (ks *KeyStore) RotateSenderKey(roomId string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	delete(ks.senderKeys, roomId)
+	for k := range ks.distributed {
+		if strings.HasPrefix(k, roomId+"/") {
+			delete(ks.distributed, k)
+		}
+	}
+}
+
+func (ks *KeyStore) needDistribute(roomId, uid, keyId string) bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return ks.distributed[roomId+"/"+uid] != keyId
+}
+
+func (ks *KeyStore) markDistributed(roomId, uid, keyId string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.distributed[roomId+"/"+uid] = keyId
+}
+
+func (ks *KeyStore) setPeerSenderKey(roomId, uid, keyId string, key []byte) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.peerKeys[roomId+"/"+uid+"/"+keyId] = key
+}
+
+// openGroup 群聊解密
+func (ks *KeyStore) openGroup(env *Envelope, from, roomId string) ([]byte, error) {
+	ks.mu.Lock()
+	key, ok := ks.peerKeys[roomId+"/"+from+"/"+env.SenderKeyId]
+	ks.mu.Unlock()
+	if !ok {
+		return nil, ErrUnknownSenderKey
+	}
+	return unseal(key, env.Nonce, env.Ciphertext, []byte(from+">"+roomId+"/"))
+}
+
+func sessionInfo(senderIdentity, recipientIdentity []byte) []byte {
+	info := []byte(e2eeInfo)
+	info = append(info, senderIdentity...)
+	return append(info, recipientIdentity...)
+}
+
+// EnableE2EE 开启端到端加密，上传身份公钥及prekeys个一次性prekey
+// 只支持一个设备，其他设备已上传不同的身份公钥时返回错误，见ReplaceE2EE
+// 重启后应使用LoadKeyStore恢复的KeyStore，否则对方会收到ErrIdentityChanged
+func (c *Client) EnableE2EE(ks *KeyStore, prekeys int) error {
+	return c.uploadKeys(ks, prekeys, false)
+}
+
+// ReplaceE2EE 在当前设备开启端到端加密，替换其他设备上传的身份公钥
+// 原设备此后无法解密新消息，对方需调用KeyStore.Trust重新信任
+func (c *Client) ReplaceE2EE(ks *KeyStore, prekeys int) error {
+	return c.uploadKeys(ks, prekeys, true)
+}
+
+func (c *Client) uploadKeys(ks *KeyStore, prekeys int, replace bool) error {
+	c.keys = ks
+	pks, err := ks.NewPreKeys(prekeys)
+	if err != nil {
+		return err
+	}
+	body, _ := json.Marshal(&proto.UploadKeysRequest{
+		IdentityKey: ks.IdentityKey,
+		Prekeys:     pks,
+		Replace:     replace,
+	})
+	_, err = c.request(&proto.Event{
+		Type: "upload_keys",
+		Body: string(body),
+	})
+	return err
+}
+
+// SayEncrypted 发送端到端加密的私聊消息
+func (c *Client) SayEncrypted(to, body string) error {
+	pt, _ := json.Marshal(&plaintext{Kind: "message", Body: body})
+	return c.sendEncrypted(to, pt)
+}
+
+// SayRoomEncrypted 发送端到端加密的房间消息，members为当前房间成员
+// 尚未收到自己sender key的成员会先通过加密私聊收到sender key
+func (c *Client) SayRoomEncrypted(roomId, body string, members []string) error {
+	if c.keys == nil {
+		return ErrE2EENotEnabled
+	}
+	sk, err := c.keys.senderKey(roomId, c.Id)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m == c.Id || !c.keys.needDistribute(roomId, m, sk.id) {
+			continue
+		}
+		pt, _ := json.Marshal(&plaintext{
+			Kind:  "sender_key",
+			Room:  roomId,
+			KeyId: sk.id,
+			Key:   sk.key,
+		})
+		if err := c.sendEncrypted(m, pt); err != nil {
+			return err
+		}
+		c.keys.markDistributed(roomId, m, sk.id)
+	}
+
+	pt, _ := json.Marshal(&plaintext{Kind: "message", Body: body})
+	nonce, ct, err := seal(sk.key, pt, []byte(c.Id+">"+roomId+"/"))
+	if err != nil {
+		return err
+	}
+	d, _ := json.Marshal(&Envelope{
+		Version:     e2eeVersion,
+		SenderKeyId: sk.id,
+		Nonce:       nonce,
+		Ciphertext:  ct,
+	})
+	return c.Send(&proto.Event{
+		Type: EncryptedEvent,
+		To:   roomId + "/",
+		Body: string(d),
+	})
+}
+
+func (c *Client) sendEncrypted(to string, pt []byte) error {
+	if c.keys == nil {
+		return ErrE2EENotEnabled
+	}
+	s := c.keys.session(to)
+	if s == nil {
+		rsp, err := c.request(&proto.Event{
+			Type: "fetch_keys",
+			To:   to,
+		})
+		if err != nil {
+			return err
+		}
+		bundle := &proto.KeyBundle{}
+		if err := json.Unmarshal([]byte(rsp.Body), bundle); err != nil {
+			return err
+		}
+		if s, err = c.keys.newSession(bundle); err != nil {
+			return err
+		}
+	}
+
+	env, err := c.keys.sealPairwise(s, c.Id, to, pt)
+	if err != nil {
+		return err
+	}
+	d, _ := json.Marshal(env)
+	return c.Send(&proto.Event{
+		Type: EncryptedEvent,
+		To:   to,
+		Body: string(d),
+	})
+}
+
+// decrypt 解密收到的消息，sender key分发消息返回nil
+func (c *Client) decrypt(event *proto.Event) (*Message, error) {
+	if c.keys == nil {
+		return nil, ErrE2EENotEnabled
+	}
+	env := &Envelope{}
+	if err := json.Unmarshal([]byte(event.Body), env); err != nil {
+		return nil, err
+	}
+
+	var (
+		pt  []byte
+		err error
+	)
+	roomId, to := splitDest(event.To)
+	if len(env.SenderKeyId) > 0 {
+		pt, err = c.keys.openGroup(env, event.From, roomId)
+	} else {
+		pt, err = c.keys.openPairwise(env, event.From, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &plaintext{}
+	if err := json.Unmarshal(pt, p); err != nil {
+		return nil, err
+	}
+	if p.Kind == "sender_key" {
+		c.keys.setPeerSenderKey(p.Room, event.From, p.KeyId, p.Key)
+		return nil, nil
+	}
+	return &Message{
+		Id:        event.Id,
+		Room:      roomId,
+		From:      event.From,
+		To:        event.To,
+		Body:      p.Body,
+		Type:      "message",
+		Encrypted: true,
+	}, nil
+}
+
+// request 发送事件并等待相同id的响应
+func (c *Client) request(event *proto.Event) (*proto.Event, error) {
+	event.Id = newEventId()
+	ch := make(chan *proto.Event, 1)
+	c.pmu.Lock()
+	c.pending[event.Id] = ch
+	c.pmu.Unlock()
+	defer func() {
+		c.pmu.Lock()
+		delete(c.pending, event.Id)
+		c.pmu.Unlock()
+	}()
+
+	if err := c.Send(event); err != nil {
+		return nil, err
+	}
+	select {
+	case rsp := <-ch:
+		if rsp.Type == "error" {
+			return nil, errors.New(rsp.Body)
+		}
+		return rsp, nil
+	case <-time.After(requestTimeout):
+		return nil, errors.New("request timeout")
+	}
+}
+
+// resolve 将响应交给等待中的request
+func (c *Client) resolve(event *proto.Event) bool {
+	if len(event.Id) == 0 {
+		return false
+	}
+	c.pmu.Lock()
+	ch, ok := c.pending[event.Id]
+	c.pmu.Unlock()
+	if ok {
+		ch <- event
+	}
+	return ok
+}
@@ -0,0 +1,112 @@
+package gochat_test
+
+import (
+	"context"
+	"testing"
+
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// 重启后从导出的KeyStore恢复，身份不变且能解密发给旧prekey的消息
+func TestKeyStoreExport(t *testing.T) {
+	alice, _ := gochat.NewKeyStore()
+	bob, _ := gochat.NewKeyStore()
+	prekeys, err := bob.NewPreKeys(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := &proto.KeyBundle{UserId: "bob", IdentityKey: bob.IdentityKey, Prekey: prekeys[0]}
+	first, err := alice.SealTo(bundle, "alice", "bob", []byte("one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := bob.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := gochat.LoadKeyStore(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(restored.IdentityKey) != string(bob.IdentityKey) {
+		t.Fatal("identity key changed after load")
+	}
+	if pt, err := restored.Open(first, "alice", "bob"); err != nil || string(pt) != "one" {
+		t.Fatalf("Open after load: %q, %v", pt, err)
+	}
+
+	// 会话密钥也随导出保存，prekey用后已删除
+	data, _ = restored.Export()
+	restored, _ = gochat.LoadKeyStore(data)
+	second, _ := alice.SealTo(bundle, "alice", "bob", []byte("two"))
+	if pt, err := restored.Open(second, "alice", "bob"); err != nil || string(pt) != "two" {
+		t.Fatalf("Open second: %q, %v", pt, err)
+	}
+
+	if _, err := gochat.LoadKeyStore([]byte(`{}`)); err == nil {
+		t.Fatal("LoadKeyStore: empty identity accepted")
+	}
+}
+
+// 对方更换身份后需显式重新信任
+func TestKeyStoreTrust(t *testing.T) {
+	bob, _ := gochat.NewKeyStore()
+	bundle := &proto.KeyBundle{UserId: "bob", IdentityKey: bob.IdentityKey}
+
+	alice, _ := gochat.NewKeyStore()
+	env, _ := alice.SealTo(bundle, "alice", "bob", []byte("hi"))
+	if _, err := bob.Open(env, "alice", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	// alice换了新设备
+	alice, _ = gochat.NewKeyStore()
+	env, _ = alice.SealTo(bundle, "alice", "bob", []byte("again"))
+	if _, err := bob.Open(env, "alice", "bob"); err != gochat.ErrIdentityChanged {
+		t.Fatalf("Open new identity: err = %v, want ErrIdentityChanged", err)
+	}
+	bob.Trust("alice", alice.IdentityKey)
+	if pt, err := bob.Open(env, "alice", "bob"); err != nil || string(pt) != "again" {
+		t.Fatalf("Open after Trust: %q, %v", pt, err)
+	}
+}
+
+// 其他设备上传不同的身份公钥时需明确替换
+func TestUploadKeysSingleDevice(t *testing.T) {
+	srv, closeFn := newDBServer(t)
+	defer closeFn()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	web, _ := gochat.NewKeyStore()
+	mobile, _ := gochat.NewKeyStore()
+	upload := func(ks *gochat.KeyStore, replace bool) error {
+		prekeys, _ := ks.NewPreKeys(2)
+		_, err := cli.UploadKeys(ctx, &proto.UploadKeysRequest{
+			Id: "alice", IdentityKey: ks.IdentityKey, Prekeys: prekeys, Replace: replace,
+		})
+		return err
+	}
+	if err := upload(web, false); err != nil {
+		t.Fatal(err)
+	}
+	// 相同身份重新上传只追加prekeys
+	if err := upload(web, false); err != nil {
+		t.Fatalf("re-upload same identity: %v", err)
+	}
+	if rsp, err := cli.FetchKeys(ctx, &proto.FetchKeysRequest{Id: "bob", UserId: "alice"}); err != nil || rsp.Bundle.Prekey == nil {
+		t.Fatalf("FetchKeys: %+v, %v", rsp, err)
+	}
+	if err := upload(mobile, false); err == nil {
+		t.Fatal("second device identity accepted without replace")
+	}
+	if err := upload(mobile, true); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	rsp, err := cli.FetchKeys(ctx, &proto.FetchKeysRequest{Id: "bob", UserId: "alice"})
+	if err != nil || string(rsp.Bundle.IdentityKey) != string(mobile.IdentityKey) {
+		t.Fatalf("FetchKeys after replace: %+v, %v", rsp, err)
+	}
+}
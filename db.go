@@ -1,80 +1,421 @@
 package gochat
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/laoqiu/sqlxt"
 )
 
-var schemas = []string{
-	// 用户表
-	`CREATE TABLE IF NOT EXISTS users (
-		id VARCHAR(45) NOT NULL COMMENT '用户唯一标识',
-		name VARCHAR(45) NOT NULL COMMENT '昵称',
-		created DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '注册时间',
-		updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
-		PRIMARY KEY (id)
-	);`,
-	// 用户登录记录
-	`CREATE TABLE IF NOT EXISTS user_login_logs (
-		id INT(11) NOT NULL AUTO_INCREMENT,
-		user_id VARCHAR(45) NOT NULL COMMENT '用户名',
-		platform VARCHAR(20) NOT NULL COMMENT '平台',
-		ip VARCHAR(15) NOT NULL COMMENT 'ip地址',
-		browser_info VARCHAR(200) DEFAULT '' COMMENT '浏览器信息',
-		os_info VARCHAR(200) DEFAULT '' COMMENT '操作系统信息',
-		created DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '登录时间',
-		PRIMARY KEY (id)
-	);`,
-	// 用户状态
-	`CREATE TABLE IF NOT EXISTS user_status (
-		id INT(11) NOT NULL AUTO_INCREMENT,
-		user_id VARCHAR(45) NOT NULL COMMENT '用户名',
-		platform VARCHAR(20) NOT NULL COMMENT '平台',
-		is_online TINYINT(1) DEFAULT 0 COMMENT '当前在线',
-		created DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '登录/登出时间',
-		PRIMARY KEY (id),
-		UNIQUE KEY user_UNIQUE (user_id, platform)
-	);`,
-	// 组
-	`CREATE TABLE IF NOT EXISTS chatgroup (
-		id INT(11) NOT NULL AUTO_INCREMENT,
-		name VARCHAR(45) NOT NULL COMMENT '组名',
-		description VARCHAR(200) DEFAULT '' COMMENT '群组描述',
-		owner VARCHAR(45) NOT NULL COMMENT '创建者/群主',
-		public TINYINT(1) DEFAULT 1 COMMENT '是否公开，默认是',
-		maxmembers INT(11) DEFAULT 50 COMMENT '群成员上限',
-		created DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-		PRIMARY KEY (id)
-	);`,
-	// 组成员表
-	`CREATE TABLE IF NOT EXISTS chatgroup_members (
-		id INT(11) NOT NULL AUTO_INCREMENT,
-		group_id INT(11) NOT NULL COMMENT '组id',
-		member VARCHAR(45) NOT NULL COMMENT '成员名称',
-		is_manager TINYINT(1) DEFAULT 0 COMMENT '是否管理员，默认否',
-		created DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-		PRIMARY KEY (id),
-		INDEX group_id_IDX (group_id ASC)
-	);`,
+// Migrations 各方言的数据库版本，按Version顺序执行，已发布的版本不可修改只能追加
+// 各方言的版本号及名称保持一致
+var Migrations = map[Dialect][]Migration{
+	MySQL:    mysqlMigrations,
+	SQLite:   sqliteMigrations,
+	Postgres: postgresMigrations,
 }
 
-func Init(opts ...sqlxt.Option) (*sqlx.DB, error) {
+// mysql早期版本使用IF NOT EXISTS，兼容由旧版Init创建表结构的数据库
+var mysqlMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "init",
+		Up: []string{
+			// 用户表
+			`CREATE TABLE IF NOT EXISTS users (
+				id VARCHAR(45) NOT NULL COMMENT '用户唯一标识',
+				name VARCHAR(45) NOT NULL COMMENT '昵称',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '注册时间',
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+				PRIMARY KEY (id)
+			);`,
+			// 用户登录记录
+			`CREATE TABLE IF NOT EXISTS user_login_logs (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				user_id VARCHAR(45) NOT NULL COMMENT '用户名',
+				platform VARCHAR(20) NOT NULL COMMENT '平台',
+				ip VARCHAR(15) NOT NULL COMMENT 'ip地址',
+				browser_info VARCHAR(200) DEFAULT '' COMMENT '浏览器信息',
+				os_info VARCHAR(200) DEFAULT '' COMMENT '操作系统信息',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '登录时间',
+				PRIMARY KEY (id)
+			);`,
+			// 用户状态
+			`CREATE TABLE IF NOT EXISTS user_status (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				user_id VARCHAR(45) NOT NULL COMMENT '用户名',
+				platform VARCHAR(20) NOT NULL COMMENT '平台',
+				is_online TINYINT(1) DEFAULT 0 COMMENT '当前在线',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '登录/登出时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY user_UNIQUE (user_id, platform)
+			);`,
+			// 组
+			`CREATE TABLE IF NOT EXISTS chatgroup (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				name VARCHAR(45) NOT NULL COMMENT '组名',
+				description VARCHAR(200) DEFAULT '' COMMENT '群组描述',
+				owner VARCHAR(45) NOT NULL COMMENT '创建者/群主',
+				public TINYINT(1) DEFAULT 1 COMMENT '是否公开，默认是',
+				maxmembers INT(11) DEFAULT 50 COMMENT '群成员上限',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				PRIMARY KEY (id)
+			);`,
+			// 组成员表
+			`CREATE TABLE IF NOT EXISTS chatgroup_members (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				group_id INT(11) NOT NULL COMMENT '组id',
+				member VARCHAR(45) NOT NULL COMMENT '成员名称',
+				is_manager TINYINT(1) DEFAULT 0 COMMENT '是否管理员，默认否',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				INDEX group_id_IDX (group_id ASC)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS chatgroup_members;`,
+			`DROP TABLE IF EXISTS chatgroup;`,
+			`DROP TABLE IF EXISTS user_status;`,
+			`DROP TABLE IF EXISTS user_login_logs;`,
+			`DROP TABLE IF EXISTS users;`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "webhooks",
+		Up: []string{
+			// webhook回调地址
+			`CREATE TABLE IF NOT EXISTS webhooks (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				url VARCHAR(200) NOT NULL COMMENT '回调地址',
+				secret VARCHAR(64) NOT NULL COMMENT '签名密钥',
+				events VARCHAR(200) DEFAULT '' COMMENT '事件类型过滤，逗号分隔',
+				rooms VARCHAR(200) DEFAULT '' COMMENT '房间过滤，逗号分隔',
+				active TINYINT(1) DEFAULT 1 COMMENT '是否启用',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				PRIMARY KEY (id)
+			);`,
+			// webhook投递记录
+			`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				webhook_id INT(11) NOT NULL COMMENT 'webhook id',
+				delivery_id VARCHAR(45) NOT NULL COMMENT '投递唯一标识',
+				event_id VARCHAR(45) DEFAULT '' COMMENT '消息id',
+				event_type VARCHAR(20) NOT NULL COMMENT '事件类型',
+				attempt INT(11) DEFAULT 1 COMMENT '第几次尝试',
+				status_code INT(11) DEFAULT 0 COMMENT 'http状态码',
+				error VARCHAR(200) DEFAULT '' COMMENT '错误信息',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				INDEX webhook_id_IDX (webhook_id ASC)
+			);`,
+			// webhook死信
+			`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				webhook_id INT(11) NOT NULL COMMENT 'webhook id',
+				delivery_id VARCHAR(45) NOT NULL COMMENT '投递唯一标识',
+				payload TEXT NOT NULL COMMENT '投递内容',
+				error VARCHAR(200) DEFAULT '' COMMENT '最后一次错误信息',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				INDEX webhook_id_IDX (webhook_id ASC)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS webhook_dead_letters;`,
+			`DROP TABLE IF EXISTS webhook_deliveries;`,
+			`DROP TABLE IF EXISTS webhooks;`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "bots",
+		Up: []string{
+			// 机器人
+			`CREATE TABLE IF NOT EXISTS bots (
+				id VARCHAR(45) NOT NULL COMMENT '机器人用户id',
+				token_hash VARCHAR(64) NOT NULL COMMENT 'api token的sha256',
+				passthrough TINYINT(1) DEFAULT 0 COMMENT '命令消息是否同时发送到房间',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				UNIQUE KEY token_UNIQUE (token_hash)
+			);`,
+			// 机器人命令
+			`CREATE TABLE IF NOT EXISTS bot_commands (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				bot_id VARCHAR(45) NOT NULL COMMENT '机器人用户id',
+				command VARCHAR(45) NOT NULL COMMENT '斜杠命令，不含/',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				UNIQUE KEY command_UNIQUE (command)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS bot_commands;`,
+			`DROP TABLE IF EXISTS bots;`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "e2ee_keys",
+		Up: []string{
+			// 端到端加密身份公钥
+			`CREATE TABLE IF NOT EXISTS user_identity_keys (
+				user_id VARCHAR(45) NOT NULL COMMENT '用户名',
+				identity_key VARCHAR(64) NOT NULL COMMENT 'X25519公钥(base64)',
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				PRIMARY KEY (user_id)
+			);`,
+			// 端到端加密一次性prekey
+			`CREATE TABLE IF NOT EXISTS user_prekeys (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				user_id VARCHAR(45) NOT NULL COMMENT '用户名',
+				key_id VARCHAR(45) NOT NULL COMMENT '客户端生成的prekey id',
+				public_key VARCHAR(64) NOT NULL COMMENT 'X25519公钥(base64)',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				UNIQUE KEY prekey_UNIQUE (user_id, key_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS user_prekeys;`,
+			`DROP TABLE IF EXISTS user_identity_keys;`,
+		},
+	},
+	{
+		Version: 5,
+		Name:    "chatgroup_members_unique",
+		Up: []string{
+			// 去除重复成员，保留最早加入的记录
+			`DELETE m1 FROM chatgroup_members AS m1 JOIN chatgroup_members AS m2
+				ON m1.group_id = m2.group_id AND m1.member = m2.member AND m1.id > m2.id;`,
+			`ALTER TABLE chatgroup_members ADD UNIQUE KEY member_UNIQUE (group_id, member);`,
+		},
+		Down: []string{
+			`ALTER TABLE chatgroup_members DROP INDEX member_UNIQUE;`,
+		},
+	},
+	{
+		Version: 6,
+		Name:    "user_login_logs_sessions",
+		Up: []string{
+			// ip支持IPv6，按用户查询登录记录
+			`ALTER TABLE user_login_logs MODIFY ip VARCHAR(45) NOT NULL COMMENT 'ip地址', ADD INDEX user_id_IDX (user_id ASC);`,
+		},
+		Down: []string{
+			`ALTER TABLE user_login_logs DROP INDEX user_id_IDX, MODIFY ip VARCHAR(15) NOT NULL COMMENT 'ip地址';`,
+		},
+	},
+	{
+		Version: 7,
+		Name:    "message_receipts",
+		Up: []string{
+			// 消息在各接收者上的状态
+			`CREATE TABLE IF NOT EXISTS message_receipts (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				message_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				room_id VARCHAR(45) DEFAULT '' COMMENT '房间，私聊为空',
+				sender VARCHAR(45) NOT NULL COMMENT '发送者',
+				user_id VARCHAR(45) NOT NULL COMMENT '接收者',
+				status TINYINT(1) NOT NULL DEFAULT 1 COMMENT '1已发送 2已送达 3已读',
+				updated DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '状态更新时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY receipt_UNIQUE (message_id, user_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_receipts;`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "messages",
+		Up: []string{
+			// 消息历史
+			`CREATE TABLE IF NOT EXISTS messages (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				event_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				type VARCHAR(20) NOT NULL COMMENT '消息类型',
+				conversation VARCHAR(100) NOT NULL COMMENT '会话，房间id或排序后的两个用户id',
+				room_id VARCHAR(45) DEFAULT '' COMMENT '房间，私聊为空',
+				sender VARCHAR(45) NOT NULL COMMENT '发送者',
+				recipient VARCHAR(100) DEFAULT '' COMMENT '接收者，同Event.to',
+				body TEXT NOT NULL COMMENT '内容',
+				parent_id VARCHAR(45) DEFAULT '' COMMENT '回复的消息id',
+				reply_count INT(11) DEFAULT 0 COMMENT '回复数',
+				last_reply BIGINT DEFAULT 0 COMMENT '最后回复时间',
+				created BIGINT NOT NULL COMMENT '发送时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY event_UNIQUE (event_id),
+				INDEX conversation_IDX (conversation, parent_id, id),
+				INDEX parent_IDX (parent_id, id)
+			);`,
+			// 话题关注
+			`CREATE TABLE IF NOT EXISTS thread_followers (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				parent_id VARCHAR(45) NOT NULL COMMENT '话题根消息id',
+				user_id VARCHAR(45) NOT NULL COMMENT '关注者',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				UNIQUE KEY follower_UNIQUE (parent_id, user_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS thread_followers;`,
+			`DROP TABLE IF EXISTS messages;`,
+		},
+	},
+	{
+		Version: 9,
+		Name:    "message_reactions",
+		Up: []string{
+			// 消息表情
+			`CREATE TABLE IF NOT EXISTS message_reactions (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				event_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				user_id VARCHAR(45) NOT NULL COMMENT '用户',
+				emoji VARCHAR(32) NOT NULL COMMENT '表情',
+				created DATETIME DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (id),
+				UNIQUE KEY reaction_UNIQUE (event_id, user_id, emoji)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_reactions;`,
+		},
+	},
+	{
+		Version: 10,
+		Name:    "mentions",
+		Up: []string{
+			// 提及收件箱
+			`CREATE TABLE IF NOT EXISTS mentions (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				user_id VARCHAR(45) NOT NULL COMMENT '被提及的用户',
+				event_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				room_id VARCHAR(45) NOT NULL COMMENT '房间',
+				sender VARCHAR(45) NOT NULL COMMENT '发送者',
+				body TEXT NOT NULL COMMENT '内容',
+				kind VARCHAR(10) NOT NULL COMMENT 'user、all或here',
+				created BIGINT NOT NULL COMMENT '发送时间',
+				is_read TINYINT(1) DEFAULT 0 COMMENT '是否已读',
+				PRIMARY KEY (id),
+				UNIQUE KEY mention_UNIQUE (user_id, event_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS mentions;`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "room_pins",
+		Up: []string{
+			// 房间公告
+			`ALTER TABLE chatgroup ADD COLUMN notice VARCHAR(1000) DEFAULT '' COMMENT '公告';`,
+			`ALTER TABLE chatgroup ADD COLUMN notice_by VARCHAR(45) DEFAULT '' COMMENT '设置公告的管理员';`,
+			`ALTER TABLE chatgroup ADD COLUMN notice_updated BIGINT DEFAULT 0 COMMENT '公告更新时间';`,
+			// 置顶消息
+			`CREATE TABLE IF NOT EXISTS room_pins (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				room_id VARCHAR(45) NOT NULL COMMENT '房间',
+				event_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				pinned_by VARCHAR(45) NOT NULL COMMENT '置顶的管理员',
+				created BIGINT NOT NULL COMMENT '置顶时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY pin_UNIQUE (room_id, event_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS room_pins;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice_updated;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice_by;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice;`,
+		},
+	},
+	{
+		Version: 12,
+		Name:    "scheduled_messages",
+		Up: []string{
+			// 定时消息，发送后删除
+			`CREATE TABLE IF NOT EXISTS scheduled_messages (
+				id INT(11) NOT NULL AUTO_INCREMENT,
+				event_id VARCHAR(45) NOT NULL COMMENT '消息id',
+				sender VARCHAR(45) NOT NULL COMMENT '发送者',
+				event TEXT NOT NULL COMMENT '消息json',
+				send_at BIGINT NOT NULL COMMENT '发送时间',
+				claimed_at BIGINT DEFAULT 0 COMMENT '节点领取时间，0为待发送',
+				created BIGINT NOT NULL COMMENT '创建时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY event_id_UNIQUE (event_id),
+				INDEX send_at_IDX (send_at ASC),
+				INDEX sender_IDX (sender ASC)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS scheduled_messages;`,
+		},
+	},
+	{
+		Version: 13,
+		Name:    "message_ttl",
+		Up: []string{
+			`ALTER TABLE messages ADD COLUMN expires BIGINT DEFAULT 0 COMMENT '过期时间，0为不过期', ADD INDEX expires_IDX (expires ASC);`,
+			// 会话的消息有效期
+			`CREATE TABLE IF NOT EXISTS message_ttl (
+				conversation VARCHAR(100) NOT NULL COMMENT '会话，房间id或排序后的两个用户id',
+				ttl BIGINT NOT NULL COMMENT '有效期(秒)',
+				updated_by VARCHAR(45) NOT NULL COMMENT '设置者',
+				updated BIGINT NOT NULL COMMENT '设置时间',
+				PRIMARY KEY (conversation)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_ttl;`,
+			`ALTER TABLE messages DROP INDEX expires_IDX, DROP COLUMN expires;`,
+		},
+	},
+}
+
+// Connect 连接数据库，不执行迁移
+// mysql以外的驱动(sqlite、postgres、pgx)需由调用方导入
+func Connect(opts ...sqlxt.Option) (*sqlx.DB, error) {
+	var (
+		db  *sqlx.DB
+		err error
+	)
 	o := sqlxt.NewOptions(opts...)
-	db, err := sqlxt.Connect(o.Driver, o.URI, o.Charset, o.ParseTime, o.MaxClient, o.MaxClient)
+	switch o.Driver {
+	case "", "mysql":
+		db, err = sqlxt.Connect(o.Driver, o.URI, o.Charset, o.ParseTime, o.MaxClient, o.MaxClient)
+	default:
+		db, err = sqlx.Connect(o.Driver, o.URI)
+		if err == nil {
+			db.SetMaxIdleConns(o.MaxClient)
+			db.SetMaxOpenConns(o.MaxClient)
+		}
+	}
 	if err != nil {
 		return db, err
 	}
 	// 加载mapper
 	sqlxt.LoadMapper(db, sqlxt.DefaultMapper)
+	return db, nil
+}
+
+// Init 连接数据库并执行未执行的迁移
+func Init(opts ...sqlxt.Option) (*sqlx.DB, error) {
+	db, err := Connect(opts...)
+	if err != nil {
+		return db, err
+	}
 	// 初始化表结构
-	for _, s := range schemas {
-		_, err = db.Exec(s)
-		if err != nil {
-			return db, err
-		}
+	if err := NewMigrator(db, Migrations[DialectOf(db)], nil).Up(context.Background()); err != nil {
+		return db, err
 	}
 	return db, nil
 }
@@ -0,0 +1,324 @@
+package gochat
+
+// sqlite没有ON UPDATE，updated由语句显式更新
+var sqliteMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "init",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS users (
+				id VARCHAR(45) NOT NULL PRIMARY KEY,
+				name VARCHAR(45) NOT NULL,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS user_login_logs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id VARCHAR(45) NOT NULL,
+				platform VARCHAR(20) NOT NULL,
+				ip VARCHAR(15) NOT NULL,
+				browser_info VARCHAR(200) DEFAULT '',
+				os_info VARCHAR(200) DEFAULT '',
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS user_status (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id VARCHAR(45) NOT NULL,
+				platform VARCHAR(20) NOT NULL,
+				is_online BOOLEAN DEFAULT FALSE,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, platform)
+			);`,
+			`CREATE TABLE IF NOT EXISTS chatgroup (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name VARCHAR(45) NOT NULL,
+				description VARCHAR(200) DEFAULT '',
+				owner VARCHAR(45) NOT NULL,
+				public BOOLEAN DEFAULT TRUE,
+				maxmembers INTEGER DEFAULT 50,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS chatgroup_members (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				group_id INTEGER NOT NULL,
+				member VARCHAR(45) NOT NULL,
+				is_manager BOOLEAN DEFAULT FALSE,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS chatgroup_members_group_id_idx ON chatgroup_members (group_id);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS chatgroup_members;`,
+			`DROP TABLE IF EXISTS chatgroup;`,
+			`DROP TABLE IF EXISTS user_status;`,
+			`DROP TABLE IF EXISTS user_login_logs;`,
+			`DROP TABLE IF EXISTS users;`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "webhooks",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS webhooks (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				url VARCHAR(200) NOT NULL,
+				secret VARCHAR(64) NOT NULL,
+				events VARCHAR(200) DEFAULT '',
+				rooms VARCHAR(200) DEFAULT '',
+				active BOOLEAN DEFAULT TRUE,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				webhook_id INTEGER NOT NULL,
+				delivery_id VARCHAR(45) NOT NULL,
+				event_id VARCHAR(45) DEFAULT '',
+				event_type VARCHAR(20) NOT NULL,
+				attempt INTEGER DEFAULT 1,
+				status_code INTEGER DEFAULT 0,
+				error VARCHAR(200) DEFAULT '',
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id);`,
+			`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				webhook_id INTEGER NOT NULL,
+				delivery_id VARCHAR(45) NOT NULL,
+				payload TEXT NOT NULL,
+				error VARCHAR(200) DEFAULT '',
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS webhook_dead_letters_webhook_id_idx ON webhook_dead_letters (webhook_id);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS webhook_dead_letters;`,
+			`DROP TABLE IF EXISTS webhook_deliveries;`,
+			`DROP TABLE IF EXISTS webhooks;`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "bots",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS bots (
+				id VARCHAR(45) NOT NULL PRIMARY KEY,
+				token_hash VARCHAR(64) NOT NULL UNIQUE,
+				passthrough BOOLEAN DEFAULT FALSE,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS bot_commands (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				bot_id VARCHAR(45) NOT NULL,
+				command VARCHAR(45) NOT NULL UNIQUE,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS bot_commands;`,
+			`DROP TABLE IF EXISTS bots;`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "e2ee_keys",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS user_identity_keys (
+				user_id VARCHAR(45) NOT NULL PRIMARY KEY,
+				identity_key VARCHAR(64) NOT NULL,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE TABLE IF NOT EXISTS user_prekeys (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id VARCHAR(45) NOT NULL,
+				key_id VARCHAR(45) NOT NULL,
+				public_key VARCHAR(64) NOT NULL,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, key_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS user_prekeys;`,
+			`DROP TABLE IF EXISTS user_identity_keys;`,
+		},
+	},
+	{
+		Version: 5,
+		Name:    "chatgroup_members_unique",
+		Up: []string{
+			`DELETE FROM chatgroup_members WHERE id NOT IN (
+				SELECT MIN(id) FROM chatgroup_members GROUP BY group_id, member
+			);`,
+			`CREATE UNIQUE INDEX chatgroup_members_member_unique ON chatgroup_members (group_id, member);`,
+		},
+		Down: []string{
+			`DROP INDEX chatgroup_members_member_unique;`,
+		},
+	},
+	{
+		Version: 6,
+		Name:    "user_login_logs_sessions",
+		Up: []string{
+			// sqlite不限制VARCHAR长度，只需索引
+			`CREATE INDEX IF NOT EXISTS user_login_logs_user_id_idx ON user_login_logs (user_id);`,
+		},
+		Down: []string{
+			`DROP INDEX user_login_logs_user_id_idx;`,
+		},
+	},
+	{
+		Version: 7,
+		Name:    "message_receipts",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS message_receipts (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				message_id VARCHAR(45) NOT NULL,
+				room_id VARCHAR(45) DEFAULT '',
+				sender VARCHAR(45) NOT NULL,
+				user_id VARCHAR(45) NOT NULL,
+				status SMALLINT NOT NULL DEFAULT 1,
+				updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (message_id, user_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_receipts;`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "messages",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS messages (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				event_id VARCHAR(45) NOT NULL UNIQUE,
+				type VARCHAR(20) NOT NULL,
+				conversation VARCHAR(100) NOT NULL,
+				room_id VARCHAR(45) DEFAULT '',
+				sender VARCHAR(45) NOT NULL,
+				recipient VARCHAR(100) DEFAULT '',
+				body TEXT NOT NULL,
+				parent_id VARCHAR(45) DEFAULT '',
+				reply_count INTEGER DEFAULT 0,
+				last_reply BIGINT DEFAULT 0,
+				created BIGINT NOT NULL
+			);`,
+			`CREATE INDEX IF NOT EXISTS messages_conversation_idx ON messages (conversation, parent_id, id);`,
+			`CREATE INDEX IF NOT EXISTS messages_parent_id_idx ON messages (parent_id, id);`,
+			`CREATE TABLE IF NOT EXISTS thread_followers (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				parent_id VARCHAR(45) NOT NULL,
+				user_id VARCHAR(45) NOT NULL,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (parent_id, user_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS thread_followers;`,
+			`DROP TABLE IF EXISTS messages;`,
+		},
+	},
+	{
+		Version: 9,
+		Name:    "message_reactions",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS message_reactions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				event_id VARCHAR(45) NOT NULL,
+				user_id VARCHAR(45) NOT NULL,
+				emoji VARCHAR(32) NOT NULL,
+				created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (event_id, user_id, emoji)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_reactions;`,
+		},
+	},
+	{
+		Version: 10,
+		Name:    "mentions",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS mentions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id VARCHAR(45) NOT NULL,
+				event_id VARCHAR(45) NOT NULL,
+				room_id VARCHAR(45) NOT NULL,
+				sender VARCHAR(45) NOT NULL,
+				body TEXT NOT NULL,
+				kind VARCHAR(10) NOT NULL,
+				created BIGINT NOT NULL,
+				is_read BOOLEAN DEFAULT FALSE,
+				UNIQUE (user_id, event_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS mentions;`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "room_pins",
+		Up: []string{
+			`ALTER TABLE chatgroup ADD COLUMN notice VARCHAR(1000) DEFAULT '';`,
+			`ALTER TABLE chatgroup ADD COLUMN notice_by VARCHAR(45) DEFAULT '';`,
+			`ALTER TABLE chatgroup ADD COLUMN notice_updated BIGINT DEFAULT 0;`,
+			`CREATE TABLE IF NOT EXISTS room_pins (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				room_id VARCHAR(45) NOT NULL,
+				event_id VARCHAR(45) NOT NULL,
+				pinned_by VARCHAR(45) NOT NULL,
+				created BIGINT NOT NULL,
+				UNIQUE (room_id, event_id)
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS room_pins;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice_updated;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice_by;`,
+			`ALTER TABLE chatgroup DROP COLUMN notice;`,
+		},
+	},
+	{
+		Version: 12,
+		Name:    "scheduled_messages",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS scheduled_messages (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				event_id VARCHAR(45) NOT NULL UNIQUE,
+				sender VARCHAR(45) NOT NULL,
+				event TEXT NOT NULL,
+				send_at BIGINT NOT NULL,
+				claimed_at BIGINT DEFAULT 0,
+				created BIGINT NOT NULL
+			);`,
+			`CREATE INDEX IF NOT EXISTS scheduled_messages_send_at_idx ON scheduled_messages (send_at);`,
+			`CREATE INDEX IF NOT EXISTS scheduled_messages_sender_idx ON scheduled_messages (sender);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS scheduled_messages;`,
+		},
+	},
+	{
+		Version: 13,
+		Name:    "message_ttl",
+		Up: []string{
+			`ALTER TABLE messages ADD COLUMN expires BIGINT DEFAULT 0;`,
+			`CREATE INDEX IF NOT EXISTS messages_expires_idx ON messages (expires);`,
+			`CREATE TABLE IF NOT EXISTS message_ttl (
+				conversation VARCHAR(100) PRIMARY KEY,
+				ttl BIGINT NOT NULL,
+				updated_by VARCHAR(45) NOT NULL,
+				updated BIGINT NOT NULL
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS message_ttl;`,
+			`DROP INDEX IF EXISTS messages_expires_idx;`,
+			`ALTER TABLE messages DROP COLUMN expires;`,
+		},
+	},
+}
@@ -0,0 +1,79 @@
+package gochat
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Dialect 数据库方言
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// DialectOf 根据驱动名判断方言，未知驱动按mysql处理
+func DialectOf(db *sqlx.DB) Dialect {
+	switch db.DriverName() {
+	case "sqlite", "sqlite3":
+		return SQLite
+	case "postgres", "pgx", "pgx/v5":
+		return Postgres
+	}
+	return MySQL
+}
+
+// insertIgnore 主键或唯一键冲突时忽略
+func (d Dialect) insertIgnore(table string, cols []string) string {
+	values := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	switch d {
+	case MySQL:
+		return "INSERT IGNORE INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + values + ")"
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + values + ") ON CONFLICT DO NOTHING"
+}
+
+// upsert 冲突时更新update中的字段，conflict为冲突的唯一键
+func (d Dialect) upsert(table string, cols, conflict, update []string) string {
+	values := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	q := "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + values + ")"
+	sets := []string{}
+	for _, c := range update {
+		switch d {
+		case MySQL:
+			sets = append(sets, c+" = VALUES("+c+")")
+		default:
+			sets = append(sets, c+" = excluded."+c)
+		}
+	}
+	switch d {
+	case MySQL:
+		return q + " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+	return q + " ON CONFLICT (" + strings.Join(conflict, ", ") + ") DO UPDATE SET " + strings.Join(sets, ", ")
+}
+
+// forUpdate sqlite不支持行锁，事务本身是串行的
+func (d Dialect) forUpdate() string {
+	if d == SQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// insertId 执行insert并返回自增id，postgres不支持LastInsertId
+func insertId(db *sqlx.DB, query string, args ...interface{}) (int64, error) {
+	if DialectOf(db) == Postgres {
+		var id int64
+		err := db.Get(&id, db.Rebind(query+" RETURNING id"), args...)
+		return id, err
+	}
+	result, err := db.Exec(db.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
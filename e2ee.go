@@ -0,0 +1,219 @@
+package gochat
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// 端到端加密消息，服务端只转发不解析、不记录内容
+	EncryptedEvent = "encrypted"
+
+	e2eeVersion = 1
+	e2eeInfo    = "gochat-e2ee-v1"
+)
+
+// 端到端加密只支持每个用户一个设备: 身份公钥及prekey按用户保存，
+// 其他设备上传不同的身份公钥时返回ErrIdentityExists，需设置replace替换，
+// 替换后原设备无法再解密新消息
+var (
+	ErrNoIdentityKey  = errors.New("用户未开启端到端加密")
+	ErrIdentityExists = errors.New("已在其他设备开启端到端加密")
+)
+
+// Envelope 加密消息体，序列化为json后作为Event.Body
+type Envelope struct {
+	Version int `json:"v"`
+	// 私聊: 发送方身份公钥、本次会话临时公钥及使用的prekey
+	Sender    []byte `json:"sender,omitempty"`
+	Ephemeral []byte `json:"ephemeral,omitempty"`
+	PreKeyId  string `json:"prekey_id,omitempty"`
+	// 群聊: 发送方sender key的id
+	SenderKeyId string `json:"sender_key_id,omitempty"`
+	Nonce       []byte `json:"nonce"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// redact 返回可记录日志的事件，加密消息不输出内容
+func redact(event *proto.Event) *proto.Event {
+	if event == nil || event.Type != EncryptedEvent {
+		return event
+	}
+	return &proto.Event{
+		Id:      event.Id,
+		Type:    event.Type,
+		From:    event.From,
+		To:      event.To,
+		Body:    "[encrypted]",
+		Created: event.Created,
+		Parent:  event.Parent,
+	}
+}
+
+type KeyRepository interface {
+	// 设置身份公钥，同时删除旧的prekey
+	SetIdentityKey(uid string, key []byte) error
+	// 追加一次性prekey
+	AddPreKeys(uid string, prekeys []*proto.PreKey) error
+	// 查询身份公钥，未上传时返回ErrNoIdentityKey
+	IdentityKey(uid string) ([]byte, error)
+	// 取出并删除一个prekey，用完时返回nil
+	TakePreKey(uid string) (*proto.PreKey, error)
+	// 剩余prekey数量
+	PreKeyCount(uid string) (int64, error)
+}
+
+func NewKeyRepo(db *sqlx.DB) *keyRepo {
+	return &keyRepo{
+		db: db,
+	}
+}
+
+type keyRepo struct {
+	db *sqlx.DB
+}
+
+func (r *keyRepo) SetIdentityKey(uid string, key []byte) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(tx.Rebind(`DELETE FROM user_identity_keys WHERE user_id = ?`), uid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(tx.Rebind(`
+		INSERT INTO user_identity_keys (user_id, identity_key) VALUES (?, ?)
+		`), uid, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return err
+	}
+	// 更换身份密钥后旧的prekey失效
+	if _, err := tx.Exec(tx.Rebind(`DELETE FROM user_prekeys WHERE user_id = ?`), uid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *keyRepo) AddPreKeys(uid string, prekeys []*proto.PreKey) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, k := range prekeys {
+		if _, err := tx.Exec(tx.Rebind(`
+			INSERT INTO user_prekeys (user_id, key_id, public_key) VALUES (?, ?, ?)
+			`), uid, k.Id, base64.StdEncoding.EncodeToString(k.PublicKey)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *keyRepo) IdentityKey(uid string) ([]byte, error) {
+	var key string
+	if err := r.db.Get(&key, r.db.Rebind(`SELECT identity_key FROM user_identity_keys WHERE user_id = ?`), uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoIdentityKey
+		}
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(key)
+}
+
+func (r *keyRepo) TakePreKey(uid string) (*proto.PreKey, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	row := struct {
+		Id        int64  `db:"id"`
+		KeyId     string `db:"key_id"`
+		PublicKey string `db:"public_key"`
+	}{}
+	if err := tx.Get(&row, tx.Rebind(`
+		SELECT id, key_id, public_key FROM user_prekeys WHERE user_id = ? ORDER BY id LIMIT 1`+DialectOf(r.db).forUpdate()), uid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, err := tx.Exec(tx.Rebind(`DELETE FROM user_prekeys WHERE id = ?`), row.Id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(row.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PreKey{Id: row.KeyId, PublicKey: key}, nil
+}
+
+func (r *keyRepo) PreKeyCount(uid string) (int64, error) {
+	var count int64
+	err := r.db.Get(&count, r.db.Rebind(`SELECT COUNT(*) FROM user_prekeys WHERE user_id = ?`), uid)
+	return count, err
+}
+
+// 以下为客户端使用的密码学工具
+
+// newKeyPair 生成X25519密钥对
+func newKeyPair() (priv, pub []byte, err error) {
+	priv = make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(priv); err != nil {
+		return nil, nil, err
+	}
+	pub, err = curve25519.X25519(priv, curve25519.Basepoint)
+	return priv, pub, err
+}
+
+// deriveKey 将多次DH的结果拼接后经HKDF-SHA256导出32字节对称密钥
+func deriveKey(info []byte, dhs ...[]byte) ([]byte, error) {
+	secret := []byte{}
+	for _, dh := range dhs {
+		secret = append(secret, dh...)
+	}
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// seal 使用XChaCha20-Poly1305加密，ad绑定发送方和接收方防止转投
+func seal(key, plaintext, ad []byte) (nonce, ciphertext []byte, err error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, ad), nil
+}
+
+func unseal(key, nonce, ciphertext, ad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ciphertext, ad)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+
+	// sqlite驱动
+	_ "modernc.org/sqlite"
+)
+
+// 单进程运行聊天服务，不需要registry、MySQL及nats-streaming
+// go run main.go -users alice,bob 后使用examples/cli连接
+func main() {
+	addr := flag.String("addr", ":8082", "The address to listen on")
+	dbFile := flag.String("db", "", "The sqlite file, empty to keep data in memory")
+	users := flag.String("users", "", "Comma separated users to register on start")
+	proxies := flag.String("trusted_proxies", "", "Comma separated proxy CIDRs allowed to set X-Forwarded-For")
+	flag.Parse()
+
+	if len(*proxies) > 0 {
+		if err := gochat.TrustProxies(strings.Split(*proxies, ",")...); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	logger := gochat.NewLogger(os.Stderr, gochat.LevelInfo)
+	opts := []gochat.ServerOption{
+		gochat.ServerLogger(logger),
+		gochat.ServerHandlerOptions(gochat.WithRateLimiter(gochat.NewRateLimiter(gochat.DefaultLimits))),
+	}
+	if len(*dbFile) > 0 {
+		db, err := sqlx.Connect("sqlite", *dbFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// sqlite只允许一个写连接
+		db.SetMaxOpenConns(1)
+		defer db.Close()
+		opts = append(opts, gochat.ServerDB(db))
+	}
+
+	srv, err := gochat.NewServer(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer srv.Close()
+
+	for _, id := range strings.Split(*users, ",") {
+		if len(id) == 0 {
+			continue
+		}
+		if _, err := srv.Client().Register(context.Background(), &proto.RegisterRequest{
+			User: &proto.User{Id: id, Name: id},
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	http.Handle("/chat/", http.StripPrefix("/chat", srv))
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
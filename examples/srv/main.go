@@ -1,7 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"os"
 
 	gochat "github.com/laoqiu/go-chat"
 	proto "github.com/laoqiu/go-chat/proto"
@@ -10,6 +14,10 @@ import (
 	"github.com/micro/cli"
 	micro "github.com/micro/go-micro"
 	"github.com/micro/go-micro/broker"
+
+	// sqlite及postgres驱动，mysql由sqlxt导入
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
 func main() {
@@ -17,12 +25,22 @@ func main() {
 
 	dbOpts := []sqlxt.Option{}
 	brokerOpts := []broker.Option{}
+	limits := gochat.DefaultLimits.Copy()
+	filterConfig := ""
+	logger := gochat.NewLogger(os.Stderr, gochat.LevelInfo)
+	metricsAddress := ""
+	var migrateArgs []string
 
 	// create a service
 	service := micro.NewService(
 		micro.Name("go.micro.srv.chat"),
 		micro.Version("0.2"),
 		micro.Flags(
+			cli.StringFlag{
+				Name:   "database_driver",
+				EnvVar: "DATABASE_DRIVER",
+				Usage:  "The database driver: mysql, sqlite or pgx",
+			},
 			cli.StringFlag{
 				Name:   "database_url",
 				EnvVar: "DATABASE_URL",
@@ -38,11 +56,59 @@ func main() {
 				EnvVar: "NATS_ADDRESS",
 				Usage:  "The nats streaming address",
 			},
+			cli.Float64Flag{
+				Name:   "rate_limit_user",
+				EnvVar: "RATE_LIMIT_USER",
+				Usage:  "Messages per second allowed for each user",
+			},
+			cli.IntFlag{
+				Name:   "rate_limit_user_burst",
+				EnvVar: "RATE_LIMIT_USER_BURST",
+				Usage:  "Messages a user may send in a burst, defaults to twice the rate",
+			},
+			cli.Float64Flag{
+				Name:   "rate_limit_room",
+				EnvVar: "RATE_LIMIT_ROOM",
+				Usage:  "Messages per second allowed for each room",
+			},
+			cli.IntFlag{
+				Name:   "rate_limit_room_burst",
+				EnvVar: "RATE_LIMIT_ROOM_BURST",
+				Usage:  "Messages a room may receive in a burst, defaults to twice the rate",
+			},
+			cli.StringFlag{
+				Name:   "rate_limit_events",
+				EnvVar: "RATE_LIMIT_EVENTS",
+				Usage:  "Per event type limits as type=rate:burst e.g candidate=50:100,receipt=20:50",
+			},
+			cli.StringFlag{
+				Name:   "filter_config",
+				EnvVar: "FILTER_CONFIG",
+				Usage:  "The content filter config file e.g filters.json",
+			},
+			cli.StringFlag{
+				Name:   "log_level",
+				EnvVar: "LOG_LEVEL",
+				Usage:  "The log level: debug, info, warn or error",
+			},
+			cli.BoolFlag{
+				Name:   "log_bodies",
+				EnvVar: "LOG_BODIES",
+				Usage:  "Log message bodies at debug level, encrypted messages are never logged",
+			},
+			cli.StringFlag{
+				Name:   "metrics_address",
+				EnvVar: "METRICS_ADDRESS",
+				Usage:  "The address to expose /metrics on e.g :9100",
+			},
 		),
 		micro.Action(func(c *cli.Context) {
 			if len(c.String("server_name")) > 0 {
 				serviceName = c.String("server_name")
 			}
+			if len(c.String("database_driver")) > 0 {
+				dbOpts = append(dbOpts, sqlxt.Driver(c.String("database_driver")))
+			}
 			if len(c.String("database_url")) > 0 {
 				dbOpts = append(dbOpts, sqlxt.URI(c.String("database_url")))
 			}
@@ -52,12 +118,64 @@ func main() {
 			if len(c.String("nats_client_id")) > 0 {
 				brokerOpts = append(brokerOpts, stan.ClientID(c.String("nats_client_id")))
 			}
+			if rate := c.Float64("rate_limit_user"); rate > 0 {
+				limits.User = gochat.Limit{Rate: rate, Burst: int(rate*2) + 1}
+			}
+			if rate := c.Float64("rate_limit_room"); rate > 0 {
+				limits.Room = gochat.Limit{Rate: rate, Burst: int(rate*2) + 1}
+			}
+			if burst := c.Int("rate_limit_user_burst"); burst > 0 {
+				limits.User.Burst = burst
+			}
+			if burst := c.Int("rate_limit_room_burst"); burst > 0 {
+				limits.Room.Burst = burst
+			}
+			if len(c.String("rate_limit_events")) > 0 {
+				events, err := gochat.ParseEventLimits(c.String("rate_limit_events"))
+				if err != nil {
+					log.Fatal(err)
+				}
+				for eventType, l := range events {
+					limits.Event[eventType] = l
+				}
+			}
+			filterConfig = c.String("filter_config")
+			level, err := gochat.ParseLevel(c.String("log_level"))
+			if err != nil {
+				log.Fatal(err)
+			}
+			logger.SetLevel(level)
+			logger.LogBodies(c.Bool("log_bodies"))
+			metricsAddress = c.String("metrics_address")
+			// 子命令: migrate [up|down [n]|status]
+			if c.Args().First() == "migrate" {
+				migrateArgs = append([]string{}, c.Args().Tail()...)
+			}
 		}),
 	)
 
 	// parse command line
 	service.Init()
 
+	if migrateArgs != nil {
+		if err := migrate(dbOpts, migrateArgs, logger); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// 指标
+	metrics := gochat.NewMetrics(nil)
+	if len(metricsAddress) > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", gochat.MetricsHandler())
+		go func() {
+			if err := http.ListenAndServe(metricsAddress, mux); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	// broker connect
 	sbroker := stan.NewBroker(brokerOpts...)
 	if err := sbroker.Connect(); err != nil {
@@ -69,17 +187,70 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	repo := gochat.NewChatRepo(conn)
+	repo := gochat.NewChatRepo(conn, logger)
 
 	// Hub
-	hub := gochat.NewHub(serviceName, sbroker)
+	hub := gochat.NewHub(serviceName, sbroker, logger)
 	sub, err := hub.Subscribe()
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer sub.Unsubscribe()
 
-	proto.RegisterChatHandler(service.Server(), gochat.NewHandler(serviceName, repo, hub, sbroker))
+	// 内容过滤
+	filterCfg := gochat.FiltersConfig{}
+	if len(filterConfig) > 0 {
+		b, err := ioutil.ReadFile(filterConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &filterCfg); err != nil {
+			log.Fatal(err)
+		}
+	}
+	filters, err := gochat.NewFilters(filterCfg, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// webhook
+	webhooks := gochat.NewWebhooks(gochat.NewWebhookRepo(conn), gochat.WebhookLogger(logger))
+	defer webhooks.Close()
+
+	handler := gochat.NewHandler(serviceName, repo, hub, sbroker,
+		gochat.WithWebhooks(webhooks),
+		gochat.WithBots(gochat.NewBotRepo(conn)),
+		gochat.WithRateLimiter(gochat.NewRateLimiter(limits)),
+		gochat.WithFilters(filters),
+		gochat.WithKeys(gochat.NewKeyRepo(conn)),
+		gochat.WithReceipts(gochat.NewReceiptRepo(conn)),
+		gochat.WithHistory(gochat.NewHistoryRepo(conn)),
+		gochat.WithMentions(gochat.NewMentionRepo(conn)),
+		gochat.WithSchedules(gochat.NewScheduleRepo(conn)),
+		gochat.WithLogger(logger),
+		gochat.WithMetrics(metrics),
+		// 强制下线需要所有节点确认
+		gochat.WithNodes(func() (int, error) {
+			services, err := service.Options().Registry.GetService(service.Server().Options().Name)
+			if err != nil {
+				return 0, err
+			}
+			n := 0
+			for _, s := range services {
+				n += len(s.Nodes)
+			}
+			return n, nil
+		}),
+	)
+	proto.RegisterChatHandler(service.Server(), handler)
+
+	// 定时消息，多节点同时运行时每条消息只发送一次
+	scheduler := gochat.NewScheduler(handler)
+	defer scheduler.Close()
+
+	// 清理过期消息
+	sweeper := gochat.NewSweeper(handler)
+	defer sweeper.Close()
 
 	if err := service.Run(); err != nil {
 		log.Fatal(err)
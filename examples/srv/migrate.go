@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	gochat "github.com/laoqiu/go-chat"
+	"github.com/laoqiu/sqlxt"
+)
+
+// migrate 执行数据库迁移子命令: up执行所有未执行的迁移(默认)，
+// down [n]回滚最近n个迁移(默认1个)，status查看迁移状态
+func migrate(dbOpts []sqlxt.Option, args []string, logger gochat.Logger) error {
+	conn, err := gochat.Connect(dbOpts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	m := gochat.NewMigrator(conn, gochat.Migrations[gochat.DialectOf(conn)], logger)
+
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	switch cmd {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New("invalid steps: " + args[1])
+			}
+		}
+		return m.Down(ctx, steps)
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.Applied {
+				applied = s.Created.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-30s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	}
+	return errors.New("unknown migrate command: " + cmd)
+}
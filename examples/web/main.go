@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"net/http"
+	"os"
 	"regexp"
 
 	gochat "github.com/laoqiu/go-chat"
@@ -33,9 +35,57 @@ func main() {
 		log.Fatal("请在config.json中配置正确的认证地址")
 	}
 
+	// 日志
+	level, err := gochat.ParseLevel(config.Get("log_level").String("info"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger := gochat.NewLogger(os.Stderr, level)
+
+	// 管理接口只监听在内部地址，config.json中配置 "admin_address": "127.0.0.1:9101"
+	if addr := config.Get("admin_address").String(""); len(addr) > 0 {
+		admin := http.NewServeMux()
+		admin.HandleFunc("/debug/loglevel", gochat.NewLogLevelHandler(logger))
+		go func() {
+			if err := http.ListenAndServe(addr, admin); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	// 网关在反向代理之后时，config.json中配置 "trusted_proxies": ["10.0.0.0/8"]
+	// 只有来自这些地址的请求才读取X-Real-IP及X-Forwarded-For
+	if err := gochat.TrustProxies(config.Get("trusted_proxies").StringSlice(nil)...); err != nil {
+		log.Fatal(err)
+	}
+
+	// 指标
+	metrics := gochat.NewMetrics(nil)
+	service.Handle("/metrics", gochat.MetricsHandler())
+
 	// register chat handler
 	cli := proto.NewChatService("go.micro.srv.chat", client.DefaultClient)
-	service.HandleFunc("/stream", gochat.NewWebsocketHandler(cli))
+	// config.json中配置 "ws_compression": 压缩级别(1-9)，启用permessage-deflate
+	wsOpts := []gochat.WebsocketOption{}
+	if level := config.Get("ws_compression").Int(0); level > 0 {
+		wsOpts = append(wsOpts, gochat.WebsocketCompression(level))
+	}
+	service.HandleFunc("/stream", gochat.NewWebsocketHandler(cli, logger, metrics, wsOpts...))
+	// 代理不支持websocket时使用SSE或长轮询接收，http发送
+	service.HandleFunc("/sse", gochat.NewSSEHandler(cli, logger, metrics))
+	service.HandleFunc("/poll", gochat.NewPollHandler(cli, logger, metrics))
+	service.HandleFunc("/send", gochat.NewSendHandler(cli, logger))
+	service.HandleFunc("/bot/send", gochat.NewBotHandler(cli))
+
+	// REST接口，config.json中配置 "api_tokens": {"名称": "token"}
+	tokens := config.Get("api_tokens").StringMap(map[string]string{})
+	if len(tokens) > 0 {
+		apiOpts := []gochat.APIOption{gochat.APILogger(logger)}
+		for name, token := range tokens {
+			apiOpts = append(apiOpts, gochat.APIToken(name, token))
+		}
+		service.Handle(gochat.APIPrefix+"/", gochat.NewAPIHandler(cli, apiOpts...))
+	}
 
 	// run service
 	if err := service.Run(); err != nil {
@@ -0,0 +1,277 @@
+package gochat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+const (
+	// 消息已过期，客户端删除本地副本，Body为消息id，多条以逗号分隔
+	ExpireEvent = "expire"
+	// 会话的消息有效期变更，Body为秒数，0为关闭
+	TTLEvent = "ttl"
+)
+
+var (
+	ErrTTL = errors.New("无效的消息有效期")
+
+	// 消息有效期上限(秒)
+	MaxTTL int64 = 365 * 24 * 3600
+)
+
+func (r *historyRepo) TTL(conversation string) (int64, error) {
+	var ttl int64
+	err := r.db.Get(&ttl, r.db.Rebind(`SELECT ttl FROM message_ttl WHERE conversation = ?`), conversation)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return ttl, err
+}
+
+func (r *historyRepo) SetTTL(conversation string, ttl int64, uid string) error {
+	if ttl == 0 {
+		_, err := r.db.Exec(r.db.Rebind(`DELETE FROM message_ttl WHERE conversation = ?`), conversation)
+		return err
+	}
+	_, err := r.db.Exec(r.db.Rebind(r.dialect.upsert("message_ttl",
+		[]string{"conversation", "ttl", "updated_by", "updated"}, []string{"conversation"}, []string{"ttl", "updated_by", "updated"},
+	)), conversation, ttl, uid, time.Now().Unix())
+	return err
+}
+
+func (r *historyRepo) Expired(now int64, limit int) ([]*Message, error) {
+	msgs := []*Message{}
+	err := r.db.Select(&msgs, r.db.Rebind(`
+		SELECT `+messageColumns+` FROM messages WHERE expires > 0 AND expires <= ? ORDER BY expires, id LIMIT ?
+		`), now, limit)
+	return msgs, err
+}
+
+func (r *historyRepo) Expire(eventId string) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// 多个节点同时清理时只有删除成功的节点继续
+	result, err := tx.Exec(tx.Rebind(`DELETE FROM messages WHERE event_id = ?`), eventId)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	for _, q := range []string{
+		`DELETE FROM message_reactions WHERE event_id = ?`,
+		`DELETE FROM room_pins WHERE event_id = ?`,
+		`DELETE FROM thread_followers WHERE parent_id = ?`,
+	} {
+		if _, err := tx.Exec(tx.Rebind(q), eventId); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// MessageTTL 会话的消息有效期
+func (h *Handler) MessageTTL(ctx context.Context, req *proto.MessageTTLRequest, rsp *proto.MessageTTLResponse) error {
+	roomId, to, err := h.ttlConversation(req.Id, req.To, false)
+	if err != nil {
+		return err
+	}
+	rsp.Ttl, err = h.opts.History.TTL(conversation(roomId, req.Id, to))
+	return err
+}
+
+// SetMessageTTL 设置会话的消息有效期，房间只有管理员可以设置，私聊双方均可设置
+// 只对之后发送的消息生效
+func (h *Handler) SetMessageTTL(ctx context.Context, req *proto.SetMessageTTLRequest, rsp *proto.SetMessageTTLResponse) error {
+	if req.Ttl < 0 || req.Ttl > MaxTTL {
+		return ErrTTL
+	}
+	roomId, to, err := h.ttlConversation(req.Id, req.To, true)
+	if err != nil {
+		return err
+	}
+	if err := h.opts.History.SetTTL(conversation(roomId, req.Id, to), req.Ttl, req.Id); err != nil {
+		return err
+	}
+
+	e := &proto.Event{Type: TTLEvent, From: req.Id, Body: strconv.FormatInt(req.Ttl, 10)}
+	if len(roomId) > 0 {
+		h.roomEvent(ctx, roomId, e)
+		return nil
+	}
+	e.Id = newEventId()
+	e.To = to
+	e.Created = time.Now().Unix()
+	event, _ := json.Marshal(e)
+	for _, uid := range []string{req.Id, to} {
+		if err := h.publish(ctx, h.service+"."+uid, event); err != nil {
+			h.opts.Logger.Warn("ttl publish failed", UserField(uid), ErrField(err))
+		}
+	}
+	return nil
+}
+
+// ttlConversation 检查uid能否查看或修改会话的有效期
+func (h *Handler) ttlConversation(uid, dest string, manage bool) (string, string, error) {
+	if h.opts.History == nil {
+		return "", "", errors.New("消息历史未启用")
+	}
+	if len(uid) == 0 {
+		return "", "", errors.New("id is required")
+	}
+	roomId, to := splitDest(dest)
+	if len(roomId) > 0 {
+		if manage {
+			return roomId, "", h.manager(uid, roomId)
+		}
+		return roomId, "", h.member(uid, roomId)
+	}
+	if len(to) == 0 {
+		return "", "", errors.New("to is required")
+	}
+	if _, err := h.repo.GetUser(to); err != nil {
+		return "", "", err
+	}
+	return "", to, nil
+}
+
+// expires 按会话的有效期设置消息的过期时间，客户端不能自行设置
+func (h *Handler) expires(event *proto.Event, roomId, to string) error {
+	event.Expires = 0
+	if h.opts.History == nil || !in(historyEvents, event.Type) {
+		return nil
+	}
+	ttl, err := h.opts.History.TTL(conversation(roomId, event.From, to))
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		event.Expires = event.Created + ttl
+	}
+	return nil
+}
+
+type SweeperOption func(*Sweeper)
+
+// SweeperInterval 清理过期消息的间隔，默认10秒
+func SweeperInterval(d time.Duration) SweeperOption {
+	return func(s *Sweeper) {
+		s.interval = d
+	}
+}
+
+// Sweeper 从历史中删除过期的消息并通知会话成员，多个节点同时运行时每条消息只通知一次
+// nats-streaming不支持删除单条消息，队列中的过期消息由Conn在推送时丢弃
+type Sweeper struct {
+	h        *Handler
+	interval time.Duration
+	batch    int
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewSweeper 启动Sweeper，Handler未启用消息历史时不执行
+func NewSweeper(h *Handler, opts ...SweeperOption) *Sweeper {
+	s := &Sweeper{
+		h:        h,
+		interval: 10 * time.Second,
+		batch:    100,
+		done:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if h.opts.History != nil {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return s
+}
+
+func (s *Sweeper) Close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+}
+
+func (s *Sweeper) run() {
+	defer s.wg.Done()
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			s.sweep()
+		}
+	}
+}
+
+// sweep 删除一批过期消息，按会话合并通知
+func (s *Sweeper) sweep() {
+	h := s.h
+	msgs, err := h.opts.History.Expired(time.Now().Unix(), s.batch)
+	if err != nil {
+		h.opts.Logger.Warn("expired query failed", ErrField(err))
+		return
+	}
+	expired := make(map[string][]*Message)
+	order := []string{}
+	for _, m := range msgs {
+		ok, err := h.opts.History.Expire(m.EventId)
+		if err != nil {
+			h.opts.Logger.Warn("expire failed", EventIdField(m.EventId), ErrField(err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if h.opts.Mentions != nil {
+			if err := h.opts.Mentions.DeleteMentions(m.EventId); err != nil {
+				h.opts.Logger.Warn("mentions delete failed", EventIdField(m.EventId), ErrField(err))
+			}
+		}
+		if _, ok := expired[m.Conversation]; !ok {
+			order = append(order, m.Conversation)
+		}
+		expired[m.Conversation] = append(expired[m.Conversation], m)
+	}
+
+	ctx := context.Background()
+	for _, c := range order {
+		ids := []string{}
+		for _, m := range expired[c] {
+			ids = append(ids, m.EventId)
+		}
+		e := &proto.Event{Type: ExpireEvent, Body: strings.Join(ids, ",")}
+		first := expired[c][0]
+		if len(first.RoomId) > 0 {
+			h.roomEvent(ctx, first.RoomId, e)
+			continue
+		}
+		e.Id = newEventId()
+		e.Created = time.Now().Unix()
+		for _, uid := range []string{first.Sender, first.Recipient} {
+			e.To = uid
+			event, _ := json.Marshal(e)
+			if err := h.publish(ctx, h.service+"."+uid, event); err != nil {
+				h.opts.Logger.Warn("expire publish failed", UserField(uid), ErrField(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package gochat
+
+import (
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// 供gochat_test测试未导出的函数
+var ClientIP = clientIP
+
+var IsRedelivered = isRedelivered
+
+// SealTo 与对方建立会话并加密私聊消息
+func (ks *KeyStore) SealTo(bundle *proto.KeyBundle, from, to string, pt []byte) (*Envelope, error) {
+	s := ks.session(bundle.UserId)
+	if s == nil {
+		var err error
+		if s, err = ks.newSession(bundle); err != nil {
+			return nil, err
+		}
+	}
+	return ks.sealPairwise(s, from, to, pt)
+}
+
+// Open 解密私聊消息
+func (ks *KeyStore) Open(env *Envelope, from, to string) ([]byte, error) {
+	return ks.openPairwise(env, from, to)
+}
@@ -0,0 +1,289 @@
+package gochat
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var ErrFilterAction = errors.New("未知的过滤动作")
+
+// 过滤命中后的处理方式
+const (
+	FilterReject = "reject" // 拒绝发送
+	FilterMask   = "mask"   // 替换命中内容后发送
+	FilterFlag   = "flag"   // 正常发送，同时提交管理后台审核
+)
+
+// Verdict 单个过滤器的结果，Action为空表示未命中
+type Verdict struct {
+	Action string
+	Reason string
+}
+
+// Filter 消息内容过滤器，mask时直接修改event.Body
+type Filter interface {
+	Filter(event *proto.Event) Verdict
+}
+
+// FilterResult 过滤链的汇总结果
+type FilterResult struct {
+	Rejected bool
+	Reason   string
+	Flags    []string
+}
+
+// FilterChain 按顺序执行过滤器，遇到reject立即停止
+type FilterChain []Filter
+
+func (c FilterChain) Run(event *proto.Event) *FilterResult {
+	result := &FilterResult{}
+	for _, f := range c {
+		v := f.Filter(event)
+		switch v.Action {
+		case FilterReject:
+			result.Rejected = true
+			result.Reason = v.Reason
+			return result
+		case FilterFlag:
+			result.Flags = append(result.Flags, v.Reason)
+		}
+	}
+	return result
+}
+
+// Filters 按房间配置的过滤链，未单独配置的房间及私聊使用Default
+type Filters struct {
+	Default FilterChain
+	Rooms   map[string]FilterChain
+}
+
+func (f *Filters) Chain(roomId string) FilterChain {
+	if c, ok := f.Rooms[roomId]; ok {
+		return c
+	}
+	return f.Default
+}
+
+// KeywordFilter 关键字及正则黑名单
+type KeywordFilter struct {
+	Action   string
+	Keywords []string
+	Patterns []*regexp.Regexp
+}
+
+func (f *KeywordFilter) Filter(event *proto.Event) Verdict {
+	hit := ""
+	for _, k := range f.Keywords {
+		if len(k) > 0 && strings.Contains(event.Body, k) {
+			hit = k
+			if f.Action == FilterMask {
+				event.Body = strings.Replace(event.Body, k, mask(k), -1)
+			}
+		}
+	}
+	for _, p := range f.Patterns {
+		if p.MatchString(event.Body) {
+			hit = p.String()
+			if f.Action == FilterMask {
+				event.Body = p.ReplaceAllStringFunc(event.Body, mask)
+			}
+		}
+	}
+	if len(hit) == 0 {
+		return Verdict{}
+	}
+	return Verdict{Action: f.Action, Reason: "keyword: " + hit}
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s]+`)
+
+// LinkFilter 链接域名白名单/黑名单，Allow不为空时只允许白名单内的域名
+type LinkFilter struct {
+	Action string
+	Allow  []string
+	Deny   []string
+}
+
+func (f *LinkFilter) Filter(event *proto.Event) Verdict {
+	hit := ""
+	event.Body = linkPattern.ReplaceAllStringFunc(event.Body, func(link string) string {
+		u, err := url.Parse(link)
+		if err != nil || f.permit(u.Hostname()) {
+			return link
+		}
+		hit = link
+		if f.Action == FilterMask {
+			return mask(link)
+		}
+		return link
+	})
+	if len(hit) == 0 {
+		return Verdict{}
+	}
+	return Verdict{Action: f.Action, Reason: "link: " + hit}
+}
+
+func (f *LinkFilter) permit(host string) bool {
+	host = strings.ToLower(host)
+	for _, d := range f.Deny {
+		if matchDomain(host, d) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, d := range f.Allow {
+		if matchDomain(host, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDomain 域名或其子域名
+func matchDomain(host, domain string) bool {
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// LengthFilter 消息长度上限(字符数)，mask时截断
+type LengthFilter struct {
+	Action string
+	Max    int
+}
+
+func (f *LengthFilter) Filter(event *proto.Event) Verdict {
+	n := utf8.RuneCountInString(event.Body)
+	if f.Max <= 0 || n <= f.Max {
+		return Verdict{}
+	}
+	if f.Action == FilterMask {
+		event.Body = string([]rune(event.Body)[:f.Max])
+	}
+	return Verdict{Action: f.Action, Reason: fmt.Sprintf("length: %d > %d", n, f.Max)}
+}
+
+// SpamFilter 外部垃圾消息评分，分数不低于Threshold时命中
+type SpamFilter struct {
+	Action    string
+	Threshold float64
+	Score     func(event *proto.Event) float64
+}
+
+func (f *SpamFilter) Filter(event *proto.Event) Verdict {
+	if f.Score == nil {
+		return Verdict{}
+	}
+	score := f.Score(event)
+	if score < f.Threshold {
+		return Verdict{}
+	}
+	if f.Action == FilterMask {
+		event.Body = mask(event.Body)
+	}
+	return Verdict{Action: f.Action, Reason: fmt.Sprintf("spam: %.2f", score)}
+}
+
+func mask(s string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(s))
+}
+
+// FilterConfig 单个过滤链的配置，可从json加载
+type FilterConfig struct {
+	Keywords      []string `json:"keywords"`
+	Patterns      []string `json:"patterns"`
+	KeywordAction string   `json:"keyword_action"`
+	AllowDomains  []string `json:"allow_domains"`
+	DenyDomains   []string `json:"deny_domains"`
+	LinkAction    string   `json:"link_action"`
+	MaxLength     int      `json:"max_length"`
+	LengthAction  string   `json:"length_action"`
+}
+
+// FiltersConfig 默认配置及按房间覆盖的配置
+type FiltersConfig struct {
+	Default FilterConfig            `json:"default"`
+	Rooms   map[string]FilterConfig `json:"rooms"`
+}
+
+// NewFilters 根据配置创建过滤链，spam不为空时追加到每个过滤链末尾
+func NewFilters(cfg FiltersConfig, spam *SpamFilter) (*Filters, error) {
+	if spam != nil {
+		a, err := action(spam.Action)
+		if err != nil {
+			return nil, err
+		}
+		spam.Action = a
+	}
+	def, err := cfg.Default.chain(spam)
+	if err != nil {
+		return nil, err
+	}
+	f := &Filters{
+		Default: def,
+		Rooms:   make(map[string]FilterChain),
+	}
+	for roomId, c := range cfg.Rooms {
+		chain, err := c.chain(spam)
+		if err != nil {
+			return nil, err
+		}
+		f.Rooms[roomId] = chain
+	}
+	return f, nil
+}
+
+func (c FilterConfig) chain(spam *SpamFilter) (FilterChain, error) {
+	chain := FilterChain{}
+	if c.MaxLength > 0 {
+		a, err := action(c.LengthAction)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, &LengthFilter{Action: a, Max: c.MaxLength})
+	}
+	if len(c.Keywords) > 0 || len(c.Patterns) > 0 {
+		a, err := action(c.KeywordAction)
+		if err != nil {
+			return nil, err
+		}
+		f := &KeywordFilter{Action: a, Keywords: c.Keywords}
+		for _, p := range c.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, err
+			}
+			f.Patterns = append(f.Patterns, re)
+		}
+		chain = append(chain, f)
+	}
+	if len(c.AllowDomains) > 0 || len(c.DenyDomains) > 0 {
+		a, err := action(c.LinkAction)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, &LinkFilter{Action: a, Allow: c.AllowDomains, Deny: c.DenyDomains})
+	}
+	if spam != nil {
+		chain = append(chain, spam)
+	}
+	return chain, nil
+}
+
+// action 未配置时默认拒绝，未知的动作返回错误，避免被当作未命中放行
+func action(a string) (string, error) {
+	switch a {
+	case "":
+		return FilterReject, nil
+	case FilterReject, FilterMask, FilterFlag:
+		return a, nil
+	}
+	return "", fmt.Errorf("%v: %q", ErrFilterAction, a)
+}
@@ -0,0 +1,38 @@
+package gochat_test
+
+import (
+	"strings"
+	"testing"
+
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+func TestFilterUnknownAction(t *testing.T) {
+	cfgs := []gochat.FiltersConfig{
+		{Default: gochat.FilterConfig{Keywords: []string{"spam"}, KeywordAction: "block"}},
+		{Default: gochat.FilterConfig{MaxLength: 10, LengthAction: "drop"}},
+		{Rooms: map[string]gochat.FilterConfig{"r1": {DenyDomains: []string{"a.com"}, LinkAction: "Reject"}}},
+	}
+	for _, cfg := range cfgs {
+		if _, err := gochat.NewFilters(cfg, nil); err == nil || !strings.Contains(err.Error(), gochat.ErrFilterAction.Error()) {
+			t.Fatalf("NewFilters(%+v) err = %v, want %v", cfg, err, gochat.ErrFilterAction)
+		}
+	}
+	spam := &gochat.SpamFilter{Action: "block"}
+	if _, err := gochat.NewFilters(gochat.FiltersConfig{}, spam); err == nil {
+		t.Fatal("NewFilters: unknown spam action accepted")
+	}
+}
+
+func TestFilterDefaultAction(t *testing.T) {
+	f, err := gochat.NewFilters(gochat.FiltersConfig{
+		Default: gochat.FilterConfig{Keywords: []string{"spam"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := f.Default.Run(&proto.Event{Type: "message", Body: "buy spam"}); !result.Rejected {
+		t.Fatalf("result = %+v, want rejected", result)
+	}
+}
@@ -0,0 +1,87 @@
+package gochat
+
+import (
+	"bytes"
+	"encoding/json"
+
+	pb "github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// websocket子协议，客户端通过Sec-WebSocket-Protocol协商
+// gochat.json 为文本帧json，gochat.proto 为二进制帧protobuf编码的proto.Event
+// 未协商子协议时使用json，兼容旧客户端
+// 两种格式只是外层Event的编码不同，Body的内容(如history返回的json)不变
+const (
+	JSONSubprotocol  = "gochat.json"
+	ProtoSubprotocol = "gochat.proto"
+)
+
+// 客户端同时支持时优先使用protobuf
+var Subprotocols = []string{ProtoSubprotocol, JSONSubprotocol}
+
+// 小于此大小的帧不压缩，压缩收益低于开销
+const minCompressSize = 256
+
+// frameCodec websocket帧与事件的转换
+type frameCodec interface {
+	marshal(event *proto.Event) (int, []byte, error)
+	unmarshal(data []byte, event *proto.Event) error
+}
+
+// codecFor 按协商的子协议选择编码
+func codecFor(subprotocol string) frameCodec {
+	if subprotocol == ProtoSubprotocol {
+		return protoCodec{}
+	}
+	return jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) marshal(event *proto.Event) (int, []byte, error) {
+	b, err := json.Marshal(event)
+	return websocket.TextMessage, b, err
+}
+
+func (jsonCodec) unmarshal(data []byte, event *proto.Event) error {
+	data = bytes.TrimSpace(bytes.Replace(data, newline, space, -1))
+	return json.Unmarshal(data, event)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) marshal(event *proto.Event) (int, []byte, error) {
+	b, err := pb.Marshal(event)
+	return websocket.BinaryMessage, b, err
+}
+
+func (protoCodec) unmarshal(data []byte, event *proto.Event) error {
+	return pb.Unmarshal(data, event)
+}
+
+type WebsocketOption func(*websocketOptions)
+
+type websocketOptions struct {
+	compression bool
+	level       int
+}
+
+// WebsocketCompression 启用permessage-deflate，客户端支持时生效
+// level为flate压缩级别，1(最快)至9(最小)
+func WebsocketCompression(level int) WebsocketOption {
+	return func(o *websocketOptions) {
+		o.compression = true
+		o.level = level
+	}
+}
+
+// newUpgrader 按选项生成upgrader，默认不压缩
+func newUpgrader(o websocketOptions) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		CheckOrigin:       upgrader.CheckOrigin,
+		Subprotocols:      Subprotocols,
+		EnableCompression: o.compression,
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -13,6 +11,9 @@ import (
 	"github.com/micro/go-micro/broker"
 	"github.com/nats-io/nats-streaming-server/server"
 	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Handler struct {
@@ -20,14 +21,16 @@ type Handler struct {
 	repo    Repository
 	hub     *Hub
 	broker  broker.Broker
+	opts    Options
 }
 
-func NewHandler(service string, repo Repository, hub *Hub, broker broker.Broker) *Handler {
+func NewHandler(service string, repo Repository, hub *Hub, broker broker.Broker, opts ...Option) *Handler {
 	return &Handler{
 		service: service,
 		repo:    repo,
 		hub:     hub,
 		broker:  broker,
+		opts:    newOptions(opts...),
 	}
 }
 
@@ -38,7 +41,8 @@ func (h *Handler) Register(ctx context.Context, req *proto.RegisterRequest, rsp
 	}
 
 	// 注册队列: 如果不注册将无法收到执久化消息
-	conn := NewConn(h.service, req.User.Id, MasterPlatform, 0, nil)
+	conn := NewConn(h.service, req.User.Id, MasterPlatform, 0, nil, h.opts.Logger)
+	conn.newBroker = h.opts.ConnBroker
 	if err := conn.Init(); err != nil {
 		return err
 	}
@@ -57,15 +61,7 @@ func (h *Handler) Unregister(ctx context.Context, req *proto.UnregisterRequest,
 	// }
 
 	// 通知所有平台下线
-	body, _ := json.Marshal(map[string]interface{}{
-		"id":       req.Id,
-		"platform": "all",
-	})
-	// 发送强制下线消息给所有srv
-	if err := h.broker.Publish(h.service, &broker.Message{
-		Body: body,
-	}); err != nil {
-		fmt.Println("stream publish err", err)
+	if err := h.kick(req.Id, AllPlatforms, KickAccountDeleted); err != nil {
 		return err
 	}
 
@@ -75,7 +71,8 @@ func (h *Handler) Unregister(ctx context.Context, req *proto.UnregisterRequest,
 	}
 
 	// 连上队列后执行退订
-	conn := NewConn(h.service, req.Id, MasterPlatform, 0, nil)
+	conn := NewConn(h.service, req.Id, MasterPlatform, 0, nil, h.opts.Logger)
+	conn.newBroker = h.opts.ConnBroker
 	if err := conn.Init(); err != nil {
 		return err
 	}
@@ -108,6 +105,34 @@ func (h *Handler) Rooms(ctx context.Context, req *proto.RoomsRequest, rsp *proto
 	return nil
 }
 
+// Members 房间成员，房间不存在时返回ErrNotFound
+func (h *Handler) Members(ctx context.Context, req *proto.MembersRequest, rsp *proto.MembersResponse) error {
+	if _, err := h.repo.GetRoom(req.RoomId); err != nil {
+		return err
+	}
+	users, err := h.repo.Members(req.RoomId, req.Managers)
+	if err != nil {
+		return err
+	}
+	rsp.Users = users
+	return nil
+}
+
+// Presence 用户是否在线，一次最多查询MaxPresence个
+func (h *Handler) Presence(ctx context.Context, req *proto.PresenceRequest, rsp *proto.PresenceResponse) error {
+	if len(req.Ids) > MaxPresence {
+		return errors.New("too many ids")
+	}
+	online, err := h.repo.OnlineUsers(req.Ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range req.Ids {
+		rsp.Presences = append(rsp.Presences, &proto.Presence{UserId: id, Online: in(online, id)})
+	}
+	return nil
+}
+
 func (h *Handler) Join(ctx context.Context, req *proto.JoinRequest, rsp *proto.JoinResponse) error {
 
 	if err := h.repo.Join(req.Id, req.RoomId); err != nil {
@@ -119,19 +144,23 @@ func (h *Handler) Join(ctx context.Context, req *proto.JoinRequest, rsp *proto.J
 		return err
 	}
 
-	event, _ := json.Marshal(&proto.Event{
+	e := &proto.Event{
 		Type: "join",
 		From: req.Id,
 		To:   req.RoomId,
-	})
+	}
+	event, _ := json.Marshal(e)
 
 	for _, m := range members {
 		topic := h.service + "." + m.Id
-		if err := h.broker.Publish(topic, &broker.Message{Body: event}); err != nil {
-			fmt.Println("DEBUG ->", err)
+		if err := h.publish(ctx, topic, event); err != nil {
+			h.opts.Logger.Warn("join publish failed", UserField(m.Id), RoomField(req.RoomId), ErrField(err))
 		}
 	}
 
+	h.dispatch(req.RoomId, e)
+	h.welcome(ctx, req.Id, req.RoomId)
+
 	return nil
 }
 
@@ -145,70 +174,197 @@ func (h *Handler) Out(ctx context.Context, req *proto.OutRequest, rsp *proto.Out
 		return err
 	}
 
-	event, _ := json.Marshal(&proto.Event{
+	e := &proto.Event{
 		Type: "out",
 		From: req.Id,
 		To:   req.RoomId,
-	})
+	}
+	event, _ := json.Marshal(e)
 
 	for _, m := range managers {
 		topic := h.service + "." + m.Id
-		if err := h.broker.Publish(topic, &broker.Message{Body: event}); err != nil {
-			fmt.Println("DEBUG ->", err)
+		if err := h.publish(ctx, topic, event); err != nil {
+			h.opts.Logger.Warn("out publish failed", UserField(m.Id), RoomField(req.RoomId), ErrField(err))
 		}
 	}
 
+	h.dispatch(req.RoomId, e)
+
 	return nil
 }
 
-func (h *Handler) Send(ctx context.Context, req *proto.SendRequest, rsp *proto.SendResponse) error {
-	log.Println("server recv event", req.Event)
+func (h *Handler) Send(ctx context.Context, req *proto.SendRequest, rsp *proto.SendResponse) (err error) {
+	ctx, span := tracer().Start(traceFromContext(ctx), "Handler.Send", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() { endSpan(span, err) }()
+
+	h.opts.Logger.Debug("server recv event", EventField(req.Event))
 
 	if !in(AcceptEvent, req.Event.Type) {
+		h.opts.Metrics.error("event_type")
 		return errors.New("不能接受的消息类型")
 	}
+	h.opts.Metrics.event(req.Event.Type)
+
+	if err := h.authorizeSender(ctx, req.Event.From); err != nil {
+		h.opts.Metrics.error("bot_token")
+		return err
+	}
+
+	roomId, to := splitDest(req.Event.To)
+
+	// 频率限制
+	if h.opts.RateLimiter != nil && ctx.Value(scheduledKey{}) == nil {
+		if err := h.opts.RateLimiter.Allow(req.Event.From, roomId, req.Event.Type); err != nil {
+			h.opts.Metrics.error("rate_limited")
+			return rateLimitError(h.service, err)
+		}
+	}
+
+	// 已读回执由服务端汇总后以status事件通知发送者，不再转发
+	if req.Event.Type == ReceiptEvent {
+		h.readMentions(req.Event.From, req.Event.Body)
+	}
+	if req.Event.Type == ReceiptEvent && h.opts.Receipts != nil {
+		return h.read(ctx, req.Event.From, req.Event.Body)
+	}
 
 	if len(req.Event.Id) == 0 {
 		u1, _ := uuid.NewV4()
 		req.Event.Id = strings.Replace(u1.String(), "-", "", -1)
 	}
+	// 定时消息保存后由Scheduler发送
+	if req.SendAt > time.Now().Unix() {
+		return h.schedule(req, rsp)
+	}
+	if req.Event.Created == 0 {
+		req.Event.Created = time.Now().Unix()
+	}
+	// 提及及优先级由服务端设置
+	req.Event.Mentions = nil
+	req.Event.Priority = ""
+	span.SetAttributes(
+		attribute.String("chat.event.id", req.Event.Id),
+		attribute.String("chat.event.type", req.Event.Type),
+	)
+
+	// 内容过滤
+	if h.opts.Filters != nil && req.Event.Type == "message" {
+		result := h.opts.Filters.Chain(roomId).Run(req.Event)
+		if result.Rejected {
+			h.opts.Metrics.error("filtered")
+			return errors.New("消息被拒绝: " + result.Reason)
+		}
+		if len(result.Flags) > 0 {
+			h.flag(ctx, req.Event, result.Flags)
+		}
+	}
+
+	// 回复消息只通知关注话题的成员
+	var followers []string
+	if len(req.Event.Parent) > 0 && h.opts.History != nil {
+		if followers, err = h.thread(req.Event, roomId, to); err != nil {
+			return err
+		}
+	}
+
+	// 房间消息中的@，被提及的成员收到高优先级的副本
+	var (
+		mentioned map[string]string
+		priority  []byte
+	)
+	if len(roomId) > 0 && req.Event.Type == "message" {
+		if mentioned, err = h.mentions(req.Event, roomId); err != nil {
+			return err
+		}
+	}
+
+	// 会话设置了有效期时消息到期后删除
+	if err := h.expires(req.Event, roomId, to); err != nil {
+		return err
+	}
 
 	event, err := json.Marshal(req.Event)
 	if err != nil {
 		return err
 	}
+	if len(mentioned) > 0 {
+		e := *req.Event
+		e.Priority = PriorityHigh
+		priority, _ = json.Marshal(&e)
+	}
 
-	roomId, to := splitDest(req.Event.To)
+	// 斜杠命令路由给机器人
+	routed, err := h.route(ctx, req.Event)
+	if err != nil {
+		return err
+	}
+	if routed {
+		rsp.Id = req.Event.Id
+		return nil
+	}
 
 	if len(roomId) > 0 {
 		members, err := h.repo.Members(roomId, false)
 		if err != nil {
 			return err
 		}
+		if followers != nil {
+			// 被提及的成员即使未关注话题也会收到
+			for id := range mentioned {
+				followers = append(followers, id)
+			}
+			members = following(members, followers)
+		}
+		start := time.Now()
+		recipients := []string{}
 		for _, m := range members {
 			topic := h.service + "." + m.Id
-			if err := h.broker.Publish(topic, &broker.Message{Body: event}); err != nil {
-				fmt.Println("Publish DEBUG ->", err)
+			body := event
+			if _, ok := mentioned[m.Id]; ok {
+				body = priority
+			}
+			if err := h.publish(ctx, topic, body); err != nil {
+				h.opts.Metrics.error("publish")
+				h.opts.Logger.Warn("publish failed", UserField(m.Id), RoomField(roomId), EventIdField(req.Event.Id), ErrField(err))
+				continue
+			}
+			if m.Id != req.Event.From {
+				recipients = append(recipients, m.Id)
 			}
 		}
+		h.opts.Metrics.fanout("room", start)
+		h.sent(req.Event, roomId, recipients)
 	} else {
 		// 判断用户是否存在
 		if _, err := h.repo.GetUser(to); err != nil {
 			return err
 		}
 
+		start := time.Now()
 		topic := h.service + "." + to
-		if err := h.broker.Publish(topic, &broker.Message{Body: event}); err != nil {
+		if err := h.publish(ctx, topic, event); err != nil {
+			h.opts.Metrics.error("publish")
 			return err
 		}
+		h.opts.Metrics.fanout("direct", start)
+		h.sent(req.Event, "", []string{to})
 	}
 
-	// 同时合并消息发送一条给管理后台订阅
+	h.save(req.Event, roomId, to)
+	h.saveMentions(req.Event, roomId, mentioned)
+
+	// 同时合并消息发送一条给管理后台订阅，加密消息只发送元数据
+	if req.Event.Type == EncryptedEvent {
+		event, _ = json.Marshal(redact(req.Event))
+	}
 	adminTopic := h.service + "." + "admin"
-	if err := h.broker.Publish(adminTopic, &broker.Message{Body: event}); err != nil {
+	if err := h.publish(ctx, adminTopic, event); err != nil {
 		return err
 	}
 
+	// webhook回调
+	h.dispatch(roomId, redact(req.Event))
+
 	// 返回id
 	rsp.Id = req.Event.Id
 
@@ -228,6 +384,9 @@ func (h *Handler) Stream(ctx context.Context, req *proto.StreamRequest, stream p
 	if _, err := h.repo.GetUser(req.Id); err != nil {
 		return err
 	}
+	if err := h.authorizeBot(req); err != nil {
+		return err
+	}
 
 	// 检查用户所有平台登录情况
 	current, err := h.repo.AvailableClient(req.Id, req.Platform)
@@ -237,21 +396,20 @@ func (h *Handler) Stream(ctx context.Context, req *proto.StreamRequest, stream p
 
 	// 处理用户平台冲突强制下线逻辑
 	if current.IsOnline {
-		body, _ := json.Marshal(map[string]interface{}{
-			"id":       current.Id,
-			"platform": current.Platform,
-		})
-		// 发送强制下线消息给所有srv
-		if err := h.broker.Publish(h.service, &broker.Message{
-			Body: body,
-		}); err != nil {
-			fmt.Println("stream publish err", err)
+		if err := h.kick(current.Id, current.Platform, KickDuplicateLogin); err != nil {
 			return err
 		}
 	}
 
 	// 初始化
-	conn := NewConn(h.service, req.Id, req.Platform, req.Start, stream)
+	conn := NewConn(h.service, req.Id, req.Platform, req.Start, stream, h.opts.Logger)
+	conn.metrics = h.opts.Metrics
+	conn.newBroker = h.opts.ConnBroker
+	if h.opts.Receipts != nil {
+		conn.delivered = func(ctx context.Context, event *proto.Event) {
+			h.delivered(ctx, req.Id, event)
+		}
+	}
 
 	retry = 0
 	for {
@@ -272,20 +430,343 @@ func (h *Handler) Stream(ctx context.Context, req *proto.StreamRequest, stream p
 	defer conn.Close()
 
 	if _, err := conn.Subscribe(); err != nil {
-		fmt.Println("subscribe err", err)
+		h.opts.Logger.Error("subscribe failed", UserField(req.Id), PlatformField(req.Platform), ErrField(err))
 		return err
 	}
 
 	// 加入hub管理
 	h.hub.Register(conn)
 	defer h.hub.Unregister(conn)
+	h.opts.Metrics.session(req.Platform, 1)
+	defer h.opts.Metrics.session(req.Platform, -1)
+
+	// 登录记录先于上线写入，会话列表中在线的平台总有对应记录
+	h.logLogin(req)
 
 	// 在线
 	if err := h.repo.Online(req.Id, req.Platform); err != nil {
 		return err
 	}
-	defer h.repo.Offline(req.Id, req.Platform)
+	defer func() {
+		if !conn.kicked {
+			h.repo.Offline(req.Id, req.Platform)
+		}
+	}()
 
 	conn.Run()
 	return nil
 }
+
+func (h *Handler) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest, rsp *proto.CreateWebhookResponse) error {
+	if h.opts.Webhooks == nil {
+		return errors.New("webhook未启用")
+	}
+	if req.Webhook == nil || len(req.Webhook.Url) == 0 {
+		return errors.New("url is required")
+	}
+	if len(req.Webhook.Secret) == 0 {
+		return errors.New("secret is required")
+	}
+
+	hook := &Webhook{
+		Url:    req.Webhook.Url,
+		Secret: req.Webhook.Secret,
+		Events: strings.Join(req.Webhook.Events, ","),
+		Rooms:  strings.Join(req.Webhook.Rooms, ","),
+		Active: true,
+	}
+	if err := h.opts.Webhooks.Create(hook); err != nil {
+		return err
+	}
+	rsp.Webhook = hook.ToProto()
+	return nil
+}
+
+func (h *Handler) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest, rsp *proto.DeleteWebhookResponse) error {
+	if h.opts.Webhooks == nil {
+		return errors.New("webhook未启用")
+	}
+	return h.opts.Webhooks.Delete(req.Id)
+}
+
+func (h *Handler) Webhooks(ctx context.Context, req *proto.WebhooksRequest, rsp *proto.WebhooksResponse) error {
+	if h.opts.Webhooks == nil {
+		return errors.New("webhook未启用")
+	}
+	hooks, err := h.opts.Webhooks.repo.Webhooks()
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		rsp.Webhooks = append(rsp.Webhooks, hook.ToProto())
+	}
+	return nil
+}
+
+// dispatch 投递webhook，未启用时忽略
+func (h *Handler) dispatch(roomId string, event *proto.Event) {
+	if h.opts.Webhooks == nil {
+		return
+	}
+	h.opts.Webhooks.Dispatch(roomId, event)
+}
+
+func (h *Handler) CreateBot(ctx context.Context, req *proto.CreateBotRequest, rsp *proto.CreateBotResponse) error {
+	if h.opts.Bots == nil {
+		return errors.New("机器人未启用")
+	}
+	if req.Bot == nil || len(req.Bot.Id) == 0 {
+		return errors.New("id is required")
+	}
+	// 不能把已有用户变为机器人，否则token可以冒充该用户发送
+	if _, err := h.repo.GetUser(req.Bot.Id); err == nil {
+		return ErrBotExists
+	} else if err != ErrNotFound {
+		return err
+	}
+
+	token, hash, err := NewBotToken()
+	if err != nil {
+		return err
+	}
+
+	// 机器人同时是普通用户
+	if err := h.Register(ctx, &proto.RegisterRequest{
+		User: &proto.User{Id: req.Bot.Id, Name: req.Bot.Name},
+	}, &proto.RegisterResponse{}); err != nil {
+		return err
+	}
+	bot := &Bot{
+		Id:          req.Bot.Id,
+		Name:        req.Bot.Name,
+		Passthrough: req.Bot.Passthrough,
+		Commands:    req.Bot.Commands,
+		Created:     time.Now(),
+	}
+	if err := h.opts.Bots.CreateBot(bot, hash); err != nil {
+		// 删除已注册的用户，否则重试时会返回ErrBotExists
+		if uerr := h.Unregister(ctx, &proto.UnregisterRequest{Id: bot.Id}, &proto.UnregisterResponse{}); uerr != nil {
+			h.opts.Logger.Error("create bot rollback failed", UserField(bot.Id), ErrField(uerr))
+		}
+		return err
+	}
+
+	rsp.Bot = bot.ToProto()
+	rsp.Token = token
+	return nil
+}
+
+func (h *Handler) DeleteBot(ctx context.Context, req *proto.DeleteBotRequest, rsp *proto.DeleteBotResponse) error {
+	if h.opts.Bots == nil {
+		return errors.New("机器人未启用")
+	}
+	if err := h.opts.Bots.DeleteBot(req.Id); err != nil {
+		return err
+	}
+	return h.Unregister(ctx, &proto.UnregisterRequest{Id: req.Id}, &proto.UnregisterResponse{})
+}
+
+func (h *Handler) Bots(ctx context.Context, req *proto.BotsRequest, rsp *proto.BotsResponse) error {
+	if h.opts.Bots == nil {
+		return errors.New("机器人未启用")
+	}
+	bots, err := h.opts.Bots.Bots()
+	if err != nil {
+		return err
+	}
+	for _, bot := range bots {
+		rsp.Bots = append(rsp.Bots, bot.ToProto())
+	}
+	return nil
+}
+
+func (h *Handler) BotSend(ctx context.Context, req *proto.BotSendRequest, rsp *proto.BotSendResponse) error {
+	if h.opts.Bots == nil {
+		return errors.New("机器人未启用")
+	}
+	if req.Event == nil {
+		return errors.New("event is required")
+	}
+
+	bot, err := h.opts.Bots.BotByToken(HashBotToken(req.Token))
+	if err != nil {
+		return err
+	}
+
+	// 以机器人身份发送
+	req.Event.From = bot.Id
+	sendRsp := &proto.SendResponse{}
+	ctx = context.WithValue(ctx, botSendKey{}, true)
+	if err := h.Send(ctx, &proto.SendRequest{Event: req.Event, SendAt: req.SendAt}, sendRsp); err != nil {
+		return err
+	}
+	rsp.Id = sendRsp.Id
+	return nil
+}
+
+// authorizeSender 机器人只能通过BotSend以token发送，定时消息在定时时已验证
+func (h *Handler) authorizeSender(ctx context.Context, from string) error {
+	if h.opts.Bots == nil || ctx.Value(botSendKey{}) != nil || ctx.Value(scheduledKey{}) != nil {
+		return nil
+	}
+	bot, err := h.opts.Bots.BotById(from)
+	if err != nil || bot == nil {
+		return err
+	}
+	return ErrBotToken
+}
+
+// authorizeBot 机器人只能以BotPlatform及创建时返回的token连接
+func (h *Handler) authorizeBot(req *proto.StreamRequest) error {
+	if h.opts.Bots == nil {
+		return nil
+	}
+	bot, err := h.opts.Bots.BotById(req.Id)
+	if err != nil || bot == nil {
+		return err
+	}
+	if req.Platform != BotPlatform || len(req.Token) == 0 {
+		return ErrBotToken
+	}
+	byToken, err := h.opts.Bots.BotByToken(HashBotToken(req.Token))
+	if err != nil {
+		return err
+	}
+	if byToken.Id != bot.Id {
+		return ErrBotToken
+	}
+	return nil
+}
+
+// route 将斜杠命令投递给注册的机器人，返回true时不再发送给原接收者
+func (h *Handler) route(ctx context.Context, event *proto.Event) (bool, error) {
+	if h.opts.Bots == nil || event.Type != "message" {
+		return false, nil
+	}
+	command, _, ok := ParseCommand(event.Body)
+	if !ok {
+		return false, nil
+	}
+	bot, err := h.opts.Bots.BotByCommand(command)
+	if err != nil {
+		return false, err
+	}
+	// 未注册的命令按普通消息处理; 机器人自己发出的命令不再路由
+	if bot == nil || bot.Id == event.From {
+		return false, nil
+	}
+
+	body, _ := json.Marshal(&proto.Event{
+		Id:      event.Id,
+		Type:    CommandEvent,
+		From:    event.From,
+		To:      event.To,
+		Body:    event.Body,
+		Created: event.Created,
+	})
+	topic := h.service + "." + bot.Id
+	if err := h.publish(ctx, topic, body); err != nil {
+		return false, err
+	}
+
+	return !bot.Passthrough, nil
+}
+
+// publish 发布到broker，消息头中携带trace上下文
+func (h *Handler) publish(ctx context.Context, topic string, body []byte) error {
+	ctx, span := tracer().Start(ctx, "broker.Publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("messaging.destination", topic)),
+	)
+	msg := &broker.Message{
+		Header: map[string]string{},
+		Body:   body,
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(msg.Header))
+	err := h.broker.Publish(topic, msg)
+	endSpan(span, err)
+	return err
+}
+
+// flag 将命中过滤规则的消息提交管理后台审核
+func (h *Handler) flag(ctx context.Context, event *proto.Event, reasons []string) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"reasons": reasons,
+		"event":   event,
+	})
+	flagged, _ := json.Marshal(&proto.Event{
+		Id:   event.Id,
+		Type: "flag",
+		From: event.From,
+		To:   event.To,
+		Body: string(body),
+	})
+	adminTopic := h.service + "." + "admin"
+	if err := h.publish(ctx, adminTopic, flagged); err != nil {
+		h.opts.Logger.Error("flag publish failed", EventIdField(event.Id), ErrField(err))
+	}
+}
+
+func (h *Handler) UploadKeys(ctx context.Context, req *proto.UploadKeysRequest, rsp *proto.UploadKeysResponse) error {
+	if h.opts.Keys == nil {
+		return errors.New("端到端加密未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	if len(req.IdentityKey) > 0 {
+		if len(req.IdentityKey) != 32 {
+			return errors.New("invalid identity key")
+		}
+		current, err := h.opts.Keys.IdentityKey(req.Id)
+		if err != nil && err != ErrNoIdentityKey {
+			return err
+		}
+		// 重新上传相同的身份公钥只追加prekeys，不同时需明确替换，避免第二个设备使第一个无法解密
+		if err == ErrNoIdentityKey || string(current) != string(req.IdentityKey) {
+			if err == nil && !req.Replace {
+				return ErrIdentityExists
+			}
+			if err := h.opts.Keys.SetIdentityKey(req.Id, req.IdentityKey); err != nil {
+				return err
+			}
+		}
+	} else if _, err := h.opts.Keys.IdentityKey(req.Id); err != nil {
+		return err
+	}
+	for _, k := range req.Prekeys {
+		if len(k.Id) == 0 || len(k.PublicKey) != 32 {
+			return errors.New("invalid prekey")
+		}
+	}
+	if err := h.opts.Keys.AddPreKeys(req.Id, req.Prekeys); err != nil {
+		return err
+	}
+
+	count, err := h.opts.Keys.PreKeyCount(req.Id)
+	if err != nil {
+		return err
+	}
+	rsp.Prekeys = count
+	return nil
+}
+
+func (h *Handler) FetchKeys(ctx context.Context, req *proto.FetchKeysRequest, rsp *proto.FetchKeysResponse) error {
+	if h.opts.Keys == nil {
+		return errors.New("端到端加密未启用")
+	}
+	identity, err := h.opts.Keys.IdentityKey(req.UserId)
+	if err != nil {
+		return err
+	}
+	prekey, err := h.opts.Keys.TakePreKey(req.UserId)
+	if err != nil {
+		return err
+	}
+	rsp.Bundle = &proto.KeyBundle{
+		UserId:      req.UserId,
+		IdentityKey: identity,
+		Prekey:      prekey,
+	}
+	return nil
+}
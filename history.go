@@ -0,0 +1,378 @@
+package gochat
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var (
+	ErrNotMember          = errors.New("不是房间成员")
+	ErrParentConversation = errors.New("回复的消息不在此会话")
+
+	// 历史消息每页默认及最大条数
+	HistoryLimit    = 50
+	MaxHistoryLimit = 200
+)
+
+// 记录历史的消息类型
+var historyEvents = []string{"message", EncryptedEvent}
+
+// Message 历史消息，对应messages
+type Message struct {
+	Id           int64  `db:"id"`
+	EventId      string `db:"event_id"`
+	Type         string `db:"type"`
+	Conversation string `db:"conversation"`
+	RoomId       string `db:"room_id"`
+	Sender       string `db:"sender"`
+	Recipient    string `db:"recipient"` // 同Event.To
+	Body         string `db:"body"`
+	ParentId     string `db:"parent_id"`
+	ReplyCount   int64  `db:"reply_count"`
+	LastReply    int64  `db:"last_reply"`
+	Created      int64  `db:"created"`
+	Expires      int64  `db:"expires"` // 0为不过期
+}
+
+func newMessage(event *proto.Event, roomId, to string) *Message {
+	return &Message{
+		EventId:      event.Id,
+		Type:         event.Type,
+		Conversation: conversation(roomId, event.From, to),
+		RoomId:       roomId,
+		Sender:       event.From,
+		Recipient:    event.To,
+		Body:         event.Body,
+		ParentId:     event.Parent,
+		Created:      event.Created,
+		Expires:      event.Expires,
+	}
+}
+
+func (m *Message) ToProto() *proto.Message {
+	return &proto.Message{
+		Event: &proto.Event{
+			Id:      m.EventId,
+			Type:    m.Type,
+			From:    m.Sender,
+			To:      m.Recipient,
+			Body:    m.Body,
+			Created: m.Created,
+			Parent:  m.ParentId,
+			Expires: m.Expires,
+		},
+		ReplyCount: m.ReplyCount,
+		LastReply:  m.LastReply,
+	}
+}
+
+// conversation 会话标识，房间为房间id，私聊为排序后的两个用户id
+func conversation(roomId, from, to string) string {
+	if len(roomId) > 0 {
+		return roomId
+	}
+	users := []string{from, to}
+	sort.Strings(users)
+	return users[0] + ":" + users[1]
+}
+
+type HistoryRepository interface {
+	// 保存消息，回复时更新父消息的回复数及最后回复时间，重复保存忽略
+	Save(msg *Message) error
+	// 查询消息，不存在时返回ErrNotFound
+	Get(eventId string) (*Message, error)
+	// 会话中的消息，不含回复，before为空时从最新开始，按时间正序返回
+	History(conversation, before string, limit int) ([]*Message, error)
+	// 话题的回复，after为空时从第一条开始
+	Replies(parentId, after string, limit int) ([]*Message, error)
+	// 关注话题
+	Follow(parentId, uid string) error
+	// 取消关注
+	Unfollow(parentId, uid string) error
+	// 关注话题的用户
+	Followers(parentId string) ([]string, error)
+	// 添加表情，已添加过时返回false
+	React(eventId, uid, emoji string) (bool, error)
+	// 取消表情，未添加过时返回false
+	Unreact(eventId, uid, emoji string) (bool, error)
+	// 各消息的表情汇总，按首次使用的顺序，reacted按uid计算
+	Reactions(eventIds []string, uid string) (map[string][]*proto.Reaction, error)
+	// 置顶房间消息，已置顶时返回false，房间置顶数超过max时返回ErrTooManyPins
+	Pin(roomId, eventId, uid string, max int) (bool, error)
+	// 取消置顶，未置顶时返回false
+	Unpin(roomId, eventId string) (bool, error)
+	// 房间置顶的消息，按置顶时间倒序
+	Pins(roomId string) ([]*Message, error)
+	// 会话的消息有效期(秒)，未设置时返回0
+	TTL(conversation string) (int64, error)
+	// 设置会话的消息有效期，ttl为0时关闭
+	SetTTL(conversation string, ttl int64, uid string) error
+	// 过期时间不晚于now的消息，按过期时间正序
+	Expired(now int64, limit int) ([]*Message, error)
+	// 删除消息及其表情、置顶及话题关注，已被删除时返回false
+	Expire(eventId string) (bool, error)
+}
+
+func NewHistoryRepo(db *sqlx.DB) *historyRepo {
+	return &historyRepo{
+		db:      db,
+		dialect: DialectOf(db),
+	}
+}
+
+type historyRepo struct {
+	db      *sqlx.DB
+	dialect Dialect
+}
+
+// 未过期的消息，清理前已过期的也不再返回
+const unexpired = `(expires = 0 OR expires > ?)`
+
+const messageColumns = `id, event_id, type, conversation, room_id, sender, recipient, body, parent_id, reply_count, last_reply, created, expires`
+
+func (r *historyRepo) Save(msg *Message) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(tx.Rebind(r.dialect.insertIgnore("messages",
+		[]string{"event_id", "type", "conversation", "room_id", "sender", "recipient", "body", "parent_id", "created", "expires"},
+	)), msg.EventId, msg.Type, msg.Conversation, msg.RoomId, msg.Sender, msg.Recipient, msg.Body, msg.ParentId, msg.Created, msg.Expires)
+	if err != nil {
+		return err
+	}
+	// 重复投递的消息不重复计数
+	if n, _ := result.RowsAffected(); n > 0 && len(msg.ParentId) > 0 {
+		if _, err := tx.Exec(tx.Rebind(`
+			UPDATE messages SET reply_count = reply_count + 1, last_reply = ? WHERE event_id = ?
+			`), msg.Created, msg.ParentId); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *historyRepo) Get(eventId string) (*Message, error) {
+	msg := &Message{}
+	err := r.db.Get(msg, r.db.Rebind(`SELECT `+messageColumns+` FROM messages WHERE event_id = ? AND `+unexpired), eventId, time.Now().Unix())
+	return msg, notFound(err)
+}
+
+func (r *historyRepo) History(conversation, before string, limit int) ([]*Message, error) {
+	msgs := []*Message{}
+	query := `SELECT ` + messageColumns + ` FROM messages WHERE conversation = ? AND parent_id = '' AND ` + unexpired
+	args := []interface{}{conversation, time.Now().Unix()}
+	if len(before) > 0 {
+		query += ` AND id < (SELECT id FROM messages WHERE event_id = ?)`
+		args = append(args, before)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+	if err := r.db.Select(&msgs, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+	return msgs, nil
+}
+
+func (r *historyRepo) Replies(parentId, after string, limit int) ([]*Message, error) {
+	msgs := []*Message{}
+	query := `SELECT ` + messageColumns + ` FROM messages WHERE parent_id = ? AND ` + unexpired
+	args := []interface{}{parentId, time.Now().Unix()}
+	if len(after) > 0 {
+		query += ` AND id > (SELECT id FROM messages WHERE event_id = ?)`
+		args = append(args, after)
+	}
+	query += ` ORDER BY id LIMIT ?`
+	args = append(args, limit)
+	err := r.db.Select(&msgs, r.db.Rebind(query), args...)
+	return msgs, err
+}
+
+func (r *historyRepo) Follow(parentId, uid string) error {
+	_, err := r.db.Exec(r.db.Rebind(r.dialect.insertIgnore("thread_followers", []string{"parent_id", "user_id"})), parentId, uid)
+	return err
+}
+
+func (r *historyRepo) Unfollow(parentId, uid string) error {
+	_, err := r.db.Exec(r.db.Rebind(`DELETE FROM thread_followers WHERE parent_id = ? AND user_id = ?`), parentId, uid)
+	return err
+}
+
+func (r *historyRepo) Followers(parentId string) ([]string, error) {
+	users := []string{}
+	err := r.db.Select(&users, r.db.Rebind(`
+		SELECT user_id FROM thread_followers WHERE parent_id = ? ORDER BY user_id
+		`), parentId)
+	return users, err
+}
+
+func (h *Handler) History(ctx context.Context, req *proto.HistoryRequest, rsp *proto.HistoryResponse) error {
+	if h.opts.History == nil {
+		return errors.New("消息历史未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	roomId, to := splitDest(req.To)
+	if len(roomId) > 0 {
+		if err := h.member(req.Id, roomId); err != nil {
+			return err
+		}
+	} else if len(to) == 0 {
+		return errors.New("to is required")
+	}
+	msgs, err := h.opts.History.History(conversation(roomId, req.Id, to), req.Before, historyLimit(req.Limit))
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		rsp.Messages = append(rsp.Messages, m.ToProto())
+	}
+	return h.withReactions(req.Id, rsp.Messages)
+}
+
+func (h *Handler) Thread(ctx context.Context, req *proto.ThreadRequest, rsp *proto.ThreadResponse) error {
+	parent, err := h.threadParent(req.Id, req.ParentId)
+	if err != nil {
+		return err
+	}
+	replies, err := h.opts.History.Replies(parent.EventId, req.After, historyLimit(req.Limit))
+	if err != nil {
+		return err
+	}
+	rsp.Parent = parent.ToProto()
+	for _, m := range replies {
+		rsp.Replies = append(rsp.Replies, m.ToProto())
+	}
+	return h.withReactions(req.Id, append([]*proto.Message{rsp.Parent}, rsp.Replies...))
+}
+
+func (h *Handler) FollowThread(ctx context.Context, req *proto.FollowThreadRequest, rsp *proto.FollowThreadResponse) error {
+	parent, err := h.threadParent(req.Id, req.ParentId)
+	if err != nil {
+		return err
+	}
+	return h.opts.History.Follow(parent.EventId, req.Id)
+}
+
+func (h *Handler) UnfollowThread(ctx context.Context, req *proto.UnfollowThreadRequest, rsp *proto.UnfollowThreadResponse) error {
+	parent, err := h.threadParent(req.Id, req.ParentId)
+	if err != nil {
+		return err
+	}
+	return h.opts.History.Unfollow(parent.EventId, req.Id)
+}
+
+// threadParent 查询话题的根消息并检查uid能否查看
+func (h *Handler) threadParent(uid, parentId string) (*Message, error) {
+	parent, err := h.message(uid, parentId)
+	if err != nil {
+		return nil, err
+	}
+	// 回复与根消息在同一会话
+	if len(parent.ParentId) > 0 {
+		return h.opts.History.Get(parent.ParentId)
+	}
+	return parent, nil
+}
+
+// message 查询历史消息并检查uid能否查看，私聊只有双方可见
+func (h *Handler) message(uid, id string) (*Message, error) {
+	if h.opts.History == nil {
+		return nil, errors.New("消息历史未启用")
+	}
+	if len(uid) == 0 {
+		return nil, errors.New("id is required")
+	}
+	msg, err := h.opts.History.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.RoomId) > 0 {
+		if err := h.member(uid, msg.RoomId); err != nil {
+			return nil, err
+		}
+	} else if uid != msg.Sender && uid != msg.Recipient {
+		return nil, ErrNotFound
+	}
+	return msg, nil
+}
+
+// thread 回复消息挂到话题的根消息上，发送者及根消息的作者自动关注，返回关注者
+func (h *Handler) thread(event *proto.Event, roomId, to string) ([]string, error) {
+	parent, err := h.opts.History.Get(event.Parent)
+	if err != nil {
+		return nil, err
+	}
+	if len(parent.ParentId) > 0 {
+		if parent, err = h.opts.History.Get(parent.ParentId); err != nil {
+			return nil, err
+		}
+	}
+	if parent.Conversation != conversation(roomId, event.From, to) {
+		return nil, ErrParentConversation
+	}
+	event.Parent = parent.EventId
+
+	for _, uid := range []string{parent.Sender, event.From} {
+		if err := h.opts.History.Follow(parent.EventId, uid); err != nil {
+			return nil, err
+		}
+	}
+	return h.opts.History.Followers(parent.EventId)
+}
+
+// save 记录历史，失败不影响发送
+func (h *Handler) save(event *proto.Event, roomId, to string) {
+	if h.opts.History == nil || !in(historyEvents, event.Type) {
+		return
+	}
+	// 加密消息不记录内容
+	if err := h.opts.History.Save(newMessage(redact(event), roomId, to)); err != nil {
+		h.opts.Logger.Warn("history save failed", EventIdField(event.Id), RoomField(roomId), ErrField(err))
+	}
+}
+
+// following 只保留关注话题的成员
+func following(members []*proto.User, followers []string) []*proto.User {
+	users := []*proto.User{}
+	for _, m := range members {
+		if in(followers, m.Id) {
+			users = append(users, m)
+		}
+	}
+	return users
+}
+
+func (h *Handler) member(uid, roomId string) error {
+	members, err := h.repo.Members(roomId, false)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.Id == uid {
+			return nil
+		}
+	}
+	return ErrNotMember
+}
+
+func historyLimit(limit int64) int {
+	if limit <= 0 {
+		return HistoryLimit
+	}
+	if limit > int64(MaxHistoryLimit) {
+		return MaxHistoryLimit
+	}
+	return int(limit)
+}
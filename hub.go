@@ -2,53 +2,154 @@ package gochat
 
 import (
 	"encoding/json"
-	"log"
+	"sync"
 
+	proto "github.com/laoqiu/go-chat/proto"
 	"github.com/micro/go-micro/broker"
+	uuid "github.com/satori/go.uuid"
 )
 
+// EphemeralMessage 只推送给在线连接的轻量事件，不进入用户的持久化队列
+type EphemeralMessage struct {
+	Users []string     `json:"users"`
+	Event *proto.Event `json:"event"`
+}
+
 type Hub struct {
 	service string
+	node    string
 	broker  broker.Broker
+	mu      sync.Mutex
 	clients map[*Conn]bool
+	log     Logger
 }
 
-func NewHub(service string, broker broker.Broker) *Hub {
+func NewHub(service string, broker broker.Broker, logger Logger) *Hub {
+	u1, _ := uuid.NewV4()
 	return &Hub{
 		service: service,
+		node:    u1.String(),
 		broker:  broker,
 		clients: make(map[*Conn]bool),
+		log:     loggerOrDefault(logger),
 	}
 }
 
 func (h *Hub) Register(conn *Conn) {
-	log.Println("hub register", conn.id, conn.platform)
+	h.log.Info("hub register", UserField(conn.id), PlatformField(conn.platform))
+	h.mu.Lock()
 	h.clients[conn] = true
+	h.mu.Unlock()
 }
 
 func (h *Hub) Unregister(conn *Conn) {
-	log.Println("hub unregister")
+	h.log.Info("hub unregister", UserField(conn.id), PlatformField(conn.platform))
+	h.mu.Lock()
 	delete(h.clients, conn)
+	h.mu.Unlock()
 }
 
+// Subscribe 订阅强制下线及轻量事件，返回的Subscriber取消全部订阅
 func (h *Hub) Subscribe() (broker.Subscriber, error) {
+	kick, err := h.subscribeKick()
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := h.broker.Subscribe(ephemeralTopic(h.service), func(p broker.Publication) error {
+		msg := &EphemeralMessage{}
+		if err := json.Unmarshal(p.Message().Body, msg); err != nil || msg.Event == nil {
+			h.log.Warn("hub dropped malformed ephemeral message", F("bytes", len(p.Message().Body)), ErrField(err))
+			return nil
+		}
+		h.push(msg.Users, msg.Event)
+		return nil
+	})
+	if err != nil {
+		kick.Unsubscribe()
+		return nil, err
+	}
+	return &hubSubscriber{Subscriber: kick, subs: []broker.Subscriber{kick, ephemeral}}, nil
+}
+
+func (h *Hub) subscribeKick() (broker.Subscriber, error) {
 	return h.broker.Subscribe(h.service, func(p broker.Publication) error {
-		log.Println("[hub] received message:", string(p.Message().Body), "header", p.Message().Header)
-		data := map[string]interface{}{}
-		if err := json.Unmarshal(p.Message().Body, &data); err != nil {
-			return err
+		h.log.Debug("hub received message", F("bytes", len(p.Message().Body)))
+		msg := &KickMessage{}
+		// 格式错误的消息直接丢弃，重新投递也无法处理
+		if err := json.Unmarshal(p.Message().Body, msg); err != nil || len(msg.Id) == 0 {
+			h.log.Warn("hub dropped malformed message", F("bytes", len(p.Message().Body)), ErrField(err))
+			return nil
+		}
+		if len(msg.Reason) == 0 {
+			msg.Reason = KickDuplicateLogin
 		}
 		// 处理强制下线逻辑
-		h.shutdown(data["id"].(string), data["platform"].(string))
+		n := h.shutdown(msg.Id, msg.Platform, msg.Reason)
+		if len(msg.KickId) > 0 {
+			go h.ack(msg.KickId, n)
+		}
 		return nil
 	})
 }
 
-func (h *Hub) shutdown(id, platform string) {
+// ack 确认本节点已处理下线消息
+func (h *Hub) ack(kickId string, sessions int) {
+	body, _ := json.Marshal(&KickAck{KickId: kickId, Node: h.node, Sessions: sessions})
+	if err := h.broker.Publish(kickAckTopic(h.service), &broker.Message{
+		Body: body,
+	}); err != nil {
+		h.log.Warn("kick ack publish failed", ErrField(err))
+	}
+}
+
+// shutdown 在锁外通知连接退出，返回下线的连接数
+func (h *Hub) shutdown(id, platform, reason string) int {
+	kicked := []*Conn{}
+	h.mu.Lock()
 	for client := range h.clients {
 		// platform为all时强制下线所有客户端
-		if client.id == id && (platform == "all" || client.platform == platform) {
-			client.done <- 1
+		if client.id == id && (platform == AllPlatforms || client.platform == platform) {
+			kicked = append(kicked, client)
+		}
+	}
+	h.mu.Unlock()
+	for _, client := range kicked {
+		client.kick(reason)
+	}
+	return len(kicked)
+}
+
+// push 推送给本节点上users的所有在线连接
+func (h *Hub) push(users []string, event *proto.Event) {
+	conns := []*Conn{}
+	h.mu.Lock()
+	for client := range h.clients {
+		if in(users, client.id) {
+			conns = append(conns, client)
+		}
+	}
+	h.mu.Unlock()
+	for _, client := range conns {
+		client.push(event)
+	}
+}
+
+func ephemeralTopic(service string) string {
+	return service + ".ephemeral"
+}
+
+// hubSubscriber 合并Hub的多个订阅
+type hubSubscriber struct {
+	broker.Subscriber
+	subs []broker.Subscriber
+}
+
+func (s *hubSubscriber) Unsubscribe() error {
+	var err error
+	for _, sub := range s.subs {
+		if e := sub.Unsubscribe(); e != nil {
+			err = e
 		}
 	}
+	return err
 }
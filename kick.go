@@ -0,0 +1,159 @@
+package gochat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+	"github.com/micro/go-micro/broker"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// 下线原因
+	KickDuplicateLogin  = "duplicate_login"
+	KickAccountDeleted  = "account_deleted"
+	KickAdmin           = "admin"
+	KickPasswordChanged = "password_changed"
+	KickRevoked         = "revoked"
+
+	// 推送给被下线客户端的事件类型，Body为原因
+	KickedEvent = "kicked"
+
+	// 所有平台
+	AllPlatforms = "all"
+)
+
+var (
+	ErrKickReason = errors.New("未知的下线原因")
+
+	// 等待各节点确认的时间
+	KickTimeout = 3 * time.Second
+)
+
+var kickReasons = []string{KickDuplicateLogin, KickAccountDeleted, KickAdmin, KickPasswordChanged, KickRevoked}
+
+// KickMessage 广播给所有节点的强制下线消息
+// id及platform与旧版消息兼容，KickId为空时不需要确认
+type KickMessage struct {
+	Id       string `json:"id"`
+	Platform string `json:"platform"`
+	Reason   string `json:"reason,omitempty"`
+	KickId   string `json:"kick_id,omitempty"`
+}
+
+// KickAck 节点处理完下线消息后的确认
+type KickAck struct {
+	KickId   string `json:"kick_id"`
+	Node     string `json:"node"`
+	Sessions int    `json:"sessions"`
+}
+
+func (h *Handler) Kick(ctx context.Context, req *proto.KickRequest, rsp *proto.KickResponse) error {
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	reason := req.Reason
+	if len(reason) == 0 {
+		reason = KickAdmin
+	}
+	if !in(kickReasons, reason) {
+		return ErrKickReason
+	}
+	platform := req.Platform
+	if len(platform) == 0 {
+		platform = AllPlatforms
+	}
+
+	acks, expected, err := h.kickAndWait(ctx, req.Id, platform, reason)
+	if err != nil {
+		return err
+	}
+	rsp.Nodes = int64(len(acks))
+	for _, ack := range acks {
+		rsp.Sessions += int64(ack.Sessions)
+	}
+	rsp.Complete = expected > 0 && len(acks) >= expected
+	h.opts.Logger.Info("kick", UserField(req.Id), PlatformField(platform), F("reason", reason),
+		F("nodes", rsp.Nodes), F("sessions", rsp.Sessions), F("complete", rsp.Complete))
+	return nil
+}
+
+// kick 广播下线消息，不等待确认
+func (h *Handler) kick(uid, platform, reason string) error {
+	return h.publishKick(&KickMessage{Id: uid, Platform: platform, Reason: reason})
+}
+
+func (h *Handler) publishKick(msg *KickMessage) error {
+	body, _ := json.Marshal(msg)
+	if err := h.broker.Publish(h.service, &broker.Message{
+		Body: body,
+	}); err != nil {
+		h.opts.Logger.Error("kick publish failed", UserField(msg.Id), PlatformField(msg.Platform), ErrField(err))
+		return err
+	}
+	return nil
+}
+
+// kickAndWait 广播下线消息并收集各节点的确认
+// 节点数未知时等待KickTimeout，返回期间收到的确认
+func (h *Handler) kickAndWait(ctx context.Context, uid, platform, reason string) ([]*KickAck, int, error) {
+	expected := 0
+	if h.opts.Nodes != nil {
+		n, err := h.opts.Nodes()
+		if err != nil {
+			h.opts.Logger.Warn("kick node count failed", ErrField(err))
+		}
+		expected = n
+	}
+
+	u1, _ := uuid.NewV4()
+	kickId := u1.String()
+	ch := make(chan *KickAck, 64)
+	sub, err := h.broker.Subscribe(kickAckTopic(h.service), func(p broker.Publication) error {
+		ack := &KickAck{}
+		if err := json.Unmarshal(p.Message().Body, ack); err != nil || ack.KickId != kickId {
+			return nil
+		}
+		select {
+		case ch <- ack:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, expected, err
+	}
+	defer sub.Unsubscribe()
+
+	if err := h.publishKick(&KickMessage{Id: uid, Platform: platform, Reason: reason, KickId: kickId}); err != nil {
+		return nil, expected, err
+	}
+
+	acks := []*KickAck{}
+	nodes := make(map[string]bool)
+	timeout := time.NewTimer(KickTimeout)
+	defer timeout.Stop()
+	for expected == 0 || len(acks) < expected {
+		select {
+		case ack := <-ch:
+			// 重复投递的确认只计一次
+			if nodes[ack.Node] {
+				continue
+			}
+			nodes[ack.Node] = true
+			acks = append(acks, ack)
+		case <-timeout.C:
+			return acks, expected, nil
+		case <-ctx.Done():
+			return acks, expected, nil
+		}
+	}
+	return acks, expected, nil
+}
+
+func kickAckTopic(service string) string {
+	return service + ".kick_ack"
+}
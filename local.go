@@ -0,0 +1,468 @@
+package gochat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	proto "github.com/laoqiu/go-chat/proto"
+	"github.com/micro/go-micro/broker"
+	"github.com/micro/go-micro/client"
+)
+
+var (
+	// ErrStreamClosed 进程内stream已关闭
+	ErrStreamClosed = errors.New("stream已关闭")
+	// ErrStreamFull 客户端读取过慢，缓冲已满时丢弃事件
+	ErrStreamFull = errors.New("stream缓冲已满")
+
+	// 进程内stream的缓冲大小，内存broker同步推送，缓冲满时丢弃而不阻塞其它发送者
+	LocalStreamBuffer = 256
+)
+
+// NewLocalService 进程内直接调用Handler的ChatService，不经过registry及rpc
+func NewLocalService(h *Handler) proto.ChatService {
+	return &localService{h: h}
+}
+
+type localService struct {
+	h *Handler
+}
+
+func (s *localService) Register(ctx context.Context, in *proto.RegisterRequest, opts ...client.CallOption) (*proto.RegisterResponse, error) {
+	out := new(proto.RegisterResponse)
+	if err := s.h.Register(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Unregister(ctx context.Context, in *proto.UnregisterRequest, opts ...client.CallOption) (*proto.UnregisterResponse, error) {
+	out := new(proto.UnregisterResponse)
+	if err := s.h.Unregister(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Users(ctx context.Context, in *proto.UsersRequest, opts ...client.CallOption) (*proto.UsersResponse, error) {
+	out := new(proto.UsersResponse)
+	if err := s.h.Users(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Rooms(ctx context.Context, in *proto.RoomsRequest, opts ...client.CallOption) (*proto.RoomsResponse, error) {
+	out := new(proto.RoomsResponse)
+	if err := s.h.Rooms(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Join(ctx context.Context, in *proto.JoinRequest, opts ...client.CallOption) (*proto.JoinResponse, error) {
+	out := new(proto.JoinResponse)
+	if err := s.h.Join(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Out(ctx context.Context, in *proto.OutRequest, opts ...client.CallOption) (*proto.OutResponse, error) {
+	out := new(proto.OutResponse)
+	if err := s.h.Out(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Send(ctx context.Context, in *proto.SendRequest, opts ...client.CallOption) (*proto.SendResponse, error) {
+	out := new(proto.SendResponse)
+	if err := s.h.Send(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Stream Handler.Stream在goroutine中运行，返回的错误由Recv取得
+func (s *localService) Stream(ctx context.Context, in *proto.StreamRequest, opts ...client.CallOption) (proto.Chat_StreamService, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream := &localStream{
+		ctx:    ctx,
+		cancel: cancel,
+		ch:     make(chan *proto.StreamResponse, LocalStreamBuffer),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		stream.finish(s.h.Stream(ctx, in, stream.server()))
+	}()
+	return stream, nil
+}
+
+func (s *localService) CreateWebhook(ctx context.Context, in *proto.CreateWebhookRequest, opts ...client.CallOption) (*proto.CreateWebhookResponse, error) {
+	out := new(proto.CreateWebhookResponse)
+	if err := s.h.CreateWebhook(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) DeleteWebhook(ctx context.Context, in *proto.DeleteWebhookRequest, opts ...client.CallOption) (*proto.DeleteWebhookResponse, error) {
+	out := new(proto.DeleteWebhookResponse)
+	if err := s.h.DeleteWebhook(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Webhooks(ctx context.Context, in *proto.WebhooksRequest, opts ...client.CallOption) (*proto.WebhooksResponse, error) {
+	out := new(proto.WebhooksResponse)
+	if err := s.h.Webhooks(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) CreateBot(ctx context.Context, in *proto.CreateBotRequest, opts ...client.CallOption) (*proto.CreateBotResponse, error) {
+	out := new(proto.CreateBotResponse)
+	if err := s.h.CreateBot(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) DeleteBot(ctx context.Context, in *proto.DeleteBotRequest, opts ...client.CallOption) (*proto.DeleteBotResponse, error) {
+	out := new(proto.DeleteBotResponse)
+	if err := s.h.DeleteBot(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Bots(ctx context.Context, in *proto.BotsRequest, opts ...client.CallOption) (*proto.BotsResponse, error) {
+	out := new(proto.BotsResponse)
+	if err := s.h.Bots(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) BotSend(ctx context.Context, in *proto.BotSendRequest, opts ...client.CallOption) (*proto.BotSendResponse, error) {
+	out := new(proto.BotSendResponse)
+	if err := s.h.BotSend(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) UploadKeys(ctx context.Context, in *proto.UploadKeysRequest, opts ...client.CallOption) (*proto.UploadKeysResponse, error) {
+	out := new(proto.UploadKeysResponse)
+	if err := s.h.UploadKeys(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) FetchKeys(ctx context.Context, in *proto.FetchKeysRequest, opts ...client.CallOption) (*proto.FetchKeysResponse, error) {
+	out := new(proto.FetchKeysResponse)
+	if err := s.h.FetchKeys(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Kick(ctx context.Context, in *proto.KickRequest, opts ...client.CallOption) (*proto.KickResponse, error) {
+	out := new(proto.KickResponse)
+	if err := s.h.Kick(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Sessions(ctx context.Context, in *proto.SessionsRequest, opts ...client.CallOption) (*proto.SessionsResponse, error) {
+	out := new(proto.SessionsResponse)
+	if err := s.h.Sessions(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) RevokeSession(ctx context.Context, in *proto.RevokeSessionRequest, opts ...client.CallOption) (*proto.RevokeSessionResponse, error) {
+	out := new(proto.RevokeSessionResponse)
+	if err := s.h.RevokeSession(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Receipts(ctx context.Context, in *proto.ReceiptsRequest, opts ...client.CallOption) (*proto.ReceiptsResponse, error) {
+	out := new(proto.ReceiptsResponse)
+	if err := s.h.Receipts(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) History(ctx context.Context, in *proto.HistoryRequest, opts ...client.CallOption) (*proto.HistoryResponse, error) {
+	out := new(proto.HistoryResponse)
+	if err := s.h.History(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Thread(ctx context.Context, in *proto.ThreadRequest, opts ...client.CallOption) (*proto.ThreadResponse, error) {
+	out := new(proto.ThreadResponse)
+	if err := s.h.Thread(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) FollowThread(ctx context.Context, in *proto.FollowThreadRequest, opts ...client.CallOption) (*proto.FollowThreadResponse, error) {
+	out := new(proto.FollowThreadResponse)
+	if err := s.h.FollowThread(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) UnfollowThread(ctx context.Context, in *proto.UnfollowThreadRequest, opts ...client.CallOption) (*proto.UnfollowThreadResponse, error) {
+	out := new(proto.UnfollowThreadResponse)
+	if err := s.h.UnfollowThread(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) React(ctx context.Context, in *proto.ReactRequest, opts ...client.CallOption) (*proto.ReactResponse, error) {
+	out := new(proto.ReactResponse)
+	if err := s.h.React(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Mentions(ctx context.Context, in *proto.MentionsRequest, opts ...client.CallOption) (*proto.MentionsResponse, error) {
+	out := new(proto.MentionsResponse)
+	if err := s.h.Mentions(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Pin(ctx context.Context, in *proto.PinRequest, opts ...client.CallOption) (*proto.PinResponse, error) {
+	out := new(proto.PinResponse)
+	if err := s.h.Pin(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Unpin(ctx context.Context, in *proto.UnpinRequest, opts ...client.CallOption) (*proto.UnpinResponse, error) {
+	out := new(proto.UnpinResponse)
+	if err := s.h.Unpin(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Pinned(ctx context.Context, in *proto.PinnedRequest, opts ...client.CallOption) (*proto.PinnedResponse, error) {
+	out := new(proto.PinnedResponse)
+	if err := s.h.Pinned(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) SetNotice(ctx context.Context, in *proto.SetNoticeRequest, opts ...client.CallOption) (*proto.SetNoticeResponse, error) {
+	out := new(proto.SetNoticeResponse)
+	if err := s.h.SetNotice(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Scheduled(ctx context.Context, in *proto.ScheduledRequest, opts ...client.CallOption) (*proto.ScheduledResponse, error) {
+	out := new(proto.ScheduledResponse)
+	if err := s.h.Scheduled(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) EditScheduled(ctx context.Context, in *proto.EditScheduledRequest, opts ...client.CallOption) (*proto.EditScheduledResponse, error) {
+	out := new(proto.EditScheduledResponse)
+	if err := s.h.EditScheduled(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) CancelScheduled(ctx context.Context, in *proto.CancelScheduledRequest, opts ...client.CallOption) (*proto.CancelScheduledResponse, error) {
+	out := new(proto.CancelScheduledResponse)
+	if err := s.h.CancelScheduled(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) MessageTTL(ctx context.Context, in *proto.MessageTTLRequest, opts ...client.CallOption) (*proto.MessageTTLResponse, error) {
+	out := new(proto.MessageTTLResponse)
+	if err := s.h.MessageTTL(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) SetMessageTTL(ctx context.Context, in *proto.SetMessageTTLRequest, opts ...client.CallOption) (*proto.SetMessageTTLResponse, error) {
+	out := new(proto.SetMessageTTLResponse)
+	if err := s.h.SetMessageTTL(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Members(ctx context.Context, in *proto.MembersRequest, opts ...client.CallOption) (*proto.MembersResponse, error) {
+	out := new(proto.MembersResponse)
+	if err := s.h.Members(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *localService) Presence(ctx context.Context, in *proto.PresenceRequest, opts ...client.CallOption) (*proto.PresenceResponse, error) {
+	out := new(proto.PresenceResponse)
+	if err := s.h.Presence(ctx, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// localStream 连接Handler.Stream与网关的管道，客户端一侧实现Chat_StreamService
+type localStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	ch     chan *proto.StreamResponse
+	done   chan struct{}
+	err    error
+}
+
+func (s *localStream) finish(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	s.err = err
+	close(s.done)
+}
+
+func (s *localStream) server() proto.Chat_StreamStream {
+	return &localServerStream{s}
+}
+
+func (s *localStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func (s *localStream) RecvMsg(m interface{}) error {
+	rsp, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	if out, ok := m.(*proto.StreamResponse); ok {
+		*out = *rsp
+	}
+	return nil
+}
+
+// Close 关闭后Handler在下一次心跳时退出
+func (s *localStream) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *localStream) Recv() (*proto.StreamResponse, error) {
+	select {
+	case rsp := <-s.ch:
+		return rsp, nil
+	case <-s.done:
+		// Handler退出前发送的事件(如kicked)仍在缓冲中，先取完
+		select {
+		case rsp := <-s.ch:
+			return rsp, nil
+		default:
+		}
+		return nil, s.err
+	}
+}
+
+// localServerStream Handler一侧实现Chat_StreamStream
+type localServerStream struct {
+	s *localStream
+}
+
+func (x *localServerStream) SendMsg(m interface{}) error {
+	rsp, ok := m.(*proto.StreamResponse)
+	if !ok {
+		return errors.New("unexpected message type")
+	}
+	return x.Send(rsp)
+}
+
+func (x *localServerStream) RecvMsg(interface{}) error {
+	return nil
+}
+
+func (x *localServerStream) Close() error {
+	return nil
+}
+
+// Send 不阻塞，缓冲满时丢弃，心跳也被丢弃时Conn.Run结束stream
+func (x *localServerStream) Send(rsp *proto.StreamResponse) error {
+	select {
+	case <-x.s.ctx.Done():
+		return ErrStreamClosed
+	default:
+	}
+	select {
+	case x.s.ch <- rsp:
+		return nil
+	default:
+		return ErrStreamFull
+	}
+}
+
+// sharedBroker 多个连接共用的进程内broker，断开连接时只取消自己的订阅
+type sharedBroker struct {
+	broker.Broker
+
+	mu   sync.Mutex
+	subs []broker.Subscriber
+}
+
+func (b *sharedBroker) Connect() error {
+	return nil
+}
+
+func (b *sharedBroker) Disconnect() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		sub.Unsubscribe()
+	}
+	b.subs = nil
+	return nil
+}
+
+func (b *sharedBroker) Subscribe(topic string, h broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	sub, err := b.Broker.Subscribe(topic, h, opts...)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	return sub, nil
+}
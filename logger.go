@@ -0,0 +1,234 @@
+package gochat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// Level 日志级别
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParseLevel 解析debug|info|warn|error
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// Field 结构化日志字段
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// 常用字段
+func UserField(id string) Field       { return F("user", id) }
+func PlatformField(p string) Field    { return F("platform", p) }
+func RoomField(id string) Field       { return F("room", id) }
+func EventIdField(id string) Field    { return F("event_id", id) }
+func ErrField(err error) Field        { return F("err", err) }
+func EventField(e *proto.Event) Field { return F("event", e) }
+
+// Logger 结构化日志，With返回的子Logger与父Logger共享级别等运行时设置
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	// 附加固定字段
+	With(fields ...Field) Logger
+	// 运行时修改日志级别
+	SetLevel(level Level)
+	Level() Level
+	Enabled(level Level) bool
+}
+
+// DefaultLogger 未注入Logger时使用
+var DefaultLogger Logger = NewLogger(os.Stderr, LevelInfo)
+
+func loggerOrDefault(l Logger) Logger {
+	if l == nil {
+		return DefaultLogger
+	}
+	return l
+}
+
+type logState struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  int32
+	bodies int32
+}
+
+// textLogger 输出logfmt格式的单行日志
+type textLogger struct {
+	state  *logState
+	fields []Field
+}
+
+// NewLogger 创建输出到w的Logger，消息内容默认不输出
+func NewLogger(w io.Writer, level Level) *textLogger {
+	return &textLogger{
+		state: &logState{out: w, level: int32(level)},
+	}
+}
+
+// LogBodies 是否输出消息内容，加密消息始终不输出
+func (l *textLogger) LogBodies(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&l.state.bodies, v)
+}
+
+func (l *textLogger) Debug(msg string, fields ...Field) { l.log(LevelDebug, msg, fields) }
+func (l *textLogger) Info(msg string, fields ...Field)  { l.log(LevelInfo, msg, fields) }
+func (l *textLogger) Warn(msg string, fields ...Field)  { l.log(LevelWarn, msg, fields) }
+func (l *textLogger) Error(msg string, fields ...Field) { l.log(LevelError, msg, fields) }
+
+func (l *textLogger) With(fields ...Field) Logger {
+	f := make([]Field, 0, len(l.fields)+len(fields))
+	f = append(f, l.fields...)
+	return &textLogger{
+		state:  l.state,
+		fields: append(f, fields...),
+	}
+}
+
+func (l *textLogger) SetLevel(level Level) {
+	atomic.StoreInt32(&l.state.level, int32(level))
+}
+
+func (l *textLogger) Level() Level {
+	return Level(atomic.LoadInt32(&l.state.level))
+}
+
+func (l *textLogger) Enabled(level Level) bool {
+	return level >= l.Level()
+}
+
+func (l *textLogger) log(level Level, msg string, fields []Field) {
+	if !l.Enabled(level) {
+		return
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString(time.Now().Format("2006-01-02T15:04:05.000Z07:00"))
+	buf.WriteString(" level=" + level.String())
+	buf.WriteString(" msg=" + quote(msg))
+	bodies := atomic.LoadInt32(&l.state.bodies) == 1
+	for _, f := range l.fields {
+		writeField(buf, f, bodies)
+	}
+	for _, f := range fields {
+		writeField(buf, f, bodies)
+	}
+	buf.WriteByte('\n')
+
+	l.state.mu.Lock()
+	l.state.out.Write(buf.Bytes())
+	l.state.mu.Unlock()
+}
+
+func writeField(buf *bytes.Buffer, f Field, bodies bool) {
+	switch v := f.Value.(type) {
+	case *proto.Event:
+		if v == nil {
+			buf.WriteString(" " + f.Key + "=nil")
+			return
+		}
+		// 事件展开为元数据字段，内容默认脱敏
+		writeField(buf, EventIdField(v.Id), bodies)
+		writeField(buf, F("type", v.Type), bodies)
+		writeField(buf, F("from", v.From), bodies)
+		writeField(buf, F("to", v.To), bodies)
+		if bodies && v.Type != EncryptedEvent {
+			writeField(buf, F("body", v.Body), bodies)
+		} else {
+			writeField(buf, F("body", fmt.Sprintf("[redacted %dB]", len(v.Body))), bodies)
+		}
+	case error:
+		buf.WriteString(" " + f.Key + "=" + quote(v.Error()))
+	case string:
+		buf.WriteString(" " + f.Key + "=" + quote(v))
+	default:
+		buf.WriteString(" " + f.Key + "=" + quote(fmt.Sprint(v)))
+	}
+}
+
+func quote(s string) string {
+	if len(s) == 0 || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// NopLogger 丢弃所有日志
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, fields ...Field) {}
+func (NopLogger) Info(msg string, fields ...Field)  {}
+func (NopLogger) Warn(msg string, fields ...Field)  {}
+func (NopLogger) Error(msg string, fields ...Field) {}
+func (n NopLogger) With(fields ...Field) Logger     { return n }
+func (NopLogger) SetLevel(level Level)              {}
+func (NopLogger) Level() Level                      { return LevelError + 1 }
+func (NopLogger) Enabled(level Level) bool          { return false }
+
+// NewLogLevelHandler 运行时查看(GET)及修改(POST level=debug)日志级别
+func NewLogLevelHandler(l Logger) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" || r.Method == "PUT" {
+			level, err := ParseLevel(r.FormValue("level"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			l.SetLevel(level)
+		}
+		w.Write([]byte(l.Level().String() + "\n"))
+	}
+}
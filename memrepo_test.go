@@ -0,0 +1,17 @@
+package gochat_test
+
+import (
+	"testing"
+
+	gochat "github.com/laoqiu/go-chat"
+	"github.com/laoqiu/go-chat/repotest"
+)
+
+func TestMemRepo(t *testing.T) {
+	repotest.Repository(t, gochat.NewMemRepo())
+}
+
+// 同一平台第二次登录踢掉第一个连接，其它平台的连接保持在线
+func TestMemRepoSessions(t *testing.T) {
+	repotest.Sessions(t, gochat.NewMemRepo())
+}
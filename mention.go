@@ -0,0 +1,260 @@
+package gochat
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+const (
+	// 提及方式
+	MentionUser = "user"
+	MentionAll  = "all"
+	MentionHere = "here"
+
+	// 被@的接收者收到的消息优先级
+	PriorityHigh = "high"
+)
+
+var ErrMentionAll = errors.New("只有管理员可以@all")
+
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([\w.\-]+)`)
+
+// parseMentions 取出消息中@的用户，@all及@here单独返回
+func parseMentions(body string) (users []string, all, here bool) {
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		switch m[1] {
+		case MentionAll:
+			all = true
+		case MentionHere:
+			here = true
+		default:
+			if !in(users, m[1]) {
+				users = append(users, m[1])
+			}
+		}
+	}
+	return users, all, here
+}
+
+// Mention 用户被提及的记录，对应mentions
+type Mention struct {
+	Id      int64  `db:"id"`
+	UserId  string `db:"user_id"`
+	EventId string `db:"event_id"`
+	RoomId  string `db:"room_id"`
+	Sender  string `db:"sender"`
+	Body    string `db:"body"`
+	Kind    string `db:"kind"`
+	Created int64  `db:"created"`
+	IsRead  bool   `db:"is_read"`
+}
+
+func (m *Mention) ToProto() *proto.Mention {
+	return &proto.Mention{
+		Id: m.Id,
+		Event: &proto.Event{
+			Id:       m.EventId,
+			Type:     "message",
+			From:     m.Sender,
+			To:       m.RoomId + "/",
+			Body:     m.Body,
+			Created:  m.Created,
+			Priority: PriorityHigh,
+		},
+		Kind: m.Kind,
+		Read: m.IsRead,
+	}
+}
+
+type MentionRepository interface {
+	// 记录提及，重复记录忽略
+	AddMentions(mentions []*Mention) error
+	// 用户收到的提及，按时间倒序，before为0时从最新开始
+	Mentions(uid string, before int64, limit int, unread bool) ([]*Mention, error)
+	// 标记消息中对用户的提及为已读
+	ReadMention(uid, eventId string) error
+	// 删除消息的所有提及，用于消息过期
+	DeleteMentions(eventId string) error
+}
+
+func NewMentionRepo(db *sqlx.DB) *mentionRepo {
+	return &mentionRepo{
+		db:      db,
+		dialect: DialectOf(db),
+	}
+}
+
+type mentionRepo struct {
+	db      *sqlx.DB
+	dialect Dialect
+}
+
+func (r *mentionRepo) AddMentions(mentions []*Mention) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := tx.Rebind(r.dialect.insertIgnore("mentions",
+		[]string{"user_id", "event_id", "room_id", "sender", "body", "kind", "created"}))
+	for _, m := range mentions {
+		if _, err := tx.Exec(query, m.UserId, m.EventId, m.RoomId, m.Sender, m.Body, m.Kind, m.Created); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *mentionRepo) Mentions(uid string, before int64, limit int, unread bool) ([]*Mention, error) {
+	mentions := []*Mention{}
+	query := `SELECT id, user_id, event_id, room_id, sender, body, kind, created, is_read FROM mentions WHERE user_id = ?`
+	args := []interface{}{uid}
+	if before > 0 {
+		query += ` AND id < ?`
+		args = append(args, before)
+	}
+	if unread {
+		query += ` AND is_read = ?`
+		args = append(args, false)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+	err := r.db.Select(&mentions, r.db.Rebind(query), args...)
+	return mentions, err
+}
+
+func (r *mentionRepo) ReadMention(uid, eventId string) error {
+	_, err := r.db.Exec(r.db.Rebind(`
+		UPDATE mentions SET is_read = ? WHERE user_id = ? AND event_id = ?
+		`), true, uid, eventId)
+	return err
+}
+
+func (r *mentionRepo) DeleteMentions(eventId string) error {
+	_, err := r.db.Exec(r.db.Rebind(`DELETE FROM mentions WHERE event_id = ?`), eventId)
+	return err
+}
+
+// Mentions 提及收件箱
+func (h *Handler) Mentions(ctx context.Context, req *proto.MentionsRequest, rsp *proto.MentionsResponse) error {
+	if h.opts.Mentions == nil {
+		return errors.New("提及未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	mentions, err := h.opts.Mentions.Mentions(req.Id, req.Before, historyLimit(req.Limit), req.Unread)
+	if err != nil {
+		return err
+	}
+	for _, m := range mentions {
+		rsp.Mentions = append(rsp.Mentions, m.ToProto())
+	}
+	return nil
+}
+
+// mentions 解析房间消息中的提及，设置event.Mentions，返回被提及的成员及提及方式
+// @all只有管理员可用，@here只提及当前在线的成员，发送者自己不算
+func (h *Handler) mentions(event *proto.Event, roomId string) (map[string]string, error) {
+	users, all, here := parseMentions(event.Body)
+	if len(users) == 0 && !all && !here {
+		return nil, nil
+	}
+	members, err := h.repo.Members(roomId, false)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		managers, err := h.repo.Members(roomId, true)
+		if err != nil {
+			return nil, err
+		}
+		if !in(userIds(managers), event.From) {
+			return nil, ErrMentionAll
+		}
+	}
+
+	ids := userIds(members)
+	mentioned := make(map[string]string)
+	switch {
+	case all:
+		for _, id := range ids {
+			mentioned[id] = MentionAll
+		}
+	case here:
+		online, err := h.repo.OnlineUsers(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range online {
+			mentioned[id] = MentionHere
+		}
+	}
+	for _, id := range users {
+		if in(ids, id) {
+			mentioned[id] = MentionUser
+		}
+	}
+	delete(mentioned, event.From)
+
+	event.Mentions = nil
+	for _, id := range users {
+		if _, ok := mentioned[id]; ok {
+			event.Mentions = append(event.Mentions, id)
+		}
+	}
+	if all {
+		event.Mentions = append(event.Mentions, MentionAll)
+	}
+	if here {
+		event.Mentions = append(event.Mentions, MentionHere)
+	}
+	return mentioned, nil
+}
+
+// saveMentions 记录到被提及用户的收件箱，失败不影响发送
+func (h *Handler) saveMentions(event *proto.Event, roomId string, mentioned map[string]string) {
+	if h.opts.Mentions == nil || len(mentioned) == 0 {
+		return
+	}
+	mentions := []*Mention{}
+	for uid, kind := range mentioned {
+		mentions = append(mentions, &Mention{
+			UserId:  uid,
+			EventId: event.Id,
+			RoomId:  roomId,
+			Sender:  event.From,
+			Body:    event.Body,
+			Kind:    kind,
+			Created: event.Created,
+		})
+	}
+	if err := h.opts.Mentions.AddMentions(mentions); err != nil {
+		h.opts.Logger.Warn("mentions save failed", EventIdField(event.Id), RoomField(roomId), ErrField(err))
+	}
+}
+
+// readMentions 已读回执同时标记提及为已读
+func (h *Handler) readMentions(uid, body string) {
+	if h.opts.Mentions == nil {
+		return
+	}
+	for _, id := range splitIds(body) {
+		if err := h.opts.Mentions.ReadMention(uid, id); err != nil {
+			h.opts.Logger.Warn("mention read failed", UserField(uid), EventIdField(id), ErrField(err))
+		}
+	}
+}
+
+func userIds(users []*proto.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.Id)
+	}
+	return ids
+}
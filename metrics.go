@@ -0,0 +1,176 @@
+package gochat
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/micro/go-micro/broker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics prometheus指标，nil时所有记录方法均为空操作
+type Metrics struct {
+	// websocket网关当前连接数
+	Connections *prometheus.GaugeVec
+	// Hub当前stream数
+	Sessions *prometheus.GaugeVec
+	// Send接收的事件数
+	Events *prometheus.CounterVec
+	// 错误数，reason如rate_limited、filtered、publish、ack
+	Errors *prometheus.CounterVec
+	// Send中向成员逐个publish的耗时
+	Fanout *prometheus.HistogramVec
+	// 从Event.Created到推送给stream的耗时
+	DeliveryLatency *prometheus.HistogramVec
+	// 持久化队列ack及重投次数
+	Acks         prometheus.Counter
+	Redeliveries prometheus.Counter
+}
+
+// NewMetrics 创建并注册指标，reg为空时注册到prometheus.DefaultRegisterer
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	m := &Metrics{
+		Connections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "gochat",
+			Name:      "websocket_connections",
+			Help:      "Active websocket connections by platform.",
+		}, []string{"platform"}),
+		Sessions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "gochat",
+			Name:      "hub_sessions",
+			Help:      "Active hub stream sessions by platform.",
+		}, []string{"platform"}),
+		Events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gochat",
+			Name:      "events_total",
+			Help:      "Events received by Send, by type.",
+		}, []string{"type"}),
+		Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gochat",
+			Name:      "errors_total",
+			Help:      "Errors by reason.",
+		}, []string{"reason"}),
+		Fanout: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gochat",
+			Name:      "publish_fanout_seconds",
+			Help:      "Time spent publishing an event to all recipients.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"kind"}),
+		DeliveryLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gochat",
+			Name:      "delivery_latency_seconds",
+			Help:      "Time from event creation to stream delivery. Event.created has second resolution.",
+			Buckets:   []float64{1, 2, 5, 10, 30, 60, 300, 1800, 3600, 86400},
+		}, []string{"platform"}),
+		Acks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gochat",
+			Name:      "nats_acks_total",
+			Help:      "Messages acknowledged on durable subscriptions.",
+		}),
+		Redeliveries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gochat",
+			Name:      "nats_redeliveries_total",
+			Help:      "Messages redelivered on durable subscriptions.",
+		}),
+	}
+	reg.MustRegister(
+		m.Connections,
+		m.Sessions,
+		m.Events,
+		m.Errors,
+		m.Fanout,
+		m.DeliveryLatency,
+		m.Acks,
+		m.Redeliveries,
+	)
+	return m
+}
+
+// MetricsHandler /metrics接口
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+func (m *Metrics) connected(platform string, delta float64) {
+	if m == nil {
+		return
+	}
+	m.Connections.WithLabelValues(platform).Add(delta)
+}
+
+func (m *Metrics) session(platform string, delta float64) {
+	if m == nil {
+		return
+	}
+	m.Sessions.WithLabelValues(platform).Add(delta)
+}
+
+func (m *Metrics) event(eventType string) {
+	if m == nil {
+		return
+	}
+	m.Events.WithLabelValues(eventType).Inc()
+}
+
+func (m *Metrics) error(reason string) {
+	if m == nil {
+		return
+	}
+	m.Errors.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) fanout(kind string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.Fanout.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) delivered(platform string, created int64) {
+	if m == nil || created <= 0 {
+		return
+	}
+	m.DeliveryLatency.WithLabelValues(platform).Observe(time.Since(time.Unix(created, 0)).Seconds())
+}
+
+func (m *Metrics) acked() {
+	if m == nil {
+		return
+	}
+	m.Acks.Inc()
+}
+
+func (m *Metrics) redelivered() {
+	if m == nil {
+		return
+	}
+	m.Redeliveries.Inc()
+}
+
+// isRedelivered nats-streaming消息的Redelivered标记，无法读取时为false
+// broker插件的publication未导出*stan.Msg，按字段类型通过反射读取
+func isRedelivered(p broker.Publication) bool {
+	if r, ok := p.(interface{ Redelivered() bool }); ok {
+		return r.Redelivered()
+	}
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() || f.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if r := f.Elem().FieldByName("Redelivered"); r.IsValid() && r.Kind() == reflect.Bool {
+			return r.Bool()
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package gochat_test
+
+import (
+	"testing"
+
+	gochat "github.com/laoqiu/go-chat"
+	"github.com/micro/go-micro/broker"
+)
+
+// stanMsg 与stan.Msg一样通过内嵌的MsgProto提供Redelivered
+type msgProto struct {
+	Sequence    uint64
+	Redelivered bool
+}
+
+type stanMsg struct {
+	msgProto
+}
+
+// stanPublication 与nats-streaming插件一样不导出*stan.Msg
+type stanPublication struct {
+	topic string
+	m     *broker.Message
+	msg   *stanMsg
+}
+
+func (p *stanPublication) Topic() string            { return p.topic }
+func (p *stanPublication) Message() *broker.Message { return p.m }
+func (p *stanPublication) Ack() error               { return nil }
+
+func TestIsRedelivered(t *testing.T) {
+	m := &broker.Message{}
+	for _, redelivered := range []bool{false, true} {
+		p := &stanPublication{m: m, msg: &stanMsg{msgProto{Redelivered: redelivered}}}
+		if got := gochat.IsRedelivered(p); got != redelivered {
+			t.Fatalf("IsRedelivered = %v, want %v", got, redelivered)
+		}
+	}
+	// 其他broker及未设置消息时不算重投
+	if gochat.IsRedelivered(&stanPublication{m: m}) {
+		t.Fatal("IsRedelivered without stan message")
+	}
+}
@@ -0,0 +1,305 @@
+package gochat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// 多个srv同时启动时只有一个执行迁移
+	migrateLock        = "gochat_schema_migrations"
+	migrateLockTimeout = 60
+)
+
+var ErrMigrateLocked = errors.New("获取迁移锁超时")
+
+// Migration 一个数据库版本，Up/Down按顺序执行
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// MigrationStatus 迁移状态
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	Applied bool
+	Created time.Time
+}
+
+type appliedMigration struct {
+	Version int64     `db:"version"`
+	Name    string    `db:"name"`
+	Created time.Time `db:"created"`
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	dialect    Dialect
+	migrations []Migration
+	log        Logger
+}
+
+func NewMigrator(db *sqlx.DB, migrations []Migration, logger Logger) *Migrator {
+	m := make([]Migration, len(migrations))
+	copy(m, migrations)
+	sort.Slice(m, func(i, j int) bool {
+		return m[i].Version < m[j].Version
+	})
+	return &Migrator{
+		db:         db,
+		dialect:    DialectOf(db),
+		migrations: m,
+		log:        loggerOrDefault(logger),
+	}
+}
+
+// Up 执行所有未执行的迁移
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			m.log.Info("migrate up", F("version", mg.Version), F("name", mg.Name))
+			if err := m.exec(ctx, conn, mg.Up, `
+				INSERT INTO schema_migrations (version, name) VALUES (?, ?)
+				`, mg.Version, mg.Name); err != nil {
+				return fmt.Errorf("migration %d %s: %v", mg.Version, mg.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down 回滚最近执行的steps个迁移
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			m.log.Info("migrate down", F("version", mg.Version), F("name", mg.Name))
+			if err := m.exec(ctx, conn, mg.Down, `
+				DELETE FROM schema_migrations WHERE version = ?
+				`, mg.Version); err != nil {
+				return fmt.Errorf("migration %d %s: %v", mg.Version, mg.Name, err)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status 所有迁移及其执行状态
+func (m *Migrator) Status(ctx context.Context) ([]*MigrationStatus, error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	status := []*MigrationStatus{}
+	for _, mg := range m.migrations {
+		s := &MigrationStatus{Version: mg.Version, Name: mg.Name}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied = true
+			s.Created = a.Created
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// locked 在同一个连接上持有数据库锁执行fn
+// mysql使用GET_LOCK，postgres使用advisory lock，sqlite由文件锁保证写入串行
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.dialect {
+	case MySQL:
+		var ok int
+		if err := conn.GetContext(ctx, &ok, `SELECT GET_LOCK(?, ?)`, migrateLock, migrateLockTimeout); err != nil {
+			return err
+		}
+		if ok != 1 {
+			return ErrMigrateLocked
+		}
+		defer conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, migrateLock)
+	case Postgres:
+		if err := m.advisoryLock(ctx, conn); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, migrateLock)
+	}
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// advisoryLock 与GET_LOCK一致，最多等待migrateLockTimeout秒
+func (m *Migrator) advisoryLock(ctx context.Context, conn *sqlx.Conn) error {
+	deadline := time.Now().Add(migrateLockTimeout * time.Second)
+	for {
+		var ok bool
+		if err := conn.GetContext(ctx, &ok, `SELECT pg_try_advisory_lock(hashtext($1))`, migrateLock); err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrateLocked
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sqlx.Conn) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	if m.dialect == MySQL {
+		query = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL COMMENT '版本号',
+		name VARCHAR(100) NOT NULL COMMENT '名称',
+		created DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '执行时间',
+		PRIMARY KEY (version)
+	);`
+	}
+	_, err := conn.ExecContext(ctx, query)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int64]*appliedMigration, error) {
+	rows := []*appliedMigration{}
+	if err := conn.SelectContext(ctx, &rows, `SELECT version, name, created FROM schema_migrations`); err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]*appliedMigration)
+	for _, r := range rows {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// exec 执行一个版本的语句并记录版本
+// mysql的DDL会隐式提交，因此不放在事务中，中断后重新执行时跳过已生效的ALTER语句;
+// sqlite及postgres的DDL支持事务
+func (m *Migrator) exec(ctx context.Context, conn *sqlx.Conn, stmts []string, record string, args ...interface{}) error {
+	if m.dialect == MySQL {
+		for _, s := range stmts {
+			done, err := m.altered(ctx, conn, s)
+			if err != nil {
+				return err
+			}
+			if done {
+				m.log.Info("migrate skip applied statement", F("statement", s))
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, s); err != nil {
+				return err
+			}
+		}
+		_, err := conn.ExecContext(ctx, conn.Rebind(record), args...)
+		return err
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, s := range stmts {
+		if _, err := tx.ExecContext(ctx, s); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(record), args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+var (
+	alterTablePattern = regexp.MustCompile(`(?is)^\s*ALTER\s+TABLE\s+(\w+)\s`)
+	addColumnPattern  = regexp.MustCompile(`(?i)\bADD\s+COLUMN\s+(\w+)`)
+	addIndexPattern   = regexp.MustCompile(`(?i)\bADD\s+(?:UNIQUE\s+)?(?:INDEX|KEY)\s+(\w+)`)
+	dropColumnPattern = regexp.MustCompile(`(?i)\bDROP\s+COLUMN\s+(\w+)`)
+	dropIndexPattern  = regexp.MustCompile(`(?i)\bDROP\s+(?:INDEX|KEY)\s+(\w+)`)
+)
+
+// altered mysql的ALTER TABLE语句是否已生效，按information_schema检查增删的列及索引
+// 单条ALTER TABLE是原子的，所有ADD的列及索引都已存在且DROP的都已不存在即为已执行
+// 其他语句(CREATE TABLE IF NOT EXISTS、DELETE等)本身可重复执行，返回false
+func (m *Migrator) altered(ctx context.Context, conn *sqlx.Conn, stmt string) (bool, error) {
+	match := alterTablePattern.FindStringSubmatch(stmt)
+	if match == nil {
+		return false, nil
+	}
+	table := match[1]
+	checks := []struct {
+		pattern *regexp.Regexp
+		query   string
+		exists  bool
+	}{
+		{addColumnPattern, `SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`, true},
+		{addIndexPattern, `SELECT COUNT(*) FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?`, true},
+		{dropColumnPattern, `SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`, false},
+		{dropIndexPattern, `SELECT COUNT(*) FROM information_schema.STATISTICS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?`, false},
+	}
+	found := false
+	for _, c := range checks {
+		for _, name := range c.pattern.FindAllStringSubmatch(stmt, -1) {
+			found = true
+			var n int
+			if err := conn.GetContext(ctx, &n, c.query, table, name[1]); err != nil {
+				return false, err
+			}
+			if (n > 0) != c.exists {
+				return false, nil
+			}
+		}
+	}
+	return found, nil
+}
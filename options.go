@@ -0,0 +1,136 @@
+package gochat
+
+import (
+	"github.com/micro/go-micro/broker"
+)
+
+type Options struct {
+	// 事件回调，为空时不投递webhook
+	Webhooks *Webhooks
+	// 机器人，为空时不处理斜杠命令
+	Bots BotRepository
+	// 发送频率限制，为空时不限制
+	RateLimiter *RateLimiter
+	// 内容过滤，为空时不过滤
+	Filters *Filters
+	// 端到端加密公钥目录，为空时不提供密钥服务
+	Keys KeyRepository
+	// 消息回执，为空时不跟踪送达及已读状态
+	Receipts ReceiptRepository
+	// 消息历史，为空时不记录历史且不支持话题
+	History HistoryRepository
+	// 提及收件箱，为空时只标记消息优先级不记录
+	Mentions MentionRepository
+	// 定时消息，为空时不支持SendAt
+	Schedules ScheduleRepository
+	// 日志，为空时使用DefaultLogger
+	Logger Logger
+	// prometheus指标，为空时不记录
+	Metrics *Metrics
+	// 每个连接使用的broker，为空时连接nats-streaming
+	ConnBroker func(clientId string) (broker.Broker, error)
+	// 集群节点数，用于确认强制下线已在所有节点执行，为空时等待KickTimeout
+	Nodes func() (int, error)
+}
+
+type Option func(*Options)
+
+// WithWebhooks 设置Handler使用的webhook投递
+func WithWebhooks(w *Webhooks) Option {
+	return func(o *Options) {
+		o.Webhooks = w
+	}
+}
+
+// WithBots 启用机器人及斜杠命令路由
+func WithBots(r BotRepository) Option {
+	return func(o *Options) {
+		o.Bots = r
+	}
+}
+
+// WithRateLimiter 启用发送频率限制
+func WithRateLimiter(r *RateLimiter) Option {
+	return func(o *Options) {
+		o.RateLimiter = r
+	}
+}
+
+// WithFilters 启用消息内容过滤
+func WithFilters(f *Filters) Option {
+	return func(o *Options) {
+		o.Filters = f
+	}
+}
+
+// WithKeys 启用端到端加密公钥目录
+func WithKeys(r KeyRepository) Option {
+	return func(o *Options) {
+		o.Keys = r
+	}
+}
+
+// WithReceipts 启用消息送达及已读回执
+func WithReceipts(r ReceiptRepository) Option {
+	return func(o *Options) {
+		o.Receipts = r
+	}
+}
+
+// WithHistory 启用消息历史及话题回复
+func WithHistory(r HistoryRepository) Option {
+	return func(o *Options) {
+		o.History = r
+	}
+}
+
+// WithSchedules 启用定时消息，需同时运行Scheduler
+func WithSchedules(r ScheduleRepository) Option {
+	return func(o *Options) {
+		o.Schedules = r
+	}
+}
+
+// WithMentions 启用提及收件箱
+func WithMentions(r MentionRepository) Option {
+	return func(o *Options) {
+		o.Mentions = r
+	}
+}
+
+// WithLogger 设置日志
+func WithLogger(l Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithMetrics 启用prometheus指标
+func WithMetrics(m *Metrics) Option {
+	return func(o *Options) {
+		o.Metrics = m
+	}
+}
+
+// WithConnBroker 设置连接订阅消息使用的broker，嵌入模式下使用进程内broker
+func WithConnBroker(fn func(clientId string) (broker.Broker, error)) Option {
+	return func(o *Options) {
+		o.ConnBroker = fn
+	}
+}
+
+// WithNodes 设置查询集群节点数的方法
+func WithNodes(fn func() (int, error)) Option {
+	return func(o *Options) {
+		o.Nodes = fn
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.Logger = loggerOrDefault(o.Logger)
+	return o
+}
@@ -0,0 +1,241 @@
+package gochat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+const (
+	// 房间公告变更，Body为公告内容，为空表示清除
+	NoticeEvent = "notice"
+	// 置顶及取消置顶，Body为消息id
+	PinEvent   = "pin"
+	UnpinEvent = "unpin"
+	// 加入房间时推送当前的置顶消息，Body为PinnedResponse
+	PinnedEvent = "pinned"
+)
+
+// 推送给客户端的房间系统事件
+var roomEvents = []string{NoticeEvent, PinEvent, UnpinEvent, PinnedEvent, ExpireEvent, TTLEvent}
+
+var (
+	ErrNotManager  = errors.New("只有管理员可以操作")
+	ErrTooManyPins = errors.New("置顶消息已达上限")
+	ErrNoticeSize  = errors.New("公告内容过长")
+
+	// 每个房间最多置顶的消息数
+	MaxPins = 50
+	// 公告最大字符数
+	MaxNoticeLength = 1000
+)
+
+// Pin 锁定房间行后插入并计数，并发置顶不会超过max
+func (r *historyRepo) Pin(roomId, eventId, uid string, max int) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, tx.Rebind(`SELECT id FROM chatgroup WHERE id = ?`+r.dialect.forUpdate()), roomId); err != nil {
+		return false, notFound(err)
+	}
+	result, err := tx.Exec(tx.Rebind(r.dialect.insertIgnore("room_pins",
+		[]string{"room_id", "event_id", "pinned_by", "created"})), roomId, eventId, uid, time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	var count int
+	if err := tx.Get(&count, tx.Rebind(`SELECT COUNT(*) FROM room_pins WHERE room_id = ?`), roomId); err != nil {
+		return false, err
+	}
+	if count > max {
+		return false, ErrTooManyPins
+	}
+	return true, tx.Commit()
+}
+
+func (r *historyRepo) Unpin(roomId, eventId string) (bool, error) {
+	result, err := r.db.Exec(r.db.Rebind(`
+		DELETE FROM room_pins WHERE room_id = ? AND event_id = ?
+		`), roomId, eventId)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *historyRepo) Pins(roomId string) ([]*Message, error) {
+	msgs := []*Message{}
+	err := r.db.Select(&msgs, r.db.Rebind(`
+		SELECT m.id, m.event_id, m.type, m.conversation, m.room_id, m.sender, m.recipient, m.body,
+			m.parent_id, m.reply_count, m.last_reply, m.created, m.expires
+		FROM room_pins AS p JOIN messages AS m ON m.event_id = p.event_id
+		WHERE p.room_id = ? AND (m.expires = 0 OR m.expires > ?) ORDER BY p.id DESC
+		`), roomId, time.Now().Unix())
+	return msgs, err
+}
+
+// Pin 置顶房间消息，只有管理员可以操作
+func (h *Handler) Pin(ctx context.Context, req *proto.PinRequest, rsp *proto.PinResponse) error {
+	msg, err := h.pinMessage(req.Id, req.RoomId, req.MessageId)
+	if err != nil {
+		return err
+	}
+	changed, err := h.opts.History.Pin(req.RoomId, msg.EventId, req.Id, MaxPins)
+	if err != nil || !changed {
+		return err
+	}
+	h.roomEvent(ctx, req.RoomId, &proto.Event{Type: PinEvent, From: req.Id, Body: msg.EventId})
+	return nil
+}
+
+// Unpin 取消置顶，只有管理员可以操作
+func (h *Handler) Unpin(ctx context.Context, req *proto.UnpinRequest, rsp *proto.UnpinResponse) error {
+	if h.opts.History == nil {
+		return errors.New("消息历史未启用")
+	}
+	if err := h.manager(req.Id, req.RoomId); err != nil {
+		return err
+	}
+	changed, err := h.opts.History.Unpin(req.RoomId, req.MessageId)
+	if err != nil || !changed {
+		return err
+	}
+	h.roomEvent(ctx, req.RoomId, &proto.Event{Type: UnpinEvent, From: req.Id, Body: req.MessageId})
+	return nil
+}
+
+// Pinned 房间公告及置顶消息，成员可见
+func (h *Handler) Pinned(ctx context.Context, req *proto.PinnedRequest, rsp *proto.PinnedResponse) error {
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	if err := h.member(req.Id, req.RoomId); err != nil {
+		return err
+	}
+	return h.pinned(req.Id, req.RoomId, rsp)
+}
+
+// SetNotice 设置房间公告，以系统事件推送给所有成员
+func (h *Handler) SetNotice(ctx context.Context, req *proto.SetNoticeRequest, rsp *proto.SetNoticeResponse) error {
+	if utf8.RuneCountInString(req.Body) > MaxNoticeLength {
+		return ErrNoticeSize
+	}
+	if err := h.manager(req.Id, req.RoomId); err != nil {
+		return err
+	}
+	notice := &proto.Notice{Body: req.Body, From: req.Id, Updated: time.Now().Unix()}
+	if err := h.repo.SetNotice(req.RoomId, notice); err != nil {
+		return err
+	}
+	rsp.Notice = notice
+	h.roomEvent(ctx, req.RoomId, &proto.Event{Type: NoticeEvent, From: req.Id, Body: req.Body})
+	return nil
+}
+
+// pinMessage 检查操作者是管理员且消息属于该房间
+func (h *Handler) pinMessage(uid, roomId, messageId string) (*Message, error) {
+	msg, err := h.message(uid, messageId)
+	if err != nil {
+		return nil, err
+	}
+	if msg.RoomId != roomId {
+		return nil, ErrNotFound
+	}
+	if err := h.manager(uid, roomId); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (h *Handler) pinned(uid, roomId string, rsp *proto.PinnedResponse) error {
+	notice, err := h.repo.Notice(roomId)
+	if err != nil {
+		return err
+	}
+	rsp.Notice = notice
+	if h.opts.History == nil {
+		return nil
+	}
+	pins, err := h.opts.History.Pins(roomId)
+	if err != nil {
+		return err
+	}
+	for _, m := range pins {
+		rsp.Pins = append(rsp.Pins, m.ToProto())
+	}
+	return h.withReactions(uid, rsp.Pins)
+}
+
+// welcome 新成员加入后推送当前公告及置顶消息，失败只记录日志
+func (h *Handler) welcome(ctx context.Context, uid, roomId string) {
+	rsp := &proto.PinnedResponse{}
+	if err := h.pinned(uid, roomId, rsp); err != nil {
+		h.opts.Logger.Warn("pinned query failed", UserField(uid), RoomField(roomId), ErrField(err))
+		return
+	}
+	events := []*proto.Event{}
+	if len(rsp.Notice.Body) > 0 {
+		events = append(events, &proto.Event{Type: NoticeEvent, From: rsp.Notice.From, Body: rsp.Notice.Body, Created: rsp.Notice.Updated})
+	}
+	if len(rsp.Pins) > 0 {
+		body, _ := json.Marshal(rsp)
+		events = append(events, &proto.Event{Type: PinnedEvent, Body: string(body)})
+	}
+	for _, e := range events {
+		e.Id = newEventId()
+		e.To = roomId
+		if e.Created == 0 {
+			e.Created = time.Now().Unix()
+		}
+		event, _ := json.Marshal(e)
+		if err := h.publish(ctx, h.service+"."+uid, event); err != nil {
+			h.opts.Logger.Warn("welcome publish failed", UserField(uid), RoomField(roomId), ErrField(err))
+		}
+	}
+}
+
+// roomEvent 推送系统事件给房间所有成员
+func (h *Handler) roomEvent(ctx context.Context, roomId string, e *proto.Event) {
+	e.Id = newEventId()
+	e.To = roomId
+	e.Created = time.Now().Unix()
+	members, err := h.repo.Members(roomId, false)
+	if err != nil {
+		h.opts.Logger.Warn("members query failed", RoomField(roomId), ErrField(err))
+		return
+	}
+	event, _ := json.Marshal(e)
+	for _, m := range members {
+		if err := h.publish(ctx, h.service+"."+m.Id, event); err != nil {
+			h.opts.Logger.Warn(e.Type+" publish failed", UserField(m.Id), RoomField(roomId), ErrField(err))
+		}
+	}
+	h.dispatch(roomId, e)
+}
+
+func (h *Handler) manager(uid, roomId string) error {
+	if len(uid) == 0 {
+		return errors.New("id is required")
+	}
+	managers, err := h.repo.Members(roomId, true)
+	if err != nil {
+		return err
+	}
+	if !in(userIds(managers), uid) {
+		return ErrNotManager
+	}
+	return nil
+}
@@ -24,10 +24,83 @@ It has these top-level messages:
 	SendResponse
 	StreamRequest
 	StreamResponse
+	CreateWebhookRequest
+	CreateWebhookResponse
+	DeleteWebhookRequest
+	DeleteWebhookResponse
+	WebhooksRequest
+	WebhooksResponse
+	CreateBotRequest
+	CreateBotResponse
+	DeleteBotRequest
+	DeleteBotResponse
+	BotsRequest
+	BotsResponse
+	BotSendRequest
+	BotSendResponse
+	UploadKeysRequest
+	UploadKeysResponse
+	FetchKeysRequest
+	FetchKeysResponse
+	KickRequest
+	KickResponse
+	SessionsRequest
+	SessionsResponse
+	RevokeSessionRequest
+	RevokeSessionResponse
+	ReceiptsRequest
+	ReceiptsResponse
+	HistoryRequest
+	HistoryResponse
+	ThreadRequest
+	ThreadResponse
+	FollowThreadRequest
+	FollowThreadResponse
+	UnfollowThreadRequest
+	UnfollowThreadResponse
+	ReactRequest
+	ReactResponse
+	MentionsRequest
+	MentionsResponse
+	PinRequest
+	PinResponse
+	UnpinRequest
+	UnpinResponse
+	PinnedRequest
+	PinnedResponse
+	SetNoticeRequest
+	SetNoticeResponse
+	ScheduledRequest
+	ScheduledResponse
+	EditScheduledRequest
+	EditScheduledResponse
+	CancelScheduledRequest
+	CancelScheduledResponse
+	MessageTTLRequest
+	MessageTTLResponse
+	SetMessageTTLRequest
+	SetMessageTTLResponse
+	MembersRequest
+	MembersResponse
+	PresenceRequest
+	PresenceResponse
 	Event
 	Room
 	User
 	Client
+	Webhook
+	Bot
+	PreKey
+	KeyBundle
+	Session
+	Receipt
+	ReceiptSummary
+	Message
+	Reaction
+	Mention
+	Notice
+	ScheduledMessage
+	Presence
 */
 package go_micro_srv_chat
 
@@ -68,6 +141,36 @@ type ChatService interface {
 	Out(ctx context.Context, in *OutRequest, opts ...client.CallOption) (*OutResponse, error)
 	Send(ctx context.Context, in *SendRequest, opts ...client.CallOption) (*SendResponse, error)
 	Stream(ctx context.Context, in *StreamRequest, opts ...client.CallOption) (Chat_StreamService, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...client.CallOption) (*CreateWebhookResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...client.CallOption) (*DeleteWebhookResponse, error)
+	Webhooks(ctx context.Context, in *WebhooksRequest, opts ...client.CallOption) (*WebhooksResponse, error)
+	CreateBot(ctx context.Context, in *CreateBotRequest, opts ...client.CallOption) (*CreateBotResponse, error)
+	DeleteBot(ctx context.Context, in *DeleteBotRequest, opts ...client.CallOption) (*DeleteBotResponse, error)
+	Bots(ctx context.Context, in *BotsRequest, opts ...client.CallOption) (*BotsResponse, error)
+	BotSend(ctx context.Context, in *BotSendRequest, opts ...client.CallOption) (*BotSendResponse, error)
+	UploadKeys(ctx context.Context, in *UploadKeysRequest, opts ...client.CallOption) (*UploadKeysResponse, error)
+	FetchKeys(ctx context.Context, in *FetchKeysRequest, opts ...client.CallOption) (*FetchKeysResponse, error)
+	Kick(ctx context.Context, in *KickRequest, opts ...client.CallOption) (*KickResponse, error)
+	Sessions(ctx context.Context, in *SessionsRequest, opts ...client.CallOption) (*SessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error)
+	Receipts(ctx context.Context, in *ReceiptsRequest, opts ...client.CallOption) (*ReceiptsResponse, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error)
+	Thread(ctx context.Context, in *ThreadRequest, opts ...client.CallOption) (*ThreadResponse, error)
+	FollowThread(ctx context.Context, in *FollowThreadRequest, opts ...client.CallOption) (*FollowThreadResponse, error)
+	UnfollowThread(ctx context.Context, in *UnfollowThreadRequest, opts ...client.CallOption) (*UnfollowThreadResponse, error)
+	React(ctx context.Context, in *ReactRequest, opts ...client.CallOption) (*ReactResponse, error)
+	Mentions(ctx context.Context, in *MentionsRequest, opts ...client.CallOption) (*MentionsResponse, error)
+	Pin(ctx context.Context, in *PinRequest, opts ...client.CallOption) (*PinResponse, error)
+	Unpin(ctx context.Context, in *UnpinRequest, opts ...client.CallOption) (*UnpinResponse, error)
+	Pinned(ctx context.Context, in *PinnedRequest, opts ...client.CallOption) (*PinnedResponse, error)
+	SetNotice(ctx context.Context, in *SetNoticeRequest, opts ...client.CallOption) (*SetNoticeResponse, error)
+	Scheduled(ctx context.Context, in *ScheduledRequest, opts ...client.CallOption) (*ScheduledResponse, error)
+	EditScheduled(ctx context.Context, in *EditScheduledRequest, opts ...client.CallOption) (*EditScheduledResponse, error)
+	CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...client.CallOption) (*CancelScheduledResponse, error)
+	MessageTTL(ctx context.Context, in *MessageTTLRequest, opts ...client.CallOption) (*MessageTTLResponse, error)
+	SetMessageTTL(ctx context.Context, in *SetMessageTTLRequest, opts ...client.CallOption) (*SetMessageTTLResponse, error)
+	Members(ctx context.Context, in *MembersRequest, opts ...client.CallOption) (*MembersResponse, error)
+	Presence(ctx context.Context, in *PresenceRequest, opts ...client.CallOption) (*PresenceResponse, error)
 }
 
 type chatService struct {
@@ -202,6 +305,306 @@ func (x *chatServiceStream) Recv() (*StreamResponse, error) {
 	return m, nil
 }
 
+func (c *chatService) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...client.CallOption) (*CreateWebhookResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.CreateWebhook", in)
+	out := new(CreateWebhookResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...client.CallOption) (*DeleteWebhookResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.DeleteWebhook", in)
+	out := new(DeleteWebhookResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Webhooks(ctx context.Context, in *WebhooksRequest, opts ...client.CallOption) (*WebhooksResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Webhooks", in)
+	out := new(WebhooksResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) CreateBot(ctx context.Context, in *CreateBotRequest, opts ...client.CallOption) (*CreateBotResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.CreateBot", in)
+	out := new(CreateBotResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) DeleteBot(ctx context.Context, in *DeleteBotRequest, opts ...client.CallOption) (*DeleteBotResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.DeleteBot", in)
+	out := new(DeleteBotResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Bots(ctx context.Context, in *BotsRequest, opts ...client.CallOption) (*BotsResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Bots", in)
+	out := new(BotsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) BotSend(ctx context.Context, in *BotSendRequest, opts ...client.CallOption) (*BotSendResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.BotSend", in)
+	out := new(BotSendResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) UploadKeys(ctx context.Context, in *UploadKeysRequest, opts ...client.CallOption) (*UploadKeysResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.UploadKeys", in)
+	out := new(UploadKeysResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) FetchKeys(ctx context.Context, in *FetchKeysRequest, opts ...client.CallOption) (*FetchKeysResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.FetchKeys", in)
+	out := new(FetchKeysResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Kick(ctx context.Context, in *KickRequest, opts ...client.CallOption) (*KickResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Kick", in)
+	out := new(KickResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Sessions(ctx context.Context, in *SessionsRequest, opts ...client.CallOption) (*SessionsResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Sessions", in)
+	out := new(SessionsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.RevokeSession", in)
+	out := new(RevokeSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Receipts(ctx context.Context, in *ReceiptsRequest, opts ...client.CallOption) (*ReceiptsResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Receipts", in)
+	out := new(ReceiptsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.History", in)
+	out := new(HistoryResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Thread(ctx context.Context, in *ThreadRequest, opts ...client.CallOption) (*ThreadResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Thread", in)
+	out := new(ThreadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) FollowThread(ctx context.Context, in *FollowThreadRequest, opts ...client.CallOption) (*FollowThreadResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.FollowThread", in)
+	out := new(FollowThreadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) UnfollowThread(ctx context.Context, in *UnfollowThreadRequest, opts ...client.CallOption) (*UnfollowThreadResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.UnfollowThread", in)
+	out := new(UnfollowThreadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) React(ctx context.Context, in *ReactRequest, opts ...client.CallOption) (*ReactResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.React", in)
+	out := new(ReactResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Mentions(ctx context.Context, in *MentionsRequest, opts ...client.CallOption) (*MentionsResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Mentions", in)
+	out := new(MentionsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Pin(ctx context.Context, in *PinRequest, opts ...client.CallOption) (*PinResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Pin", in)
+	out := new(PinResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Unpin(ctx context.Context, in *UnpinRequest, opts ...client.CallOption) (*UnpinResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Unpin", in)
+	out := new(UnpinResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Pinned(ctx context.Context, in *PinnedRequest, opts ...client.CallOption) (*PinnedResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Pinned", in)
+	out := new(PinnedResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) SetNotice(ctx context.Context, in *SetNoticeRequest, opts ...client.CallOption) (*SetNoticeResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.SetNotice", in)
+	out := new(SetNoticeResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Scheduled(ctx context.Context, in *ScheduledRequest, opts ...client.CallOption) (*ScheduledResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Scheduled", in)
+	out := new(ScheduledResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) EditScheduled(ctx context.Context, in *EditScheduledRequest, opts ...client.CallOption) (*EditScheduledResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.EditScheduled", in)
+	out := new(EditScheduledResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) CancelScheduled(ctx context.Context, in *CancelScheduledRequest, opts ...client.CallOption) (*CancelScheduledResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.CancelScheduled", in)
+	out := new(CancelScheduledResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) MessageTTL(ctx context.Context, in *MessageTTLRequest, opts ...client.CallOption) (*MessageTTLResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.MessageTTL", in)
+	out := new(MessageTTLResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) SetMessageTTL(ctx context.Context, in *SetMessageTTLRequest, opts ...client.CallOption) (*SetMessageTTLResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.SetMessageTTL", in)
+	out := new(SetMessageTTLResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Members(ctx context.Context, in *MembersRequest, opts ...client.CallOption) (*MembersResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Members", in)
+	out := new(MembersResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatService) Presence(ctx context.Context, in *PresenceRequest, opts ...client.CallOption) (*PresenceResponse, error) {
+	req := c.c.NewRequest(c.name, "Chat.Presence", in)
+	out := new(PresenceResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Chat service
 
 type ChatHandler interface {
@@ -213,6 +616,36 @@ type ChatHandler interface {
 	Out(context.Context, *OutRequest, *OutResponse) error
 	Send(context.Context, *SendRequest, *SendResponse) error
 	Stream(context.Context, *StreamRequest, Chat_StreamStream) error
+	CreateWebhook(context.Context, *CreateWebhookRequest, *CreateWebhookResponse) error
+	DeleteWebhook(context.Context, *DeleteWebhookRequest, *DeleteWebhookResponse) error
+	Webhooks(context.Context, *WebhooksRequest, *WebhooksResponse) error
+	CreateBot(context.Context, *CreateBotRequest, *CreateBotResponse) error
+	DeleteBot(context.Context, *DeleteBotRequest, *DeleteBotResponse) error
+	Bots(context.Context, *BotsRequest, *BotsResponse) error
+	BotSend(context.Context, *BotSendRequest, *BotSendResponse) error
+	UploadKeys(context.Context, *UploadKeysRequest, *UploadKeysResponse) error
+	FetchKeys(context.Context, *FetchKeysRequest, *FetchKeysResponse) error
+	Kick(context.Context, *KickRequest, *KickResponse) error
+	Sessions(context.Context, *SessionsRequest, *SessionsResponse) error
+	RevokeSession(context.Context, *RevokeSessionRequest, *RevokeSessionResponse) error
+	Receipts(context.Context, *ReceiptsRequest, *ReceiptsResponse) error
+	History(context.Context, *HistoryRequest, *HistoryResponse) error
+	Thread(context.Context, *ThreadRequest, *ThreadResponse) error
+	FollowThread(context.Context, *FollowThreadRequest, *FollowThreadResponse) error
+	UnfollowThread(context.Context, *UnfollowThreadRequest, *UnfollowThreadResponse) error
+	React(context.Context, *ReactRequest, *ReactResponse) error
+	Mentions(context.Context, *MentionsRequest, *MentionsResponse) error
+	Pin(context.Context, *PinRequest, *PinResponse) error
+	Unpin(context.Context, *UnpinRequest, *UnpinResponse) error
+	Pinned(context.Context, *PinnedRequest, *PinnedResponse) error
+	SetNotice(context.Context, *SetNoticeRequest, *SetNoticeResponse) error
+	Scheduled(context.Context, *ScheduledRequest, *ScheduledResponse) error
+	EditScheduled(context.Context, *EditScheduledRequest, *EditScheduledResponse) error
+	CancelScheduled(context.Context, *CancelScheduledRequest, *CancelScheduledResponse) error
+	MessageTTL(context.Context, *MessageTTLRequest, *MessageTTLResponse) error
+	SetMessageTTL(context.Context, *SetMessageTTLRequest, *SetMessageTTLResponse) error
+	Members(context.Context, *MembersRequest, *MembersResponse) error
+	Presence(context.Context, *PresenceRequest, *PresenceResponse) error
 }
 
 func RegisterChatHandler(s server.Server, hdlr ChatHandler, opts ...server.HandlerOption) error {
@@ -225,6 +658,36 @@ func RegisterChatHandler(s server.Server, hdlr ChatHandler, opts ...server.Handl
 		Out(ctx context.Context, in *OutRequest, out *OutResponse) error
 		Send(ctx context.Context, in *SendRequest, out *SendResponse) error
 		Stream(ctx context.Context, stream server.Stream) error
+		CreateWebhook(ctx context.Context, in *CreateWebhookRequest, out *CreateWebhookResponse) error
+		DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, out *DeleteWebhookResponse) error
+		Webhooks(ctx context.Context, in *WebhooksRequest, out *WebhooksResponse) error
+		CreateBot(ctx context.Context, in *CreateBotRequest, out *CreateBotResponse) error
+		DeleteBot(ctx context.Context, in *DeleteBotRequest, out *DeleteBotResponse) error
+		Bots(ctx context.Context, in *BotsRequest, out *BotsResponse) error
+		BotSend(ctx context.Context, in *BotSendRequest, out *BotSendResponse) error
+		UploadKeys(ctx context.Context, in *UploadKeysRequest, out *UploadKeysResponse) error
+		FetchKeys(ctx context.Context, in *FetchKeysRequest, out *FetchKeysResponse) error
+		Kick(ctx context.Context, in *KickRequest, out *KickResponse) error
+		Sessions(ctx context.Context, in *SessionsRequest, out *SessionsResponse) error
+		RevokeSession(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error
+		Receipts(ctx context.Context, in *ReceiptsRequest, out *ReceiptsResponse) error
+		History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error
+		Thread(ctx context.Context, in *ThreadRequest, out *ThreadResponse) error
+		FollowThread(ctx context.Context, in *FollowThreadRequest, out *FollowThreadResponse) error
+		UnfollowThread(ctx context.Context, in *UnfollowThreadRequest, out *UnfollowThreadResponse) error
+		React(ctx context.Context, in *ReactRequest, out *ReactResponse) error
+		Mentions(ctx context.Context, in *MentionsRequest, out *MentionsResponse) error
+		Pin(ctx context.Context, in *PinRequest, out *PinResponse) error
+		Unpin(ctx context.Context, in *UnpinRequest, out *UnpinResponse) error
+		Pinned(ctx context.Context, in *PinnedRequest, out *PinnedResponse) error
+		SetNotice(ctx context.Context, in *SetNoticeRequest, out *SetNoticeResponse) error
+		Scheduled(ctx context.Context, in *ScheduledRequest, out *ScheduledResponse) error
+		EditScheduled(ctx context.Context, in *EditScheduledRequest, out *EditScheduledResponse) error
+		CancelScheduled(ctx context.Context, in *CancelScheduledRequest, out *CancelScheduledResponse) error
+		MessageTTL(ctx context.Context, in *MessageTTLRequest, out *MessageTTLResponse) error
+		SetMessageTTL(ctx context.Context, in *SetMessageTTLRequest, out *SetMessageTTLResponse) error
+		Members(ctx context.Context, in *MembersRequest, out *MembersResponse) error
+		Presence(ctx context.Context, in *PresenceRequest, out *PresenceResponse) error
 	}
 	type Chat struct {
 		chat
@@ -299,3 +762,123 @@ func (x *chatStreamStream) RecvMsg(m interface{}) error {
 func (x *chatStreamStream) Send(m *StreamResponse) error {
 	return x.stream.Send(m)
 }
+
+func (h *chatHandler) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, out *CreateWebhookResponse) error {
+	return h.ChatHandler.CreateWebhook(ctx, in, out)
+}
+
+func (h *chatHandler) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, out *DeleteWebhookResponse) error {
+	return h.ChatHandler.DeleteWebhook(ctx, in, out)
+}
+
+func (h *chatHandler) Webhooks(ctx context.Context, in *WebhooksRequest, out *WebhooksResponse) error {
+	return h.ChatHandler.Webhooks(ctx, in, out)
+}
+
+func (h *chatHandler) CreateBot(ctx context.Context, in *CreateBotRequest, out *CreateBotResponse) error {
+	return h.ChatHandler.CreateBot(ctx, in, out)
+}
+
+func (h *chatHandler) DeleteBot(ctx context.Context, in *DeleteBotRequest, out *DeleteBotResponse) error {
+	return h.ChatHandler.DeleteBot(ctx, in, out)
+}
+
+func (h *chatHandler) Bots(ctx context.Context, in *BotsRequest, out *BotsResponse) error {
+	return h.ChatHandler.Bots(ctx, in, out)
+}
+
+func (h *chatHandler) BotSend(ctx context.Context, in *BotSendRequest, out *BotSendResponse) error {
+	return h.ChatHandler.BotSend(ctx, in, out)
+}
+
+func (h *chatHandler) UploadKeys(ctx context.Context, in *UploadKeysRequest, out *UploadKeysResponse) error {
+	return h.ChatHandler.UploadKeys(ctx, in, out)
+}
+
+func (h *chatHandler) FetchKeys(ctx context.Context, in *FetchKeysRequest, out *FetchKeysResponse) error {
+	return h.ChatHandler.FetchKeys(ctx, in, out)
+}
+
+func (h *chatHandler) Kick(ctx context.Context, in *KickRequest, out *KickResponse) error {
+	return h.ChatHandler.Kick(ctx, in, out)
+}
+
+func (h *chatHandler) Sessions(ctx context.Context, in *SessionsRequest, out *SessionsResponse) error {
+	return h.ChatHandler.Sessions(ctx, in, out)
+}
+
+func (h *chatHandler) RevokeSession(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error {
+	return h.ChatHandler.RevokeSession(ctx, in, out)
+}
+
+func (h *chatHandler) Receipts(ctx context.Context, in *ReceiptsRequest, out *ReceiptsResponse) error {
+	return h.ChatHandler.Receipts(ctx, in, out)
+}
+
+func (h *chatHandler) History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error {
+	return h.ChatHandler.History(ctx, in, out)
+}
+
+func (h *chatHandler) Thread(ctx context.Context, in *ThreadRequest, out *ThreadResponse) error {
+	return h.ChatHandler.Thread(ctx, in, out)
+}
+
+func (h *chatHandler) FollowThread(ctx context.Context, in *FollowThreadRequest, out *FollowThreadResponse) error {
+	return h.ChatHandler.FollowThread(ctx, in, out)
+}
+
+func (h *chatHandler) UnfollowThread(ctx context.Context, in *UnfollowThreadRequest, out *UnfollowThreadResponse) error {
+	return h.ChatHandler.UnfollowThread(ctx, in, out)
+}
+
+func (h *chatHandler) React(ctx context.Context, in *ReactRequest, out *ReactResponse) error {
+	return h.ChatHandler.React(ctx, in, out)
+}
+
+func (h *chatHandler) Mentions(ctx context.Context, in *MentionsRequest, out *MentionsResponse) error {
+	return h.ChatHandler.Mentions(ctx, in, out)
+}
+
+func (h *chatHandler) Pin(ctx context.Context, in *PinRequest, out *PinResponse) error {
+	return h.ChatHandler.Pin(ctx, in, out)
+}
+
+func (h *chatHandler) Unpin(ctx context.Context, in *UnpinRequest, out *UnpinResponse) error {
+	return h.ChatHandler.Unpin(ctx, in, out)
+}
+
+func (h *chatHandler) Pinned(ctx context.Context, in *PinnedRequest, out *PinnedResponse) error {
+	return h.ChatHandler.Pinned(ctx, in, out)
+}
+
+func (h *chatHandler) SetNotice(ctx context.Context, in *SetNoticeRequest, out *SetNoticeResponse) error {
+	return h.ChatHandler.SetNotice(ctx, in, out)
+}
+
+func (h *chatHandler) Scheduled(ctx context.Context, in *ScheduledRequest, out *ScheduledResponse) error {
+	return h.ChatHandler.Scheduled(ctx, in, out)
+}
+
+func (h *chatHandler) EditScheduled(ctx context.Context, in *EditScheduledRequest, out *EditScheduledResponse) error {
+	return h.ChatHandler.EditScheduled(ctx, in, out)
+}
+
+func (h *chatHandler) CancelScheduled(ctx context.Context, in *CancelScheduledRequest, out *CancelScheduledResponse) error {
+	return h.ChatHandler.CancelScheduled(ctx, in, out)
+}
+
+func (h *chatHandler) MessageTTL(ctx context.Context, in *MessageTTLRequest, out *MessageTTLResponse) error {
+	return h.ChatHandler.MessageTTL(ctx, in, out)
+}
+
+func (h *chatHandler) SetMessageTTL(ctx context.Context, in *SetMessageTTLRequest, out *SetMessageTTLResponse) error {
+	return h.ChatHandler.SetMessageTTL(ctx, in, out)
+}
+
+func (h *chatHandler) Members(ctx context.Context, in *MembersRequest, out *MembersResponse) error {
+	return h.ChatHandler.Members(ctx, in, out)
+}
+
+func (h *chatHandler) Presence(ctx context.Context, in *PresenceRequest, out *PresenceResponse) error {
+	return h.ChatHandler.Presence(ctx, in, out)
+}
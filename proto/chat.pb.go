@@ -29,7 +29,7 @@ func (m *RegisterRequest) Reset()         { *m = RegisterRequest{} }
 func (m *RegisterRequest) String() string { return proto.CompactTextString(m) }
 func (*RegisterRequest) ProtoMessage()    {}
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{0}
+	return fileDescriptor_chat_d9755979aa374f81, []int{0}
 }
 func (m *RegisterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RegisterRequest.Unmarshal(m, b)
@@ -66,7 +66,7 @@ func (m *RegisterResponse) Reset()         { *m = RegisterResponse{} }
 func (m *RegisterResponse) String() string { return proto.CompactTextString(m) }
 func (*RegisterResponse) ProtoMessage()    {}
 func (*RegisterResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{1}
+	return fileDescriptor_chat_d9755979aa374f81, []int{1}
 }
 func (m *RegisterResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RegisterResponse.Unmarshal(m, b)
@@ -97,7 +97,7 @@ func (m *UnregisterRequest) Reset()         { *m = UnregisterRequest{} }
 func (m *UnregisterRequest) String() string { return proto.CompactTextString(m) }
 func (*UnregisterRequest) ProtoMessage()    {}
 func (*UnregisterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{2}
+	return fileDescriptor_chat_d9755979aa374f81, []int{2}
 }
 func (m *UnregisterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UnregisterRequest.Unmarshal(m, b)
@@ -134,7 +134,7 @@ func (m *UnregisterResponse) Reset()         { *m = UnregisterResponse{} }
 func (m *UnregisterResponse) String() string { return proto.CompactTextString(m) }
 func (*UnregisterResponse) ProtoMessage()    {}
 func (*UnregisterResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{3}
+	return fileDescriptor_chat_d9755979aa374f81, []int{3}
 }
 func (m *UnregisterResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UnregisterResponse.Unmarshal(m, b)
@@ -165,7 +165,7 @@ func (m *UsersRequest) Reset()         { *m = UsersRequest{} }
 func (m *UsersRequest) String() string { return proto.CompactTextString(m) }
 func (*UsersRequest) ProtoMessage()    {}
 func (*UsersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{4}
+	return fileDescriptor_chat_d9755979aa374f81, []int{4}
 }
 func (m *UsersRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UsersRequest.Unmarshal(m, b)
@@ -203,7 +203,7 @@ func (m *UsersResponse) Reset()         { *m = UsersResponse{} }
 func (m *UsersResponse) String() string { return proto.CompactTextString(m) }
 func (*UsersResponse) ProtoMessage()    {}
 func (*UsersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{5}
+	return fileDescriptor_chat_d9755979aa374f81, []int{5}
 }
 func (m *UsersResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UsersResponse.Unmarshal(m, b)
@@ -241,7 +241,7 @@ func (m *RoomsRequest) Reset()         { *m = RoomsRequest{} }
 func (m *RoomsRequest) String() string { return proto.CompactTextString(m) }
 func (*RoomsRequest) ProtoMessage()    {}
 func (*RoomsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{6}
+	return fileDescriptor_chat_d9755979aa374f81, []int{6}
 }
 func (m *RoomsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RoomsRequest.Unmarshal(m, b)
@@ -279,7 +279,7 @@ func (m *RoomsResponse) Reset()         { *m = RoomsResponse{} }
 func (m *RoomsResponse) String() string { return proto.CompactTextString(m) }
 func (*RoomsResponse) ProtoMessage()    {}
 func (*RoomsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{7}
+	return fileDescriptor_chat_d9755979aa374f81, []int{7}
 }
 func (m *RoomsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RoomsResponse.Unmarshal(m, b)
@@ -318,7 +318,7 @@ func (m *JoinRequest) Reset()         { *m = JoinRequest{} }
 func (m *JoinRequest) String() string { return proto.CompactTextString(m) }
 func (*JoinRequest) ProtoMessage()    {}
 func (*JoinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{8}
+	return fileDescriptor_chat_d9755979aa374f81, []int{8}
 }
 func (m *JoinRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_JoinRequest.Unmarshal(m, b)
@@ -362,7 +362,7 @@ func (m *JoinResponse) Reset()         { *m = JoinResponse{} }
 func (m *JoinResponse) String() string { return proto.CompactTextString(m) }
 func (*JoinResponse) ProtoMessage()    {}
 func (*JoinResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{9}
+	return fileDescriptor_chat_d9755979aa374f81, []int{9}
 }
 func (m *JoinResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_JoinResponse.Unmarshal(m, b)
@@ -394,7 +394,7 @@ func (m *OutRequest) Reset()         { *m = OutRequest{} }
 func (m *OutRequest) String() string { return proto.CompactTextString(m) }
 func (*OutRequest) ProtoMessage()    {}
 func (*OutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{10}
+	return fileDescriptor_chat_d9755979aa374f81, []int{10}
 }
 func (m *OutRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OutRequest.Unmarshal(m, b)
@@ -438,7 +438,7 @@ func (m *OutResponse) Reset()         { *m = OutResponse{} }
 func (m *OutResponse) String() string { return proto.CompactTextString(m) }
 func (*OutResponse) ProtoMessage()    {}
 func (*OutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{11}
+	return fileDescriptor_chat_d9755979aa374f81, []int{11}
 }
 func (m *OutResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OutResponse.Unmarshal(m, b)
@@ -460,6 +460,7 @@ var xxx_messageInfo_OutResponse proto.InternalMessageInfo
 
 type SendRequest struct {
 	Event                *Event   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	SendAt               int64    `protobuf:"varint,2,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -469,7 +470,7 @@ func (m *SendRequest) Reset()         { *m = SendRequest{} }
 func (m *SendRequest) String() string { return proto.CompactTextString(m) }
 func (*SendRequest) ProtoMessage()    {}
 func (*SendRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{12}
+	return fileDescriptor_chat_d9755979aa374f81, []int{12}
 }
 func (m *SendRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SendRequest.Unmarshal(m, b)
@@ -496,6 +497,13 @@ func (m *SendRequest) GetEvent() *Event {
 	return nil
 }
 
+func (m *SendRequest) GetSendAt() int64 {
+	if m != nil {
+		return m.SendAt
+	}
+	return 0
+}
+
 type SendResponse struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -507,7 +515,7 @@ func (m *SendResponse) Reset()         { *m = SendResponse{} }
 func (m *SendResponse) String() string { return proto.CompactTextString(m) }
 func (*SendResponse) ProtoMessage()    {}
 func (*SendResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{13}
+	return fileDescriptor_chat_d9755979aa374f81, []int{13}
 }
 func (m *SendResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SendResponse.Unmarshal(m, b)
@@ -538,6 +546,10 @@ type StreamRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Platform             string   `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
 	Start                int64    `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	Ip                   string   `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	Browser              string   `protobuf:"bytes,5,opt,name=browser,proto3" json:"browser,omitempty"`
+	Os                   string   `protobuf:"bytes,6,opt,name=os,proto3" json:"os,omitempty"`
+	Token                string   `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -547,7 +559,7 @@ func (m *StreamRequest) Reset()         { *m = StreamRequest{} }
 func (m *StreamRequest) String() string { return proto.CompactTextString(m) }
 func (*StreamRequest) ProtoMessage()    {}
 func (*StreamRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{14}
+	return fileDescriptor_chat_d9755979aa374f81, []int{14}
 }
 func (m *StreamRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamRequest.Unmarshal(m, b)
@@ -588,8 +600,38 @@ func (m *StreamRequest) GetStart() int64 {
 	return 0
 }
 
+func (m *StreamRequest) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *StreamRequest) GetBrowser() string {
+	if m != nil {
+		return m.Browser
+	}
+	return ""
+}
+
+func (m *StreamRequest) GetOs() string {
+	if m != nil {
+		return m.Os
+	}
+	return ""
+}
+
+func (m *StreamRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
 type StreamResponse struct {
 	Event                *Event   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Traceparent          string   `protobuf:"bytes,2,opt,name=traceparent,proto3" json:"traceparent,omitempty"`
+	Tracestate           string   `protobuf:"bytes,3,opt,name=tracestate,proto3" json:"tracestate,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -599,7 +641,7 @@ func (m *StreamResponse) Reset()         { *m = StreamResponse{} }
 func (m *StreamResponse) String() string { return proto.CompactTextString(m) }
 func (*StreamResponse) ProtoMessage()    {}
 func (*StreamResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{15}
+	return fileDescriptor_chat_d9755979aa374f81, []int{15}
 }
 func (m *StreamResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamResponse.Unmarshal(m, b)
@@ -626,232 +668,3636 @@ func (m *StreamResponse) GetEvent() *Event {
 	return nil
 }
 
-type Event struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
-	From                 string   `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
-	To                   string   `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
-	Body                 string   `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
-	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+func (m *StreamResponse) GetTraceparent() string {
+	if m != nil {
+		return m.Traceparent
+	}
+	return ""
+}
+
+func (m *StreamResponse) GetTracestate() string {
+	if m != nil {
+		return m.Tracestate
+	}
+	return ""
+}
+
+type CreateWebhookRequest struct {
+	Webhook              *Webhook `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Event) Reset()         { *m = Event{} }
-func (m *Event) String() string { return proto.CompactTextString(m) }
-func (*Event) ProtoMessage()    {}
-func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{16}
+func (m *CreateWebhookRequest) Reset()         { *m = CreateWebhookRequest{} }
+func (m *CreateWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookRequest) ProtoMessage()    {}
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{16}
 }
-func (m *Event) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Event.Unmarshal(m, b)
+func (m *CreateWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookRequest.Unmarshal(m, b)
 }
-func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+func (m *CreateWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookRequest.Marshal(b, m, deterministic)
 }
-func (dst *Event) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Event.Merge(dst, src)
+func (dst *CreateWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookRequest.Merge(dst, src)
 }
-func (m *Event) XXX_Size() int {
-	return xxx_messageInfo_Event.Size(m)
+func (m *CreateWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookRequest.Size(m)
 }
-func (m *Event) XXX_DiscardUnknown() {
-	xxx_messageInfo_Event.DiscardUnknown(m)
+func (m *CreateWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Event proto.InternalMessageInfo
+var xxx_messageInfo_CreateWebhookRequest proto.InternalMessageInfo
 
-func (m *Event) GetId() string {
+func (m *CreateWebhookRequest) GetWebhook() *Webhook {
 	if m != nil {
-		return m.Id
+		return m.Webhook
 	}
-	return ""
+	return nil
 }
 
-func (m *Event) GetType() string {
-	if m != nil {
-		return m.Type
-	}
-	return ""
+type CreateWebhookResponse struct {
+	Webhook              *Webhook `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Event) GetFrom() string {
-	if m != nil {
-		return m.From
-	}
-	return ""
+func (m *CreateWebhookResponse) Reset()         { *m = CreateWebhookResponse{} }
+func (m *CreateWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookResponse) ProtoMessage()    {}
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{17}
+}
+func (m *CreateWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookResponse.Unmarshal(m, b)
+}
+func (m *CreateWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookResponse.Marshal(b, m, deterministic)
+}
+func (dst *CreateWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookResponse.Merge(dst, src)
+}
+func (m *CreateWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookResponse.Size(m)
+}
+func (m *CreateWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookResponse.DiscardUnknown(m)
 }
 
-func (m *Event) GetTo() string {
+var xxx_messageInfo_CreateWebhookResponse proto.InternalMessageInfo
+
+func (m *CreateWebhookResponse) GetWebhook() *Webhook {
 	if m != nil {
-		return m.To
+		return m.Webhook
 	}
-	return ""
+	return nil
 }
 
-func (m *Event) GetBody() string {
-	if m != nil {
-		return m.Body
-	}
-	return ""
+type DeleteWebhookRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Event) GetCreated() int64 {
+func (m *DeleteWebhookRequest) Reset()         { *m = DeleteWebhookRequest{} }
+func (m *DeleteWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookRequest) ProtoMessage()    {}
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{18}
+}
+func (m *DeleteWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookRequest.Unmarshal(m, b)
+}
+func (m *DeleteWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookRequest.Merge(dst, src)
+}
+func (m *DeleteWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookRequest.Size(m)
+}
+func (m *DeleteWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookRequest proto.InternalMessageInfo
+
+func (m *DeleteWebhookRequest) GetId() int64 {
 	if m != nil {
-		return m.Created
+		return m.Id
 	}
 	return 0
 }
 
-type Room struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+type DeleteWebhookResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Room) Reset()         { *m = Room{} }
-func (m *Room) String() string { return proto.CompactTextString(m) }
-func (*Room) ProtoMessage()    {}
-func (*Room) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{17}
+func (m *DeleteWebhookResponse) Reset()         { *m = DeleteWebhookResponse{} }
+func (m *DeleteWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookResponse) ProtoMessage()    {}
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{19}
 }
-func (m *Room) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Room.Unmarshal(m, b)
+func (m *DeleteWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookResponse.Unmarshal(m, b)
 }
-func (m *Room) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Room.Marshal(b, m, deterministic)
+func (m *DeleteWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookResponse.Marshal(b, m, deterministic)
 }
-func (dst *Room) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Room.Merge(dst, src)
+func (dst *DeleteWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookResponse.Merge(dst, src)
 }
-func (m *Room) XXX_Size() int {
-	return xxx_messageInfo_Room.Size(m)
+func (m *DeleteWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookResponse.Size(m)
 }
-func (m *Room) XXX_DiscardUnknown() {
-	xxx_messageInfo_Room.DiscardUnknown(m)
+func (m *DeleteWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Room proto.InternalMessageInfo
+var xxx_messageInfo_DeleteWebhookResponse proto.InternalMessageInfo
 
-func (m *Room) GetId() string {
+type WebhooksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WebhooksRequest) Reset()         { *m = WebhooksRequest{} }
+func (m *WebhooksRequest) String() string { return proto.CompactTextString(m) }
+func (*WebhooksRequest) ProtoMessage()    {}
+func (*WebhooksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{20}
+}
+func (m *WebhooksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhooksRequest.Unmarshal(m, b)
+}
+func (m *WebhooksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhooksRequest.Marshal(b, m, deterministic)
+}
+func (dst *WebhooksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhooksRequest.Merge(dst, src)
+}
+func (m *WebhooksRequest) XXX_Size() int {
+	return xxx_messageInfo_WebhooksRequest.Size(m)
+}
+func (m *WebhooksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhooksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhooksRequest proto.InternalMessageInfo
+
+type WebhooksResponse struct {
+	Webhooks             []*Webhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *WebhooksResponse) Reset()         { *m = WebhooksResponse{} }
+func (m *WebhooksResponse) String() string { return proto.CompactTextString(m) }
+func (*WebhooksResponse) ProtoMessage()    {}
+func (*WebhooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{21}
+}
+func (m *WebhooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhooksResponse.Unmarshal(m, b)
+}
+func (m *WebhooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhooksResponse.Marshal(b, m, deterministic)
+}
+func (dst *WebhooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhooksResponse.Merge(dst, src)
+}
+func (m *WebhooksResponse) XXX_Size() int {
+	return xxx_messageInfo_WebhooksResponse.Size(m)
+}
+func (m *WebhooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhooksResponse proto.InternalMessageInfo
+
+func (m *WebhooksResponse) GetWebhooks() []*Webhook {
 	if m != nil {
-		return m.Id
+		return m.Webhooks
 	}
-	return ""
+	return nil
 }
 
-func (m *Room) GetName() string {
+type CreateBotRequest struct {
+	Bot                  *Bot     `protobuf:"bytes,1,opt,name=bot,proto3" json:"bot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateBotRequest) Reset()         { *m = CreateBotRequest{} }
+func (m *CreateBotRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateBotRequest) ProtoMessage()    {}
+func (*CreateBotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{22}
+}
+func (m *CreateBotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateBotRequest.Unmarshal(m, b)
+}
+func (m *CreateBotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateBotRequest.Marshal(b, m, deterministic)
+}
+func (dst *CreateBotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateBotRequest.Merge(dst, src)
+}
+func (m *CreateBotRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateBotRequest.Size(m)
+}
+func (m *CreateBotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateBotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateBotRequest proto.InternalMessageInfo
+
+func (m *CreateBotRequest) GetBot() *Bot {
 	if m != nil {
-		return m.Name
+		return m.Bot
 	}
-	return ""
+	return nil
 }
 
-type User struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+type CreateBotResponse struct {
+	Bot                  *Bot     `protobuf:"bytes,1,opt,name=bot,proto3" json:"bot,omitempty"`
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *User) Reset()         { *m = User{} }
-func (m *User) String() string { return proto.CompactTextString(m) }
-func (*User) ProtoMessage()    {}
-func (*User) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{18}
+func (m *CreateBotResponse) Reset()         { *m = CreateBotResponse{} }
+func (m *CreateBotResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateBotResponse) ProtoMessage()    {}
+func (*CreateBotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{23}
 }
-func (m *User) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_User.Unmarshal(m, b)
+func (m *CreateBotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateBotResponse.Unmarshal(m, b)
 }
-func (m *User) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_User.Marshal(b, m, deterministic)
+func (m *CreateBotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateBotResponse.Marshal(b, m, deterministic)
 }
-func (dst *User) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_User.Merge(dst, src)
+func (dst *CreateBotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateBotResponse.Merge(dst, src)
 }
-func (m *User) XXX_Size() int {
-	return xxx_messageInfo_User.Size(m)
+func (m *CreateBotResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateBotResponse.Size(m)
 }
-func (m *User) XXX_DiscardUnknown() {
-	xxx_messageInfo_User.DiscardUnknown(m)
+func (m *CreateBotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateBotResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_User proto.InternalMessageInfo
+var xxx_messageInfo_CreateBotResponse proto.InternalMessageInfo
 
-func (m *User) GetId() string {
+func (m *CreateBotResponse) GetBot() *Bot {
 	if m != nil {
-		return m.Id
+		return m.Bot
 	}
-	return ""
+	return nil
 }
 
-func (m *User) GetName() string {
+func (m *CreateBotResponse) GetToken() string {
 	if m != nil {
-		return m.Name
+		return m.Token
 	}
 	return ""
 }
 
-type Client struct {
+type DeleteBotRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Platform             string   `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
-	IsOnline             bool     `protobuf:"varint,3,opt,name=is_online,json=isOnline,proto3" json:"is_online,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Client) Reset()         { *m = Client{} }
-func (m *Client) String() string { return proto.CompactTextString(m) }
-func (*Client) ProtoMessage()    {}
-func (*Client) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chat_95643672631b59e8, []int{19}
+func (m *DeleteBotRequest) Reset()         { *m = DeleteBotRequest{} }
+func (m *DeleteBotRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteBotRequest) ProtoMessage()    {}
+func (*DeleteBotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{24}
 }
-func (m *Client) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Client.Unmarshal(m, b)
+func (m *DeleteBotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteBotRequest.Unmarshal(m, b)
 }
-func (m *Client) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Client.Marshal(b, m, deterministic)
+func (m *DeleteBotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteBotRequest.Marshal(b, m, deterministic)
 }
-func (dst *Client) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Client.Merge(dst, src)
+func (dst *DeleteBotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteBotRequest.Merge(dst, src)
 }
-func (m *Client) XXX_Size() int {
-	return xxx_messageInfo_Client.Size(m)
+func (m *DeleteBotRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteBotRequest.Size(m)
 }
-func (m *Client) XXX_DiscardUnknown() {
-	xxx_messageInfo_Client.DiscardUnknown(m)
+func (m *DeleteBotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteBotRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Client proto.InternalMessageInfo
+var xxx_messageInfo_DeleteBotRequest proto.InternalMessageInfo
 
-func (m *Client) GetId() string {
+func (m *DeleteBotRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *Client) GetPlatform() string {
-	if m != nil {
-		return m.Platform
-	}
-	return ""
+type DeleteBotResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Client) GetIsOnline() bool {
+func (m *DeleteBotResponse) Reset()         { *m = DeleteBotResponse{} }
+func (m *DeleteBotResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteBotResponse) ProtoMessage()    {}
+func (*DeleteBotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{25}
+}
+func (m *DeleteBotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteBotResponse.Unmarshal(m, b)
+}
+func (m *DeleteBotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteBotResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteBotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteBotResponse.Merge(dst, src)
+}
+func (m *DeleteBotResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteBotResponse.Size(m)
+}
+func (m *DeleteBotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteBotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteBotResponse proto.InternalMessageInfo
+
+type BotsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BotsRequest) Reset()         { *m = BotsRequest{} }
+func (m *BotsRequest) String() string { return proto.CompactTextString(m) }
+func (*BotsRequest) ProtoMessage()    {}
+func (*BotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{26}
+}
+func (m *BotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BotsRequest.Unmarshal(m, b)
+}
+func (m *BotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BotsRequest.Marshal(b, m, deterministic)
+}
+func (dst *BotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BotsRequest.Merge(dst, src)
+}
+func (m *BotsRequest) XXX_Size() int {
+	return xxx_messageInfo_BotsRequest.Size(m)
+}
+func (m *BotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BotsRequest proto.InternalMessageInfo
+
+type BotsResponse struct {
+	Bots                 []*Bot   `protobuf:"bytes,1,rep,name=bots,proto3" json:"bots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BotsResponse) Reset()         { *m = BotsResponse{} }
+func (m *BotsResponse) String() string { return proto.CompactTextString(m) }
+func (*BotsResponse) ProtoMessage()    {}
+func (*BotsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{27}
+}
+func (m *BotsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BotsResponse.Unmarshal(m, b)
+}
+func (m *BotsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BotsResponse.Marshal(b, m, deterministic)
+}
+func (dst *BotsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BotsResponse.Merge(dst, src)
+}
+func (m *BotsResponse) XXX_Size() int {
+	return xxx_messageInfo_BotsResponse.Size(m)
+}
+func (m *BotsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BotsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BotsResponse proto.InternalMessageInfo
+
+func (m *BotsResponse) GetBots() []*Bot {
 	if m != nil {
-		return m.IsOnline
+		return m.Bots
 	}
-	return false
+	return nil
 }
 
-func init() {
-	proto.RegisterType((*RegisterRequest)(nil), "go.micro.srv.chat.RegisterRequest")
+type BotSendRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Event                *Event   `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	SendAt               int64    `protobuf:"varint,3,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BotSendRequest) Reset()         { *m = BotSendRequest{} }
+func (m *BotSendRequest) String() string { return proto.CompactTextString(m) }
+func (*BotSendRequest) ProtoMessage()    {}
+func (*BotSendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{28}
+}
+func (m *BotSendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BotSendRequest.Unmarshal(m, b)
+}
+func (m *BotSendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BotSendRequest.Marshal(b, m, deterministic)
+}
+func (dst *BotSendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BotSendRequest.Merge(dst, src)
+}
+func (m *BotSendRequest) XXX_Size() int {
+	return xxx_messageInfo_BotSendRequest.Size(m)
+}
+func (m *BotSendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BotSendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BotSendRequest proto.InternalMessageInfo
+
+func (m *BotSendRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *BotSendRequest) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *BotSendRequest) GetSendAt() int64 {
+	if m != nil {
+		return m.SendAt
+	}
+	return 0
+}
+
+type BotSendResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BotSendResponse) Reset()         { *m = BotSendResponse{} }
+func (m *BotSendResponse) String() string { return proto.CompactTextString(m) }
+func (*BotSendResponse) ProtoMessage()    {}
+func (*BotSendResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{29}
+}
+func (m *BotSendResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BotSendResponse.Unmarshal(m, b)
+}
+func (m *BotSendResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BotSendResponse.Marshal(b, m, deterministic)
+}
+func (dst *BotSendResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BotSendResponse.Merge(dst, src)
+}
+func (m *BotSendResponse) XXX_Size() int {
+	return xxx_messageInfo_BotSendResponse.Size(m)
+}
+func (m *BotSendResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BotSendResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BotSendResponse proto.InternalMessageInfo
+
+func (m *BotSendResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type UploadKeysRequest struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IdentityKey          []byte    `protobuf:"bytes,2,opt,name=identity_key,json=identityKey,proto3" json:"identity_key,omitempty"`
+	Prekeys              []*PreKey `protobuf:"bytes,3,rep,name=prekeys,proto3" json:"prekeys,omitempty"`
+	Replace              bool      `protobuf:"varint,4,opt,name=replace,proto3" json:"replace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UploadKeysRequest) Reset()         { *m = UploadKeysRequest{} }
+func (m *UploadKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*UploadKeysRequest) ProtoMessage()    {}
+func (*UploadKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{30}
+}
+func (m *UploadKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UploadKeysRequest.Unmarshal(m, b)
+}
+func (m *UploadKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UploadKeysRequest.Marshal(b, m, deterministic)
+}
+func (dst *UploadKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UploadKeysRequest.Merge(dst, src)
+}
+func (m *UploadKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_UploadKeysRequest.Size(m)
+}
+func (m *UploadKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UploadKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UploadKeysRequest proto.InternalMessageInfo
+
+func (m *UploadKeysRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UploadKeysRequest) GetIdentityKey() []byte {
+	if m != nil {
+		return m.IdentityKey
+	}
+	return nil
+}
+
+func (m *UploadKeysRequest) GetPrekeys() []*PreKey {
+	if m != nil {
+		return m.Prekeys
+	}
+	return nil
+}
+
+func (m *UploadKeysRequest) GetReplace() bool {
+	if m != nil {
+		return m.Replace
+	}
+	return false
+}
+
+type UploadKeysResponse struct {
+	Prekeys              int64    `protobuf:"varint,1,opt,name=prekeys,proto3" json:"prekeys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UploadKeysResponse) Reset()         { *m = UploadKeysResponse{} }
+func (m *UploadKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*UploadKeysResponse) ProtoMessage()    {}
+func (*UploadKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{31}
+}
+func (m *UploadKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UploadKeysResponse.Unmarshal(m, b)
+}
+func (m *UploadKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UploadKeysResponse.Marshal(b, m, deterministic)
+}
+func (dst *UploadKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UploadKeysResponse.Merge(dst, src)
+}
+func (m *UploadKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_UploadKeysResponse.Size(m)
+}
+func (m *UploadKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UploadKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UploadKeysResponse proto.InternalMessageInfo
+
+func (m *UploadKeysResponse) GetPrekeys() int64 {
+	if m != nil {
+		return m.Prekeys
+	}
+	return 0
+}
+
+type FetchKeysRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FetchKeysRequest) Reset()         { *m = FetchKeysRequest{} }
+func (m *FetchKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*FetchKeysRequest) ProtoMessage()    {}
+func (*FetchKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{32}
+}
+func (m *FetchKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchKeysRequest.Unmarshal(m, b)
+}
+func (m *FetchKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchKeysRequest.Marshal(b, m, deterministic)
+}
+func (dst *FetchKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchKeysRequest.Merge(dst, src)
+}
+func (m *FetchKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_FetchKeysRequest.Size(m)
+}
+func (m *FetchKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchKeysRequest proto.InternalMessageInfo
+
+func (m *FetchKeysRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *FetchKeysRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type FetchKeysResponse struct {
+	Bundle               *KeyBundle `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *FetchKeysResponse) Reset()         { *m = FetchKeysResponse{} }
+func (m *FetchKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*FetchKeysResponse) ProtoMessage()    {}
+func (*FetchKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{33}
+}
+func (m *FetchKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchKeysResponse.Unmarshal(m, b)
+}
+func (m *FetchKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchKeysResponse.Marshal(b, m, deterministic)
+}
+func (dst *FetchKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchKeysResponse.Merge(dst, src)
+}
+func (m *FetchKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_FetchKeysResponse.Size(m)
+}
+func (m *FetchKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchKeysResponse proto.InternalMessageInfo
+
+func (m *FetchKeysResponse) GetBundle() *KeyBundle {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type KickRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Platform             string   `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickRequest) Reset()         { *m = KickRequest{} }
+func (m *KickRequest) String() string { return proto.CompactTextString(m) }
+func (*KickRequest) ProtoMessage()    {}
+func (*KickRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{34}
+}
+func (m *KickRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickRequest.Unmarshal(m, b)
+}
+func (m *KickRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickRequest.Marshal(b, m, deterministic)
+}
+func (dst *KickRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickRequest.Merge(dst, src)
+}
+func (m *KickRequest) XXX_Size() int {
+	return xxx_messageInfo_KickRequest.Size(m)
+}
+func (m *KickRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickRequest proto.InternalMessageInfo
+
+func (m *KickRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *KickRequest) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *KickRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type KickResponse struct {
+	Nodes                int64    `protobuf:"varint,1,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	Sessions             int64    `protobuf:"varint,2,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	Complete             bool     `protobuf:"varint,3,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KickResponse) Reset()         { *m = KickResponse{} }
+func (m *KickResponse) String() string { return proto.CompactTextString(m) }
+func (*KickResponse) ProtoMessage()    {}
+func (*KickResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{35}
+}
+func (m *KickResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KickResponse.Unmarshal(m, b)
+}
+func (m *KickResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KickResponse.Marshal(b, m, deterministic)
+}
+func (dst *KickResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KickResponse.Merge(dst, src)
+}
+func (m *KickResponse) XXX_Size() int {
+	return xxx_messageInfo_KickResponse.Size(m)
+}
+func (m *KickResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KickResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KickResponse proto.InternalMessageInfo
+
+func (m *KickResponse) GetNodes() int64 {
+	if m != nil {
+		return m.Nodes
+	}
+	return 0
+}
+
+func (m *KickResponse) GetSessions() int64 {
+	if m != nil {
+		return m.Sessions
+	}
+	return 0
+}
+
+func (m *KickResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+type SessionsRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionsRequest) Reset()         { *m = SessionsRequest{} }
+func (m *SessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*SessionsRequest) ProtoMessage()    {}
+func (*SessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{36}
+}
+func (m *SessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionsRequest.Unmarshal(m, b)
+}
+func (m *SessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *SessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionsRequest.Merge(dst, src)
+}
+func (m *SessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_SessionsRequest.Size(m)
+}
+func (m *SessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionsRequest proto.InternalMessageInfo
+
+func (m *SessionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type SessionsResponse struct {
+	Sessions             []*Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SessionsResponse) Reset()         { *m = SessionsResponse{} }
+func (m *SessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*SessionsResponse) ProtoMessage()    {}
+func (*SessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{37}
+}
+func (m *SessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionsResponse.Unmarshal(m, b)
+}
+func (m *SessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *SessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionsResponse.Merge(dst, src)
+}
+func (m *SessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_SessionsResponse.Size(m)
+}
+func (m *SessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionsResponse proto.InternalMessageInfo
+
+func (m *SessionsResponse) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SessionId            int64    `protobuf:"varint,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionRequest) Reset()         { *m = RevokeSessionRequest{} }
+func (m *RevokeSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionRequest) ProtoMessage()    {}
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{38}
+}
+func (m *RevokeSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionRequest.Unmarshal(m, b)
+}
+func (m *RevokeSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionRequest.Marshal(b, m, deterministic)
+}
+func (dst *RevokeSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionRequest.Merge(dst, src)
+}
+func (m *RevokeSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionRequest.Size(m)
+}
+func (m *RevokeSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionRequest proto.InternalMessageInfo
+
+func (m *RevokeSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RevokeSessionRequest) GetSessionId() int64 {
+	if m != nil {
+		return m.SessionId
+	}
+	return 0
+}
+
+type RevokeSessionResponse struct {
+	Complete             bool     `protobuf:"varint,1,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionResponse) Reset()         { *m = RevokeSessionResponse{} }
+func (m *RevokeSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionResponse) ProtoMessage()    {}
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{39}
+}
+func (m *RevokeSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionResponse.Unmarshal(m, b)
+}
+func (m *RevokeSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionResponse.Marshal(b, m, deterministic)
+}
+func (dst *RevokeSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionResponse.Merge(dst, src)
+}
+func (m *RevokeSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionResponse.Size(m)
+}
+func (m *RevokeSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionResponse proto.InternalMessageInfo
+
+func (m *RevokeSessionResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+type ReceiptsRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageId            string   `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiptsRequest) Reset()         { *m = ReceiptsRequest{} }
+func (m *ReceiptsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReceiptsRequest) ProtoMessage()    {}
+func (*ReceiptsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{40}
+}
+func (m *ReceiptsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiptsRequest.Unmarshal(m, b)
+}
+func (m *ReceiptsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiptsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReceiptsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiptsRequest.Merge(dst, src)
+}
+func (m *ReceiptsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReceiptsRequest.Size(m)
+}
+func (m *ReceiptsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiptsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiptsRequest proto.InternalMessageInfo
+
+func (m *ReceiptsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReceiptsRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+type ReceiptsResponse struct {
+	Summary              *ReceiptSummary `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Receipts             []*Receipt      `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ReceiptsResponse) Reset()         { *m = ReceiptsResponse{} }
+func (m *ReceiptsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReceiptsResponse) ProtoMessage()    {}
+func (*ReceiptsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{41}
+}
+func (m *ReceiptsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiptsResponse.Unmarshal(m, b)
+}
+func (m *ReceiptsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiptsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReceiptsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiptsResponse.Merge(dst, src)
+}
+func (m *ReceiptsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReceiptsResponse.Size(m)
+}
+func (m *ReceiptsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiptsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiptsResponse proto.InternalMessageInfo
+
+func (m *ReceiptsResponse) GetSummary() *ReceiptSummary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+func (m *ReceiptsResponse) GetReceipts() []*Receipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+type HistoryRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Before               string   `protobuf:"bytes,3,opt,name=before,proto3" json:"before,omitempty"`
+	Limit                int64    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRequest) Reset()         { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()    {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{42}
+}
+func (m *HistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRequest.Unmarshal(m, b)
+}
+func (m *HistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *HistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRequest.Merge(dst, src)
+}
+func (m *HistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_HistoryRequest.Size(m)
+}
+func (m *HistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRequest proto.InternalMessageInfo
+
+func (m *HistoryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	Messages             []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *HistoryResponse) Reset()         { *m = HistoryResponse{} }
+func (m *HistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*HistoryResponse) ProtoMessage()    {}
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{43}
+}
+func (m *HistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryResponse.Unmarshal(m, b)
+}
+func (m *HistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryResponse.Marshal(b, m, deterministic)
+}
+func (dst *HistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryResponse.Merge(dst, src)
+}
+func (m *HistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_HistoryResponse.Size(m)
+}
+func (m *HistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryResponse proto.InternalMessageInfo
+
+func (m *HistoryResponse) GetMessages() []*Message {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+type ThreadRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId             string   `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	After                string   `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	Limit                int64    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ThreadRequest) Reset()         { *m = ThreadRequest{} }
+func (m *ThreadRequest) String() string { return proto.CompactTextString(m) }
+func (*ThreadRequest) ProtoMessage()    {}
+func (*ThreadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{44}
+}
+func (m *ThreadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadRequest.Unmarshal(m, b)
+}
+func (m *ThreadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadRequest.Marshal(b, m, deterministic)
+}
+func (dst *ThreadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadRequest.Merge(dst, src)
+}
+func (m *ThreadRequest) XXX_Size() int {
+	return xxx_messageInfo_ThreadRequest.Size(m)
+}
+func (m *ThreadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadRequest proto.InternalMessageInfo
+
+func (m *ThreadRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ThreadRequest) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+func (m *ThreadRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *ThreadRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ThreadResponse struct {
+	Parent               *Message   `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	Replies              []*Message `protobuf:"bytes,2,rep,name=replies,proto3" json:"replies,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ThreadResponse) Reset()         { *m = ThreadResponse{} }
+func (m *ThreadResponse) String() string { return proto.CompactTextString(m) }
+func (*ThreadResponse) ProtoMessage()    {}
+func (*ThreadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{45}
+}
+func (m *ThreadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadResponse.Unmarshal(m, b)
+}
+func (m *ThreadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadResponse.Marshal(b, m, deterministic)
+}
+func (dst *ThreadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadResponse.Merge(dst, src)
+}
+func (m *ThreadResponse) XXX_Size() int {
+	return xxx_messageInfo_ThreadResponse.Size(m)
+}
+func (m *ThreadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadResponse proto.InternalMessageInfo
+
+func (m *ThreadResponse) GetParent() *Message {
+	if m != nil {
+		return m.Parent
+	}
+	return nil
+}
+
+func (m *ThreadResponse) GetReplies() []*Message {
+	if m != nil {
+		return m.Replies
+	}
+	return nil
+}
+
+type FollowThreadRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId             string   `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FollowThreadRequest) Reset()         { *m = FollowThreadRequest{} }
+func (m *FollowThreadRequest) String() string { return proto.CompactTextString(m) }
+func (*FollowThreadRequest) ProtoMessage()    {}
+func (*FollowThreadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{46}
+}
+func (m *FollowThreadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowThreadRequest.Unmarshal(m, b)
+}
+func (m *FollowThreadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowThreadRequest.Marshal(b, m, deterministic)
+}
+func (dst *FollowThreadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowThreadRequest.Merge(dst, src)
+}
+func (m *FollowThreadRequest) XXX_Size() int {
+	return xxx_messageInfo_FollowThreadRequest.Size(m)
+}
+func (m *FollowThreadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowThreadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowThreadRequest proto.InternalMessageInfo
+
+func (m *FollowThreadRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *FollowThreadRequest) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+type FollowThreadResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FollowThreadResponse) Reset()         { *m = FollowThreadResponse{} }
+func (m *FollowThreadResponse) String() string { return proto.CompactTextString(m) }
+func (*FollowThreadResponse) ProtoMessage()    {}
+func (*FollowThreadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{47}
+}
+func (m *FollowThreadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowThreadResponse.Unmarshal(m, b)
+}
+func (m *FollowThreadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowThreadResponse.Marshal(b, m, deterministic)
+}
+func (dst *FollowThreadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowThreadResponse.Merge(dst, src)
+}
+func (m *FollowThreadResponse) XXX_Size() int {
+	return xxx_messageInfo_FollowThreadResponse.Size(m)
+}
+func (m *FollowThreadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowThreadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowThreadResponse proto.InternalMessageInfo
+
+type UnfollowThreadRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId             string   `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnfollowThreadRequest) Reset()         { *m = UnfollowThreadRequest{} }
+func (m *UnfollowThreadRequest) String() string { return proto.CompactTextString(m) }
+func (*UnfollowThreadRequest) ProtoMessage()    {}
+func (*UnfollowThreadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{48}
+}
+func (m *UnfollowThreadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnfollowThreadRequest.Unmarshal(m, b)
+}
+func (m *UnfollowThreadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnfollowThreadRequest.Marshal(b, m, deterministic)
+}
+func (dst *UnfollowThreadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnfollowThreadRequest.Merge(dst, src)
+}
+func (m *UnfollowThreadRequest) XXX_Size() int {
+	return xxx_messageInfo_UnfollowThreadRequest.Size(m)
+}
+func (m *UnfollowThreadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnfollowThreadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnfollowThreadRequest proto.InternalMessageInfo
+
+func (m *UnfollowThreadRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UnfollowThreadRequest) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+type UnfollowThreadResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnfollowThreadResponse) Reset()         { *m = UnfollowThreadResponse{} }
+func (m *UnfollowThreadResponse) String() string { return proto.CompactTextString(m) }
+func (*UnfollowThreadResponse) ProtoMessage()    {}
+func (*UnfollowThreadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{49}
+}
+func (m *UnfollowThreadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnfollowThreadResponse.Unmarshal(m, b)
+}
+func (m *UnfollowThreadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnfollowThreadResponse.Marshal(b, m, deterministic)
+}
+func (dst *UnfollowThreadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnfollowThreadResponse.Merge(dst, src)
+}
+func (m *UnfollowThreadResponse) XXX_Size() int {
+	return xxx_messageInfo_UnfollowThreadResponse.Size(m)
+}
+func (m *UnfollowThreadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnfollowThreadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnfollowThreadResponse proto.InternalMessageInfo
+
+type ReactRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageId            string   `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Emoji                string   `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Remove               bool     `protobuf:"varint,4,opt,name=remove,proto3" json:"remove,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReactRequest) Reset()         { *m = ReactRequest{} }
+func (m *ReactRequest) String() string { return proto.CompactTextString(m) }
+func (*ReactRequest) ProtoMessage()    {}
+func (*ReactRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{50}
+}
+func (m *ReactRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReactRequest.Unmarshal(m, b)
+}
+func (m *ReactRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReactRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReactRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReactRequest.Merge(dst, src)
+}
+func (m *ReactRequest) XXX_Size() int {
+	return xxx_messageInfo_ReactRequest.Size(m)
+}
+func (m *ReactRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReactRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReactRequest proto.InternalMessageInfo
+
+func (m *ReactRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReactRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *ReactRequest) GetEmoji() string {
+	if m != nil {
+		return m.Emoji
+	}
+	return ""
+}
+
+func (m *ReactRequest) GetRemove() bool {
+	if m != nil {
+		return m.Remove
+	}
+	return false
+}
+
+type ReactResponse struct {
+	Reactions            []*Reaction `protobuf:"bytes,1,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ReactResponse) Reset()         { *m = ReactResponse{} }
+func (m *ReactResponse) String() string { return proto.CompactTextString(m) }
+func (*ReactResponse) ProtoMessage()    {}
+func (*ReactResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{51}
+}
+func (m *ReactResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReactResponse.Unmarshal(m, b)
+}
+func (m *ReactResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReactResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReactResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReactResponse.Merge(dst, src)
+}
+func (m *ReactResponse) XXX_Size() int {
+	return xxx_messageInfo_ReactResponse.Size(m)
+}
+func (m *ReactResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReactResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReactResponse proto.InternalMessageInfo
+
+func (m *ReactResponse) GetReactions() []*Reaction {
+	if m != nil {
+		return m.Reactions
+	}
+	return nil
+}
+
+type MentionsRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Before               int64    `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"`
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Unread               bool     `protobuf:"varint,4,opt,name=unread,proto3" json:"unread,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MentionsRequest) Reset()         { *m = MentionsRequest{} }
+func (m *MentionsRequest) String() string { return proto.CompactTextString(m) }
+func (*MentionsRequest) ProtoMessage()    {}
+func (*MentionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{52}
+}
+func (m *MentionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MentionsRequest.Unmarshal(m, b)
+}
+func (m *MentionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MentionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *MentionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MentionsRequest.Merge(dst, src)
+}
+func (m *MentionsRequest) XXX_Size() int {
+	return xxx_messageInfo_MentionsRequest.Size(m)
+}
+func (m *MentionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MentionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MentionsRequest proto.InternalMessageInfo
+
+func (m *MentionsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *MentionsRequest) GetBefore() int64 {
+	if m != nil {
+		return m.Before
+	}
+	return 0
+}
+
+func (m *MentionsRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *MentionsRequest) GetUnread() bool {
+	if m != nil {
+		return m.Unread
+	}
+	return false
+}
+
+type MentionsResponse struct {
+	Mentions             []*Mention `protobuf:"bytes,1,rep,name=mentions,proto3" json:"mentions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *MentionsResponse) Reset()         { *m = MentionsResponse{} }
+func (m *MentionsResponse) String() string { return proto.CompactTextString(m) }
+func (*MentionsResponse) ProtoMessage()    {}
+func (*MentionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{53}
+}
+func (m *MentionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MentionsResponse.Unmarshal(m, b)
+}
+func (m *MentionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MentionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *MentionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MentionsResponse.Merge(dst, src)
+}
+func (m *MentionsResponse) XXX_Size() int {
+	return xxx_messageInfo_MentionsResponse.Size(m)
+}
+func (m *MentionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MentionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MentionsResponse proto.InternalMessageInfo
+
+func (m *MentionsResponse) GetMentions() []*Mention {
+	if m != nil {
+		return m.Mentions
+	}
+	return nil
+}
+
+type PinRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId               string   `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MessageId            string   `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PinRequest) Reset()         { *m = PinRequest{} }
+func (m *PinRequest) String() string { return proto.CompactTextString(m) }
+func (*PinRequest) ProtoMessage()    {}
+func (*PinRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{54}
+}
+func (m *PinRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinRequest.Unmarshal(m, b)
+}
+func (m *PinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinRequest.Marshal(b, m, deterministic)
+}
+func (dst *PinRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinRequest.Merge(dst, src)
+}
+func (m *PinRequest) XXX_Size() int {
+	return xxx_messageInfo_PinRequest.Size(m)
+}
+func (m *PinRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinRequest proto.InternalMessageInfo
+
+func (m *PinRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PinRequest) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+func (m *PinRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+type PinResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PinResponse) Reset()         { *m = PinResponse{} }
+func (m *PinResponse) String() string { return proto.CompactTextString(m) }
+func (*PinResponse) ProtoMessage()    {}
+func (*PinResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{55}
+}
+func (m *PinResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinResponse.Unmarshal(m, b)
+}
+func (m *PinResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinResponse.Marshal(b, m, deterministic)
+}
+func (dst *PinResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinResponse.Merge(dst, src)
+}
+func (m *PinResponse) XXX_Size() int {
+	return xxx_messageInfo_PinResponse.Size(m)
+}
+func (m *PinResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinResponse proto.InternalMessageInfo
+
+type UnpinRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId               string   `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MessageId            string   `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnpinRequest) Reset()         { *m = UnpinRequest{} }
+func (m *UnpinRequest) String() string { return proto.CompactTextString(m) }
+func (*UnpinRequest) ProtoMessage()    {}
+func (*UnpinRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{56}
+}
+func (m *UnpinRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnpinRequest.Unmarshal(m, b)
+}
+func (m *UnpinRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnpinRequest.Marshal(b, m, deterministic)
+}
+func (dst *UnpinRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnpinRequest.Merge(dst, src)
+}
+func (m *UnpinRequest) XXX_Size() int {
+	return xxx_messageInfo_UnpinRequest.Size(m)
+}
+func (m *UnpinRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnpinRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnpinRequest proto.InternalMessageInfo
+
+func (m *UnpinRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UnpinRequest) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+func (m *UnpinRequest) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+type UnpinResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnpinResponse) Reset()         { *m = UnpinResponse{} }
+func (m *UnpinResponse) String() string { return proto.CompactTextString(m) }
+func (*UnpinResponse) ProtoMessage()    {}
+func (*UnpinResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{57}
+}
+func (m *UnpinResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnpinResponse.Unmarshal(m, b)
+}
+func (m *UnpinResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnpinResponse.Marshal(b, m, deterministic)
+}
+func (dst *UnpinResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnpinResponse.Merge(dst, src)
+}
+func (m *UnpinResponse) XXX_Size() int {
+	return xxx_messageInfo_UnpinResponse.Size(m)
+}
+func (m *UnpinResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnpinResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnpinResponse proto.InternalMessageInfo
+
+type PinnedRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId               string   `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PinnedRequest) Reset()         { *m = PinnedRequest{} }
+func (m *PinnedRequest) String() string { return proto.CompactTextString(m) }
+func (*PinnedRequest) ProtoMessage()    {}
+func (*PinnedRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{58}
+}
+func (m *PinnedRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinnedRequest.Unmarshal(m, b)
+}
+func (m *PinnedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinnedRequest.Marshal(b, m, deterministic)
+}
+func (dst *PinnedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinnedRequest.Merge(dst, src)
+}
+func (m *PinnedRequest) XXX_Size() int {
+	return xxx_messageInfo_PinnedRequest.Size(m)
+}
+func (m *PinnedRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinnedRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinnedRequest proto.InternalMessageInfo
+
+func (m *PinnedRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PinnedRequest) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+type PinnedResponse struct {
+	Notice               *Notice    `protobuf:"bytes,1,opt,name=notice,proto3" json:"notice,omitempty"`
+	Pins                 []*Message `protobuf:"bytes,2,rep,name=pins,proto3" json:"pins,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *PinnedResponse) Reset()         { *m = PinnedResponse{} }
+func (m *PinnedResponse) String() string { return proto.CompactTextString(m) }
+func (*PinnedResponse) ProtoMessage()    {}
+func (*PinnedResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{59}
+}
+func (m *PinnedResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PinnedResponse.Unmarshal(m, b)
+}
+func (m *PinnedResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PinnedResponse.Marshal(b, m, deterministic)
+}
+func (dst *PinnedResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PinnedResponse.Merge(dst, src)
+}
+func (m *PinnedResponse) XXX_Size() int {
+	return xxx_messageInfo_PinnedResponse.Size(m)
+}
+func (m *PinnedResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PinnedResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PinnedResponse proto.InternalMessageInfo
+
+func (m *PinnedResponse) GetNotice() *Notice {
+	if m != nil {
+		return m.Notice
+	}
+	return nil
+}
+
+func (m *PinnedResponse) GetPins() []*Message {
+	if m != nil {
+		return m.Pins
+	}
+	return nil
+}
+
+type SetNoticeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId               string   `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Body                 string   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetNoticeRequest) Reset()         { *m = SetNoticeRequest{} }
+func (m *SetNoticeRequest) String() string { return proto.CompactTextString(m) }
+func (*SetNoticeRequest) ProtoMessage()    {}
+func (*SetNoticeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{60}
+}
+func (m *SetNoticeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetNoticeRequest.Unmarshal(m, b)
+}
+func (m *SetNoticeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetNoticeRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetNoticeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetNoticeRequest.Merge(dst, src)
+}
+func (m *SetNoticeRequest) XXX_Size() int {
+	return xxx_messageInfo_SetNoticeRequest.Size(m)
+}
+func (m *SetNoticeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetNoticeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetNoticeRequest proto.InternalMessageInfo
+
+func (m *SetNoticeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetNoticeRequest) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+func (m *SetNoticeRequest) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+type SetNoticeResponse struct {
+	Notice               *Notice  `protobuf:"bytes,1,opt,name=notice,proto3" json:"notice,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetNoticeResponse) Reset()         { *m = SetNoticeResponse{} }
+func (m *SetNoticeResponse) String() string { return proto.CompactTextString(m) }
+func (*SetNoticeResponse) ProtoMessage()    {}
+func (*SetNoticeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{61}
+}
+func (m *SetNoticeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetNoticeResponse.Unmarshal(m, b)
+}
+func (m *SetNoticeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetNoticeResponse.Marshal(b, m, deterministic)
+}
+func (dst *SetNoticeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetNoticeResponse.Merge(dst, src)
+}
+func (m *SetNoticeResponse) XXX_Size() int {
+	return xxx_messageInfo_SetNoticeResponse.Size(m)
+}
+func (m *SetNoticeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetNoticeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetNoticeResponse proto.InternalMessageInfo
+
+func (m *SetNoticeResponse) GetNotice() *Notice {
+	if m != nil {
+		return m.Notice
+	}
+	return nil
+}
+
+type ScheduledRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduledRequest) Reset()         { *m = ScheduledRequest{} }
+func (m *ScheduledRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduledRequest) ProtoMessage()    {}
+func (*ScheduledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{62}
+}
+func (m *ScheduledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduledRequest.Unmarshal(m, b)
+}
+func (m *ScheduledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduledRequest.Marshal(b, m, deterministic)
+}
+func (dst *ScheduledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduledRequest.Merge(dst, src)
+}
+func (m *ScheduledRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduledRequest.Size(m)
+}
+func (m *ScheduledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduledRequest proto.InternalMessageInfo
+
+func (m *ScheduledRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ScheduledResponse struct {
+	Messages             []*ScheduledMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ScheduledResponse) Reset()         { *m = ScheduledResponse{} }
+func (m *ScheduledResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduledResponse) ProtoMessage()    {}
+func (*ScheduledResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{63}
+}
+func (m *ScheduledResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduledResponse.Unmarshal(m, b)
+}
+func (m *ScheduledResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduledResponse.Marshal(b, m, deterministic)
+}
+func (dst *ScheduledResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduledResponse.Merge(dst, src)
+}
+func (m *ScheduledResponse) XXX_Size() int {
+	return xxx_messageInfo_ScheduledResponse.Size(m)
+}
+func (m *ScheduledResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduledResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduledResponse proto.InternalMessageInfo
+
+func (m *ScheduledResponse) GetMessages() []*ScheduledMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+type EditScheduledRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId              string   `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Body                 string   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	SendAt               int64    `protobuf:"varint,4,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EditScheduledRequest) Reset()         { *m = EditScheduledRequest{} }
+func (m *EditScheduledRequest) String() string { return proto.CompactTextString(m) }
+func (*EditScheduledRequest) ProtoMessage()    {}
+func (*EditScheduledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{64}
+}
+func (m *EditScheduledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EditScheduledRequest.Unmarshal(m, b)
+}
+func (m *EditScheduledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EditScheduledRequest.Marshal(b, m, deterministic)
+}
+func (dst *EditScheduledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EditScheduledRequest.Merge(dst, src)
+}
+func (m *EditScheduledRequest) XXX_Size() int {
+	return xxx_messageInfo_EditScheduledRequest.Size(m)
+}
+func (m *EditScheduledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EditScheduledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EditScheduledRequest proto.InternalMessageInfo
+
+func (m *EditScheduledRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *EditScheduledRequest) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *EditScheduledRequest) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *EditScheduledRequest) GetSendAt() int64 {
+	if m != nil {
+		return m.SendAt
+	}
+	return 0
+}
+
+type EditScheduledResponse struct {
+	Message              *ScheduledMessage `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *EditScheduledResponse) Reset()         { *m = EditScheduledResponse{} }
+func (m *EditScheduledResponse) String() string { return proto.CompactTextString(m) }
+func (*EditScheduledResponse) ProtoMessage()    {}
+func (*EditScheduledResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{65}
+}
+func (m *EditScheduledResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EditScheduledResponse.Unmarshal(m, b)
+}
+func (m *EditScheduledResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EditScheduledResponse.Marshal(b, m, deterministic)
+}
+func (dst *EditScheduledResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EditScheduledResponse.Merge(dst, src)
+}
+func (m *EditScheduledResponse) XXX_Size() int {
+	return xxx_messageInfo_EditScheduledResponse.Size(m)
+}
+func (m *EditScheduledResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EditScheduledResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EditScheduledResponse proto.InternalMessageInfo
+
+func (m *EditScheduledResponse) GetMessage() *ScheduledMessage {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+type CancelScheduledRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId              string   `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelScheduledRequest) Reset()         { *m = CancelScheduledRequest{} }
+func (m *CancelScheduledRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelScheduledRequest) ProtoMessage()    {}
+func (*CancelScheduledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{66}
+}
+func (m *CancelScheduledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelScheduledRequest.Unmarshal(m, b)
+}
+func (m *CancelScheduledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelScheduledRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelScheduledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelScheduledRequest.Merge(dst, src)
+}
+func (m *CancelScheduledRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelScheduledRequest.Size(m)
+}
+func (m *CancelScheduledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelScheduledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelScheduledRequest proto.InternalMessageInfo
+
+func (m *CancelScheduledRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CancelScheduledRequest) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+type CancelScheduledResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelScheduledResponse) Reset()         { *m = CancelScheduledResponse{} }
+func (m *CancelScheduledResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelScheduledResponse) ProtoMessage()    {}
+func (*CancelScheduledResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{67}
+}
+func (m *CancelScheduledResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelScheduledResponse.Unmarshal(m, b)
+}
+func (m *CancelScheduledResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelScheduledResponse.Marshal(b, m, deterministic)
+}
+func (dst *CancelScheduledResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelScheduledResponse.Merge(dst, src)
+}
+func (m *CancelScheduledResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelScheduledResponse.Size(m)
+}
+func (m *CancelScheduledResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelScheduledResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelScheduledResponse proto.InternalMessageInfo
+
+type MessageTTLRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageTTLRequest) Reset()         { *m = MessageTTLRequest{} }
+func (m *MessageTTLRequest) String() string { return proto.CompactTextString(m) }
+func (*MessageTTLRequest) ProtoMessage()    {}
+func (*MessageTTLRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{68}
+}
+func (m *MessageTTLRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageTTLRequest.Unmarshal(m, b)
+}
+func (m *MessageTTLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageTTLRequest.Marshal(b, m, deterministic)
+}
+func (dst *MessageTTLRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageTTLRequest.Merge(dst, src)
+}
+func (m *MessageTTLRequest) XXX_Size() int {
+	return xxx_messageInfo_MessageTTLRequest.Size(m)
+}
+func (m *MessageTTLRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageTTLRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageTTLRequest proto.InternalMessageInfo
+
+func (m *MessageTTLRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *MessageTTLRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type MessageTTLResponse struct {
+	Ttl                  int64    `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageTTLResponse) Reset()         { *m = MessageTTLResponse{} }
+func (m *MessageTTLResponse) String() string { return proto.CompactTextString(m) }
+func (*MessageTTLResponse) ProtoMessage()    {}
+func (*MessageTTLResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{69}
+}
+func (m *MessageTTLResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageTTLResponse.Unmarshal(m, b)
+}
+func (m *MessageTTLResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageTTLResponse.Marshal(b, m, deterministic)
+}
+func (dst *MessageTTLResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageTTLResponse.Merge(dst, src)
+}
+func (m *MessageTTLResponse) XXX_Size() int {
+	return xxx_messageInfo_MessageTTLResponse.Size(m)
+}
+func (m *MessageTTLResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageTTLResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageTTLResponse proto.InternalMessageInfo
+
+func (m *MessageTTLResponse) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type SetMessageTTLRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Ttl                  int64    `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMessageTTLRequest) Reset()         { *m = SetMessageTTLRequest{} }
+func (m *SetMessageTTLRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMessageTTLRequest) ProtoMessage()    {}
+func (*SetMessageTTLRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{70}
+}
+func (m *SetMessageTTLRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMessageTTLRequest.Unmarshal(m, b)
+}
+func (m *SetMessageTTLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMessageTTLRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetMessageTTLRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMessageTTLRequest.Merge(dst, src)
+}
+func (m *SetMessageTTLRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMessageTTLRequest.Size(m)
+}
+func (m *SetMessageTTLRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMessageTTLRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMessageTTLRequest proto.InternalMessageInfo
+
+func (m *SetMessageTTLRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetMessageTTLRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *SetMessageTTLRequest) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type SetMessageTTLResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMessageTTLResponse) Reset()         { *m = SetMessageTTLResponse{} }
+func (m *SetMessageTTLResponse) String() string { return proto.CompactTextString(m) }
+func (*SetMessageTTLResponse) ProtoMessage()    {}
+func (*SetMessageTTLResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{71}
+}
+func (m *SetMessageTTLResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMessageTTLResponse.Unmarshal(m, b)
+}
+func (m *SetMessageTTLResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMessageTTLResponse.Marshal(b, m, deterministic)
+}
+func (dst *SetMessageTTLResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMessageTTLResponse.Merge(dst, src)
+}
+func (m *SetMessageTTLResponse) XXX_Size() int {
+	return xxx_messageInfo_SetMessageTTLResponse.Size(m)
+}
+func (m *SetMessageTTLResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMessageTTLResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMessageTTLResponse proto.InternalMessageInfo
+
+type MembersRequest struct {
+	RoomId               string   `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Managers             bool     `protobuf:"varint,2,opt,name=managers,proto3" json:"managers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MembersRequest) Reset()         { *m = MembersRequest{} }
+func (m *MembersRequest) String() string { return proto.CompactTextString(m) }
+func (*MembersRequest) ProtoMessage()    {}
+func (*MembersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{72}
+}
+func (m *MembersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembersRequest.Unmarshal(m, b)
+}
+func (m *MembersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembersRequest.Marshal(b, m, deterministic)
+}
+func (dst *MembersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembersRequest.Merge(dst, src)
+}
+func (m *MembersRequest) XXX_Size() int {
+	return xxx_messageInfo_MembersRequest.Size(m)
+}
+func (m *MembersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembersRequest proto.InternalMessageInfo
+
+func (m *MembersRequest) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+func (m *MembersRequest) GetManagers() bool {
+	if m != nil {
+		return m.Managers
+	}
+	return false
+}
+
+type MembersResponse struct {
+	Users                []*User  `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MembersResponse) Reset()         { *m = MembersResponse{} }
+func (m *MembersResponse) String() string { return proto.CompactTextString(m) }
+func (*MembersResponse) ProtoMessage()    {}
+func (*MembersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{73}
+}
+func (m *MembersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembersResponse.Unmarshal(m, b)
+}
+func (m *MembersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembersResponse.Marshal(b, m, deterministic)
+}
+func (dst *MembersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembersResponse.Merge(dst, src)
+}
+func (m *MembersResponse) XXX_Size() int {
+	return xxx_messageInfo_MembersResponse.Size(m)
+}
+func (m *MembersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembersResponse proto.InternalMessageInfo
+
+func (m *MembersResponse) GetUsers() []*User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+type PresenceRequest struct {
+	Ids                  []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PresenceRequest) Reset()         { *m = PresenceRequest{} }
+func (m *PresenceRequest) String() string { return proto.CompactTextString(m) }
+func (*PresenceRequest) ProtoMessage()    {}
+func (*PresenceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{74}
+}
+func (m *PresenceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PresenceRequest.Unmarshal(m, b)
+}
+func (m *PresenceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PresenceRequest.Marshal(b, m, deterministic)
+}
+func (dst *PresenceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PresenceRequest.Merge(dst, src)
+}
+func (m *PresenceRequest) XXX_Size() int {
+	return xxx_messageInfo_PresenceRequest.Size(m)
+}
+func (m *PresenceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PresenceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PresenceRequest proto.InternalMessageInfo
+
+func (m *PresenceRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type PresenceResponse struct {
+	Presences            []*Presence `protobuf:"bytes,1,rep,name=presences,proto3" json:"presences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *PresenceResponse) Reset()         { *m = PresenceResponse{} }
+func (m *PresenceResponse) String() string { return proto.CompactTextString(m) }
+func (*PresenceResponse) ProtoMessage()    {}
+func (*PresenceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{75}
+}
+func (m *PresenceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PresenceResponse.Unmarshal(m, b)
+}
+func (m *PresenceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PresenceResponse.Marshal(b, m, deterministic)
+}
+func (dst *PresenceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PresenceResponse.Merge(dst, src)
+}
+func (m *PresenceResponse) XXX_Size() int {
+	return xxx_messageInfo_PresenceResponse.Size(m)
+}
+func (m *PresenceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PresenceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PresenceResponse proto.InternalMessageInfo
+
+func (m *PresenceResponse) GetPresences() []*Presence {
+	if m != nil {
+		return m.Presences
+	}
+	return nil
+}
+
+type Event struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	From                 string   `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Body                 string   `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	Parent               string   `protobuf:"bytes,7,opt,name=parent,proto3" json:"parent,omitempty"`
+	Mentions             []string `protobuf:"bytes,8,rep,name=mentions,proto3" json:"mentions,omitempty"`
+	Priority             string   `protobuf:"bytes,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	Expires              int64    `protobuf:"varint,10,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{76}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (dst *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(dst, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Event) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *Event) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *Event) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Event) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *Event) GetMentions() []string {
+	if m != nil {
+		return m.Mentions
+	}
+	return nil
+}
+
+func (m *Event) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
+func (m *Event) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type Room struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Room) Reset()         { *m = Room{} }
+func (m *Room) String() string { return proto.CompactTextString(m) }
+func (*Room) ProtoMessage()    {}
+func (*Room) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{77}
+}
+func (m *Room) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Room.Unmarshal(m, b)
+}
+func (m *Room) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Room.Marshal(b, m, deterministic)
+}
+func (dst *Room) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Room.Merge(dst, src)
+}
+func (m *Room) XXX_Size() int {
+	return xxx_messageInfo_Room.Size(m)
+}
+func (m *Room) XXX_DiscardUnknown() {
+	xxx_messageInfo_Room.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Room proto.InternalMessageInfo
+
+func (m *Room) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Room) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type User struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *User) Reset()         { *m = User{} }
+func (m *User) String() string { return proto.CompactTextString(m) }
+func (*User) ProtoMessage()    {}
+func (*User) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{78}
+}
+func (m *User) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_User.Unmarshal(m, b)
+}
+func (m *User) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_User.Marshal(b, m, deterministic)
+}
+func (dst *User) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_User.Merge(dst, src)
+}
+func (m *User) XXX_Size() int {
+	return xxx_messageInfo_User.Size(m)
+}
+func (m *User) XXX_DiscardUnknown() {
+	xxx_messageInfo_User.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_User proto.InternalMessageInfo
+
+func (m *User) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *User) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type Client struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Platform             string   `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	IsOnline             bool     `protobuf:"varint,3,opt,name=is_online,json=isOnline,proto3" json:"is_online,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Client) Reset()         { *m = Client{} }
+func (m *Client) String() string { return proto.CompactTextString(m) }
+func (*Client) ProtoMessage()    {}
+func (*Client) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{79}
+}
+func (m *Client) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Client.Unmarshal(m, b)
+}
+func (m *Client) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Client.Marshal(b, m, deterministic)
+}
+func (dst *Client) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Client.Merge(dst, src)
+}
+func (m *Client) XXX_Size() int {
+	return xxx_messageInfo_Client.Size(m)
+}
+func (m *Client) XXX_DiscardUnknown() {
+	xxx_messageInfo_Client.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Client proto.InternalMessageInfo
+
+func (m *Client) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Client) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *Client) GetIsOnline() bool {
+	if m != nil {
+		return m.IsOnline
+	}
+	return false
+}
+
+type Webhook struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Secret               string   `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	Events               []string `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	Rooms                []string `protobuf:"bytes,5,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	Active               bool     `protobuf:"varint,6,opt,name=active,proto3" json:"active,omitempty"`
+	Created              int64    `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Webhook) Reset()         { *m = Webhook{} }
+func (m *Webhook) String() string { return proto.CompactTextString(m) }
+func (*Webhook) ProtoMessage()    {}
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{80}
+}
+func (m *Webhook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Webhook.Unmarshal(m, b)
+}
+func (m *Webhook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Webhook.Marshal(b, m, deterministic)
+}
+func (dst *Webhook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Webhook.Merge(dst, src)
+}
+func (m *Webhook) XXX_Size() int {
+	return xxx_messageInfo_Webhook.Size(m)
+}
+func (m *Webhook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Webhook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Webhook proto.InternalMessageInfo
+
+func (m *Webhook) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Webhook) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Webhook) GetEvents() []string {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *Webhook) GetRooms() []string {
+	if m != nil {
+		return m.Rooms
+	}
+	return nil
+}
+
+func (m *Webhook) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *Webhook) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+type Bot struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Commands             []string `protobuf:"bytes,3,rep,name=commands,proto3" json:"commands,omitempty"`
+	Passthrough          bool     `protobuf:"varint,4,opt,name=passthrough,proto3" json:"passthrough,omitempty"`
+	Created              int64    `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Bot) Reset()         { *m = Bot{} }
+func (m *Bot) String() string { return proto.CompactTextString(m) }
+func (*Bot) ProtoMessage()    {}
+func (*Bot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{81}
+}
+func (m *Bot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bot.Unmarshal(m, b)
+}
+func (m *Bot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bot.Marshal(b, m, deterministic)
+}
+func (dst *Bot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bot.Merge(dst, src)
+}
+func (m *Bot) XXX_Size() int {
+	return xxx_messageInfo_Bot.Size(m)
+}
+func (m *Bot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bot proto.InternalMessageInfo
+
+func (m *Bot) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Bot) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Bot) GetCommands() []string {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+func (m *Bot) GetPassthrough() bool {
+	if m != nil {
+		return m.Passthrough
+	}
+	return false
+}
+
+func (m *Bot) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+type PreKey struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKey            []byte   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreKey) Reset()         { *m = PreKey{} }
+func (m *PreKey) String() string { return proto.CompactTextString(m) }
+func (*PreKey) ProtoMessage()    {}
+func (*PreKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{82}
+}
+func (m *PreKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreKey.Unmarshal(m, b)
+}
+func (m *PreKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreKey.Marshal(b, m, deterministic)
+}
+func (dst *PreKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreKey.Merge(dst, src)
+}
+func (m *PreKey) XXX_Size() int {
+	return xxx_messageInfo_PreKey.Size(m)
+}
+func (m *PreKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreKey proto.InternalMessageInfo
+
+func (m *PreKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PreKey) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+type KeyBundle struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IdentityKey          []byte   `protobuf:"bytes,2,opt,name=identity_key,json=identityKey,proto3" json:"identity_key,omitempty"`
+	Prekey               *PreKey  `protobuf:"bytes,3,opt,name=prekey,proto3" json:"prekey,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyBundle) Reset()         { *m = KeyBundle{} }
+func (m *KeyBundle) String() string { return proto.CompactTextString(m) }
+func (*KeyBundle) ProtoMessage()    {}
+func (*KeyBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{83}
+}
+func (m *KeyBundle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyBundle.Unmarshal(m, b)
+}
+func (m *KeyBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyBundle.Marshal(b, m, deterministic)
+}
+func (dst *KeyBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyBundle.Merge(dst, src)
+}
+func (m *KeyBundle) XXX_Size() int {
+	return xxx_messageInfo_KeyBundle.Size(m)
+}
+func (m *KeyBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyBundle.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyBundle proto.InternalMessageInfo
+
+func (m *KeyBundle) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *KeyBundle) GetIdentityKey() []byte {
+	if m != nil {
+		return m.IdentityKey
+	}
+	return nil
+}
+
+func (m *KeyBundle) GetPrekey() *PreKey {
+	if m != nil {
+		return m.Prekey
+	}
+	return nil
+}
+
+type Session struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Platform             string   `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	Ip                   string   `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Browser              string   `protobuf:"bytes,4,opt,name=browser,proto3" json:"browser,omitempty"`
+	Os                   string   `protobuf:"bytes,5,opt,name=os,proto3" json:"os,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	Active               bool     `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{84}
+}
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (dst *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(dst, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Session) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *Session) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *Session) GetBrowser() string {
+	if m != nil {
+		return m.Browser
+	}
+	return ""
+}
+
+func (m *Session) GetOs() string {
+	if m != nil {
+		return m.Os
+	}
+	return ""
+}
+
+func (m *Session) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Session) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+type Receipt struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Updated              int64    `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{85}
+}
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (dst *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(dst, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Receipt) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Receipt) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+type ReceiptSummary struct {
+	MessageId            string   `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	RoomId               string   `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Total                int64    `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Delivered            int64    `protobuf:"varint,5,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Read                 int64    `protobuf:"varint,6,opt,name=read,proto3" json:"read,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiptSummary) Reset()         { *m = ReceiptSummary{} }
+func (m *ReceiptSummary) String() string { return proto.CompactTextString(m) }
+func (*ReceiptSummary) ProtoMessage()    {}
+func (*ReceiptSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{86}
+}
+func (m *ReceiptSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiptSummary.Unmarshal(m, b)
+}
+func (m *ReceiptSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiptSummary.Marshal(b, m, deterministic)
+}
+func (dst *ReceiptSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiptSummary.Merge(dst, src)
+}
+func (m *ReceiptSummary) XXX_Size() int {
+	return xxx_messageInfo_ReceiptSummary.Size(m)
+}
+func (m *ReceiptSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiptSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiptSummary proto.InternalMessageInfo
+
+func (m *ReceiptSummary) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *ReceiptSummary) GetRoomId() string {
+	if m != nil {
+		return m.RoomId
+	}
+	return ""
+}
+
+func (m *ReceiptSummary) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ReceiptSummary) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *ReceiptSummary) GetDelivered() int64 {
+	if m != nil {
+		return m.Delivered
+	}
+	return 0
+}
+
+func (m *ReceiptSummary) GetRead() int64 {
+	if m != nil {
+		return m.Read
+	}
+	return 0
+}
+
+type Message struct {
+	Event                *Event      `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	ReplyCount           int64       `protobuf:"varint,2,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	LastReply            int64       `protobuf:"varint,3,opt,name=last_reply,json=lastReply,proto3" json:"last_reply,omitempty"`
+	Reactions            []*Reaction `protobuf:"bytes,4,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{87}
+}
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (dst *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(dst, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *Message) GetReplyCount() int64 {
+	if m != nil {
+		return m.ReplyCount
+	}
+	return 0
+}
+
+func (m *Message) GetLastReply() int64 {
+	if m != nil {
+		return m.LastReply
+	}
+	return 0
+}
+
+func (m *Message) GetReactions() []*Reaction {
+	if m != nil {
+		return m.Reactions
+	}
+	return nil
+}
+
+type Reaction struct {
+	Emoji                string   `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Reacted              bool     `protobuf:"varint,3,opt,name=reacted,proto3" json:"reacted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Reaction) Reset()         { *m = Reaction{} }
+func (m *Reaction) String() string { return proto.CompactTextString(m) }
+func (*Reaction) ProtoMessage()    {}
+func (*Reaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{88}
+}
+func (m *Reaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Reaction.Unmarshal(m, b)
+}
+func (m *Reaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Reaction.Marshal(b, m, deterministic)
+}
+func (dst *Reaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reaction.Merge(dst, src)
+}
+func (m *Reaction) XXX_Size() int {
+	return xxx_messageInfo_Reaction.Size(m)
+}
+func (m *Reaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Reaction proto.InternalMessageInfo
+
+func (m *Reaction) GetEmoji() string {
+	if m != nil {
+		return m.Emoji
+	}
+	return ""
+}
+
+func (m *Reaction) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Reaction) GetReacted() bool {
+	if m != nil {
+		return m.Reacted
+	}
+	return false
+}
+
+type Mention struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Event                *Event   `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Kind                 string   `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Read                 bool     `protobuf:"varint,4,opt,name=read,proto3" json:"read,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Mention) Reset()         { *m = Mention{} }
+func (m *Mention) String() string { return proto.CompactTextString(m) }
+func (*Mention) ProtoMessage()    {}
+func (*Mention) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{89}
+}
+func (m *Mention) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Mention.Unmarshal(m, b)
+}
+func (m *Mention) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Mention.Marshal(b, m, deterministic)
+}
+func (dst *Mention) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Mention.Merge(dst, src)
+}
+func (m *Mention) XXX_Size() int {
+	return xxx_messageInfo_Mention.Size(m)
+}
+func (m *Mention) XXX_DiscardUnknown() {
+	xxx_messageInfo_Mention.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Mention proto.InternalMessageInfo
+
+func (m *Mention) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Mention) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *Mention) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Mention) GetRead() bool {
+	if m != nil {
+		return m.Read
+	}
+	return false
+}
+
+type Notice struct {
+	Body                 string   `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`
+	From                 string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Updated              int64    `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Notice) Reset()         { *m = Notice{} }
+func (m *Notice) String() string { return proto.CompactTextString(m) }
+func (*Notice) ProtoMessage()    {}
+func (*Notice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{90}
+}
+func (m *Notice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Notice.Unmarshal(m, b)
+}
+func (m *Notice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Notice.Marshal(b, m, deterministic)
+}
+func (dst *Notice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Notice.Merge(dst, src)
+}
+func (m *Notice) XXX_Size() int {
+	return xxx_messageInfo_Notice.Size(m)
+}
+func (m *Notice) XXX_DiscardUnknown() {
+	xxx_messageInfo_Notice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Notice proto.InternalMessageInfo
+
+func (m *Notice) GetBody() string {
+	if m != nil {
+		return m.Body
+	}
+	return ""
+}
+
+func (m *Notice) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Notice) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+type ScheduledMessage struct {
+	Event                *Event   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	SendAt               int64    `protobuf:"varint,2,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduledMessage) Reset()         { *m = ScheduledMessage{} }
+func (m *ScheduledMessage) String() string { return proto.CompactTextString(m) }
+func (*ScheduledMessage) ProtoMessage()    {}
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{91}
+}
+func (m *ScheduledMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduledMessage.Unmarshal(m, b)
+}
+func (m *ScheduledMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduledMessage.Marshal(b, m, deterministic)
+}
+func (dst *ScheduledMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduledMessage.Merge(dst, src)
+}
+func (m *ScheduledMessage) XXX_Size() int {
+	return xxx_messageInfo_ScheduledMessage.Size(m)
+}
+func (m *ScheduledMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduledMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduledMessage proto.InternalMessageInfo
+
+func (m *ScheduledMessage) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *ScheduledMessage) GetSendAt() int64 {
+	if m != nil {
+		return m.SendAt
+	}
+	return 0
+}
+
+type Presence struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Online               bool     `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Presence) Reset()         { *m = Presence{} }
+func (m *Presence) String() string { return proto.CompactTextString(m) }
+func (*Presence) ProtoMessage()    {}
+func (*Presence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chat_d9755979aa374f81, []int{92}
+}
+func (m *Presence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Presence.Unmarshal(m, b)
+}
+func (m *Presence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Presence.Marshal(b, m, deterministic)
+}
+func (dst *Presence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Presence.Merge(dst, src)
+}
+func (m *Presence) XXX_Size() int {
+	return xxx_messageInfo_Presence.Size(m)
+}
+func (m *Presence) XXX_DiscardUnknown() {
+	xxx_messageInfo_Presence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Presence proto.InternalMessageInfo
+
+func (m *Presence) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Presence) GetOnline() bool {
+	if m != nil {
+		return m.Online
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*RegisterRequest)(nil), "go.micro.srv.chat.RegisterRequest")
 	proto.RegisterType((*RegisterResponse)(nil), "go.micro.srv.chat.RegisterResponse")
 	proto.RegisterType((*UnregisterRequest)(nil), "go.micro.srv.chat.UnregisterRequest")
 	proto.RegisterType((*UnregisterResponse)(nil), "go.micro.srv.chat.UnregisterResponse")
@@ -867,51 +4313,253 @@ func init() {
 	proto.RegisterType((*SendResponse)(nil), "go.micro.srv.chat.SendResponse")
 	proto.RegisterType((*StreamRequest)(nil), "go.micro.srv.chat.StreamRequest")
 	proto.RegisterType((*StreamResponse)(nil), "go.micro.srv.chat.StreamResponse")
+	proto.RegisterType((*CreateWebhookRequest)(nil), "go.micro.srv.chat.CreateWebhookRequest")
+	proto.RegisterType((*CreateWebhookResponse)(nil), "go.micro.srv.chat.CreateWebhookResponse")
+	proto.RegisterType((*DeleteWebhookRequest)(nil), "go.micro.srv.chat.DeleteWebhookRequest")
+	proto.RegisterType((*DeleteWebhookResponse)(nil), "go.micro.srv.chat.DeleteWebhookResponse")
+	proto.RegisterType((*WebhooksRequest)(nil), "go.micro.srv.chat.WebhooksRequest")
+	proto.RegisterType((*WebhooksResponse)(nil), "go.micro.srv.chat.WebhooksResponse")
+	proto.RegisterType((*CreateBotRequest)(nil), "go.micro.srv.chat.CreateBotRequest")
+	proto.RegisterType((*CreateBotResponse)(nil), "go.micro.srv.chat.CreateBotResponse")
+	proto.RegisterType((*DeleteBotRequest)(nil), "go.micro.srv.chat.DeleteBotRequest")
+	proto.RegisterType((*DeleteBotResponse)(nil), "go.micro.srv.chat.DeleteBotResponse")
+	proto.RegisterType((*BotsRequest)(nil), "go.micro.srv.chat.BotsRequest")
+	proto.RegisterType((*BotsResponse)(nil), "go.micro.srv.chat.BotsResponse")
+	proto.RegisterType((*BotSendRequest)(nil), "go.micro.srv.chat.BotSendRequest")
+	proto.RegisterType((*BotSendResponse)(nil), "go.micro.srv.chat.BotSendResponse")
+	proto.RegisterType((*UploadKeysRequest)(nil), "go.micro.srv.chat.UploadKeysRequest")
+	proto.RegisterType((*UploadKeysResponse)(nil), "go.micro.srv.chat.UploadKeysResponse")
+	proto.RegisterType((*FetchKeysRequest)(nil), "go.micro.srv.chat.FetchKeysRequest")
+	proto.RegisterType((*FetchKeysResponse)(nil), "go.micro.srv.chat.FetchKeysResponse")
+	proto.RegisterType((*KickRequest)(nil), "go.micro.srv.chat.KickRequest")
+	proto.RegisterType((*KickResponse)(nil), "go.micro.srv.chat.KickResponse")
+	proto.RegisterType((*SessionsRequest)(nil), "go.micro.srv.chat.SessionsRequest")
+	proto.RegisterType((*SessionsResponse)(nil), "go.micro.srv.chat.SessionsResponse")
+	proto.RegisterType((*RevokeSessionRequest)(nil), "go.micro.srv.chat.RevokeSessionRequest")
+	proto.RegisterType((*RevokeSessionResponse)(nil), "go.micro.srv.chat.RevokeSessionResponse")
+	proto.RegisterType((*ReceiptsRequest)(nil), "go.micro.srv.chat.ReceiptsRequest")
+	proto.RegisterType((*ReceiptsResponse)(nil), "go.micro.srv.chat.ReceiptsResponse")
+	proto.RegisterType((*HistoryRequest)(nil), "go.micro.srv.chat.HistoryRequest")
+	proto.RegisterType((*HistoryResponse)(nil), "go.micro.srv.chat.HistoryResponse")
+	proto.RegisterType((*ThreadRequest)(nil), "go.micro.srv.chat.ThreadRequest")
+	proto.RegisterType((*ThreadResponse)(nil), "go.micro.srv.chat.ThreadResponse")
+	proto.RegisterType((*FollowThreadRequest)(nil), "go.micro.srv.chat.FollowThreadRequest")
+	proto.RegisterType((*FollowThreadResponse)(nil), "go.micro.srv.chat.FollowThreadResponse")
+	proto.RegisterType((*UnfollowThreadRequest)(nil), "go.micro.srv.chat.UnfollowThreadRequest")
+	proto.RegisterType((*UnfollowThreadResponse)(nil), "go.micro.srv.chat.UnfollowThreadResponse")
+	proto.RegisterType((*ReactRequest)(nil), "go.micro.srv.chat.ReactRequest")
+	proto.RegisterType((*ReactResponse)(nil), "go.micro.srv.chat.ReactResponse")
+	proto.RegisterType((*MentionsRequest)(nil), "go.micro.srv.chat.MentionsRequest")
+	proto.RegisterType((*MentionsResponse)(nil), "go.micro.srv.chat.MentionsResponse")
+	proto.RegisterType((*PinRequest)(nil), "go.micro.srv.chat.PinRequest")
+	proto.RegisterType((*PinResponse)(nil), "go.micro.srv.chat.PinResponse")
+	proto.RegisterType((*UnpinRequest)(nil), "go.micro.srv.chat.UnpinRequest")
+	proto.RegisterType((*UnpinResponse)(nil), "go.micro.srv.chat.UnpinResponse")
+	proto.RegisterType((*PinnedRequest)(nil), "go.micro.srv.chat.PinnedRequest")
+	proto.RegisterType((*PinnedResponse)(nil), "go.micro.srv.chat.PinnedResponse")
+	proto.RegisterType((*SetNoticeRequest)(nil), "go.micro.srv.chat.SetNoticeRequest")
+	proto.RegisterType((*SetNoticeResponse)(nil), "go.micro.srv.chat.SetNoticeResponse")
+	proto.RegisterType((*ScheduledRequest)(nil), "go.micro.srv.chat.ScheduledRequest")
+	proto.RegisterType((*ScheduledResponse)(nil), "go.micro.srv.chat.ScheduledResponse")
+	proto.RegisterType((*EditScheduledRequest)(nil), "go.micro.srv.chat.EditScheduledRequest")
+	proto.RegisterType((*EditScheduledResponse)(nil), "go.micro.srv.chat.EditScheduledResponse")
+	proto.RegisterType((*CancelScheduledRequest)(nil), "go.micro.srv.chat.CancelScheduledRequest")
+	proto.RegisterType((*CancelScheduledResponse)(nil), "go.micro.srv.chat.CancelScheduledResponse")
+	proto.RegisterType((*MessageTTLRequest)(nil), "go.micro.srv.chat.MessageTTLRequest")
+	proto.RegisterType((*MessageTTLResponse)(nil), "go.micro.srv.chat.MessageTTLResponse")
+	proto.RegisterType((*SetMessageTTLRequest)(nil), "go.micro.srv.chat.SetMessageTTLRequest")
+	proto.RegisterType((*SetMessageTTLResponse)(nil), "go.micro.srv.chat.SetMessageTTLResponse")
+	proto.RegisterType((*MembersRequest)(nil), "go.micro.srv.chat.MembersRequest")
+	proto.RegisterType((*MembersResponse)(nil), "go.micro.srv.chat.MembersResponse")
+	proto.RegisterType((*PresenceRequest)(nil), "go.micro.srv.chat.PresenceRequest")
+	proto.RegisterType((*PresenceResponse)(nil), "go.micro.srv.chat.PresenceResponse")
 	proto.RegisterType((*Event)(nil), "go.micro.srv.chat.Event")
 	proto.RegisterType((*Room)(nil), "go.micro.srv.chat.Room")
 	proto.RegisterType((*User)(nil), "go.micro.srv.chat.User")
 	proto.RegisterType((*Client)(nil), "go.micro.srv.chat.Client")
+	proto.RegisterType((*Webhook)(nil), "go.micro.srv.chat.Webhook")
+	proto.RegisterType((*Bot)(nil), "go.micro.srv.chat.Bot")
+	proto.RegisterType((*PreKey)(nil), "go.micro.srv.chat.PreKey")
+	proto.RegisterType((*KeyBundle)(nil), "go.micro.srv.chat.KeyBundle")
+	proto.RegisterType((*Session)(nil), "go.micro.srv.chat.Session")
+	proto.RegisterType((*Receipt)(nil), "go.micro.srv.chat.Receipt")
+	proto.RegisterType((*ReceiptSummary)(nil), "go.micro.srv.chat.ReceiptSummary")
+	proto.RegisterType((*Message)(nil), "go.micro.srv.chat.Message")
+	proto.RegisterType((*Reaction)(nil), "go.micro.srv.chat.Reaction")
+	proto.RegisterType((*Mention)(nil), "go.micro.srv.chat.Mention")
+	proto.RegisterType((*Notice)(nil), "go.micro.srv.chat.Notice")
+	proto.RegisterType((*ScheduledMessage)(nil), "go.micro.srv.chat.ScheduledMessage")
+	proto.RegisterType((*Presence)(nil), "go.micro.srv.chat.Presence")
 }
 
-func init() { proto.RegisterFile("proto/chat.proto", fileDescriptor_chat_95643672631b59e8) }
-
-var fileDescriptor_chat_95643672631b59e8 = []byte{
-	// 577 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xcd, 0x87, 0x6d, 0x92, 0x49, 0x13, 0xda, 0x55, 0x05, 0x96, 0x11, 0x69, 0xd8, 0x82, 0x54,
-	0x81, 0x30, 0xa8, 0xc0, 0x91, 0x0a, 0xa9, 0x02, 0xa9, 0x08, 0x29, 0x8a, 0xab, 0x9c, 0x38, 0x20,
-	0x37, 0xde, 0xb6, 0x96, 0x62, 0x6f, 0xd8, 0xdd, 0x54, 0xea, 0x81, 0x33, 0x7f, 0x1b, 0xed, 0x87,
-	0x13, 0x27, 0x5d, 0x9b, 0xf6, 0x36, 0xe3, 0x79, 0xef, 0xed, 0xdb, 0xc9, 0xce, 0x04, 0x76, 0x17,
-	0x8c, 0x0a, 0xfa, 0x6e, 0x76, 0x1d, 0x8b, 0x50, 0x85, 0x68, 0xef, 0x8a, 0x86, 0x59, 0x3a, 0x63,
-	0x34, 0xe4, 0xec, 0x26, 0x94, 0x05, 0x7c, 0x02, 0x8f, 0x23, 0x72, 0x95, 0x72, 0x41, 0x58, 0x44,
-	0x7e, 0x2f, 0x09, 0x17, 0xe8, 0x0d, 0x38, 0x4b, 0x4e, 0x98, 0xdf, 0x1c, 0x35, 0x8f, 0x7a, 0xc7,
-	0x4f, 0xc3, 0x3b, 0xa4, 0x70, 0xca, 0x09, 0x8b, 0x14, 0x08, 0x23, 0xd8, 0x5d, 0xf3, 0xf9, 0x82,
-	0xe6, 0x9c, 0xe0, 0x43, 0xd8, 0x9b, 0xe6, 0x6c, 0x4b, 0x75, 0x00, 0xad, 0x34, 0x51, 0x9a, 0xdd,
-	0xa8, 0x95, 0x26, 0x78, 0x1f, 0x50, 0x19, 0x64, 0xa8, 0x43, 0xd8, 0x91, 0xe2, 0xbc, 0x8a, 0x75,
-	0x02, 0x7d, 0x53, 0xd7, 0x04, 0xf4, 0x16, 0x5c, 0xe9, 0x83, 0xfb, 0xcd, 0x51, 0xbb, 0xce, 0xad,
-	0x46, 0x49, 0xfd, 0x88, 0xd2, 0xac, 0x4e, 0xdf, 0xd4, 0xd7, 0xfa, 0x4c, 0x7e, 0xa8, 0xd1, 0x97,
-	0x84, 0x48, 0xa3, 0xf0, 0x27, 0xe8, 0x7d, 0xa7, 0x69, 0x5e, 0x21, 0x8f, 0x9e, 0x80, 0x27, 0x71,
-	0x67, 0x89, 0xdf, 0x52, 0xdf, 0x4c, 0x86, 0x07, 0xb0, 0xa3, 0x69, 0xa6, 0x0d, 0x1f, 0x01, 0xc6,
-	0x4b, 0xf1, 0x50, 0x95, 0x3e, 0xf4, 0x14, 0xcb, 0x88, 0x7c, 0x86, 0xde, 0x39, 0xc9, 0x93, 0x42,
-	0x25, 0x04, 0x97, 0xdc, 0x90, 0x5c, 0x98, 0xdf, 0xd5, 0xb7, 0xdc, 0xe4, 0xab, 0xac, 0x47, 0x1a,
-	0x26, 0x5b, 0xa5, 0xe9, 0xa6, 0x13, 0xdb, 0xad, 0x9a, 0x40, 0xff, 0x5c, 0x30, 0x12, 0x67, 0x55,
-	0x36, 0x03, 0xe8, 0x2c, 0xe6, 0xb1, 0xb8, 0xa4, 0x2c, 0x33, 0x46, 0x57, 0x39, 0xda, 0x07, 0x97,
-	0x8b, 0x98, 0x09, 0xbf, 0x3d, 0x6a, 0x1e, 0xb5, 0x23, 0x9d, 0xe0, 0x2f, 0x30, 0x28, 0x24, 0xcd,
-	0xa1, 0x0f, 0x35, 0xfd, 0x07, 0x5c, 0x95, 0xdf, 0x31, 0x83, 0xc0, 0x11, 0xb7, 0x0b, 0x62, 0x8c,
-	0xa8, 0x58, 0x7e, 0xbb, 0x64, 0x34, 0x53, 0x1e, 0xba, 0x91, 0x8a, 0x25, 0x4f, 0x50, 0xdf, 0xd1,
-	0x3c, 0x41, 0x25, 0xe6, 0x82, 0x26, 0xb7, 0xbe, 0xab, 0x31, 0x32, 0x46, 0x3e, 0x3c, 0x9a, 0x31,
-	0x12, 0x0b, 0x92, 0xf8, 0x9e, 0xb2, 0x5f, 0xa4, 0xf8, 0x35, 0x38, 0xf2, 0x35, 0xd8, 0x4e, 0xcf,
-	0xe3, 0x6c, 0x75, 0xba, 0x8c, 0x25, 0x56, 0xbe, 0xcc, 0x7b, 0x61, 0x27, 0xe0, 0x9d, 0xce, 0x53,
-	0xdb, 0xbd, 0xea, 0x9a, 0xfc, 0x0c, 0xba, 0x29, 0xff, 0x45, 0xf3, 0x79, 0x9a, 0x13, 0x75, 0xc9,
-	0x4e, 0xd4, 0x49, 0xf9, 0x58, 0xe5, 0xc7, 0x7f, 0x5d, 0x70, 0x4e, 0xaf, 0x63, 0x81, 0xa6, 0xd0,
-	0x29, 0x26, 0x18, 0x61, 0xdb, 0xf3, 0xde, 0x1c, 0xe4, 0xe0, 0xb0, 0x16, 0x63, 0xde, 0x5e, 0x03,
-	0xfd, 0x04, 0x58, 0xcf, 0x37, 0x7a, 0x69, 0x9b, 0xcb, 0xed, 0x1d, 0x11, 0xbc, 0xfa, 0x0f, 0x6a,
-	0x25, 0xfe, 0x03, 0x5c, 0xb5, 0x06, 0xd0, 0x41, 0xc5, 0xbc, 0x17, 0x03, 0x1e, 0x8c, 0xaa, 0x01,
-	0x65, 0x35, 0x35, 0xf4, 0x56, 0xb5, 0xf2, 0xba, 0xb0, 0xaa, 0x6d, 0xec, 0x0b, 0xdc, 0x40, 0x67,
-	0xe0, 0xc8, 0x59, 0x46, 0x43, 0x0b, 0xb6, 0xb4, 0x1b, 0x82, 0x83, 0xca, 0xfa, 0x4a, 0xea, 0x1b,
-	0xb4, 0xc7, 0x4b, 0x81, 0x9e, 0x5b, 0x90, 0xeb, 0xf5, 0x10, 0x0c, 0xab, 0xca, 0x65, 0x4b, 0x72,
-	0x94, 0xad, 0x96, 0x4a, 0x2b, 0xc2, 0x6a, 0xa9, 0xbc, 0x03, 0x70, 0x03, 0x4d, 0xc0, 0xd3, 0x23,
-	0x8a, 0x6c, 0xbd, 0xd8, 0x58, 0x08, 0xc1, 0x8b, 0x1a, 0x44, 0x21, 0xf8, 0xbe, 0x79, 0xe1, 0xa9,
-	0x3f, 0xa7, 0x0f, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0xa9, 0xe3, 0xd8, 0xf1, 0xb0, 0x06, 0x00,
-	0x00,
+func init() { proto.RegisterFile("proto/chat.proto", fileDescriptor_chat_d9755979aa374f81) }
+
+var fileDescriptor_chat_d9755979aa374f81 = []byte{
+	// 2648 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x1a, 0xed, 0x72, 0xdb, 0xc6,
+	0xd1, 0x14, 0xbf, 0xc4, 0x95, 0x44, 0x49, 0x88, 0x24, 0xd3, 0x4c, 0x6a, 0x5b, 0xa7, 0x7c, 0xb8,
+	0xe9, 0x54, 0x6d, 0xed, 0xf4, 0xd3, 0x6d, 0xda, 0x5a, 0xb1, 0x27, 0x4e, 0xec, 0x48, 0x81, 0x2c,
+	0xb7, 0x33, 0xe9, 0x54, 0x03, 0x91, 0x67, 0x09, 0x11, 0x09, 0x30, 0x00, 0x68, 0x57, 0xfd, 0xd7,
+	0x4e, 0x67, 0xfa, 0x04, 0x7d, 0x80, 0x4e, 0xff, 0xf4, 0x01, 0xfa, 0x4e, 0x7d, 0x8d, 0xee, 0xdd,
+	0xed, 0x01, 0x07, 0xf0, 0x00, 0x89, 0x4a, 0xff, 0x61, 0x8f, 0x7b, 0xbb, 0x7b, 0x7b, 0x7b, 0xfb,
+	0x49, 0x58, 0x9b, 0x44, 0x61, 0x12, 0xfe, 0x60, 0x70, 0xe6, 0x25, 0xbb, 0xf2, 0xd3, 0x59, 0x3f,
+	0x0d, 0x77, 0xc7, 0xfe, 0x20, 0x0a, 0x77, 0xe3, 0xe8, 0xf5, 0xae, 0xf8, 0x81, 0x7d, 0x0c, 0xab,
+	0x2e, 0x3f, 0xf5, 0xe3, 0x84, 0x47, 0x2e, 0xff, 0x66, 0xca, 0xe3, 0xc4, 0xf9, 0x1e, 0x34, 0xa6,
+	0x31, 0x8f, 0x7a, 0xb5, 0xbb, 0xb5, 0x7b, 0x4b, 0xf7, 0x6f, 0xee, 0xce, 0x6c, 0xda, 0x3d, 0xc2,
+	0x9f, 0x5d, 0x89, 0xc4, 0x1c, 0x58, 0xcb, 0xf6, 0xc7, 0x93, 0x30, 0x88, 0x39, 0xdb, 0x81, 0xf5,
+	0xa3, 0x20, 0x2a, 0x50, 0xed, 0xc2, 0x82, 0x3f, 0x94, 0x34, 0x3b, 0x2e, 0x7e, 0xb1, 0x0d, 0x70,
+	0x4c, 0x24, 0xda, 0x7a, 0x1b, 0x96, 0x05, 0xf1, 0xb8, 0x6c, 0xd7, 0xc7, 0xb0, 0x42, 0xbf, 0xab,
+	0x0d, 0xce, 0xf7, 0xa1, 0x29, 0xe4, 0x88, 0x11, 0xa7, 0x5e, 0x25, 0xad, 0xc2, 0x12, 0xf4, 0xdd,
+	0x30, 0x1c, 0x57, 0xd1, 0xa7, 0xdf, 0x33, 0xfa, 0x91, 0x58, 0xa8, 0xa0, 0x2f, 0x36, 0xb8, 0x0a,
+	0x8b, 0xfd, 0x18, 0x96, 0x3e, 0x0b, 0xfd, 0xa0, 0x84, 0xbc, 0xb3, 0x05, 0x2d, 0x81, 0xf7, 0x74,
+	0xd8, 0x5b, 0x90, 0x6b, 0x04, 0xb1, 0x2e, 0x2c, 0xab, 0x6d, 0xa4, 0x86, 0x8f, 0x00, 0xf6, 0xa7,
+	0xc9, 0xbc, 0x54, 0x56, 0x60, 0x49, 0xee, 0x22, 0x22, 0x2f, 0x61, 0xe9, 0x90, 0x07, 0x43, 0x4d,
+	0x65, 0x17, 0x9a, 0xfc, 0x35, 0x0f, 0x12, 0xba, 0xd7, 0x9e, 0xe5, 0x24, 0x8f, 0xc5, 0xef, 0xae,
+	0x42, 0x73, 0x6e, 0x42, 0x3b, 0xc6, 0xed, 0xc7, 0x5e, 0x22, 0xd9, 0xd4, 0xdd, 0x96, 0x00, 0x7f,
+	0x9b, 0x08, 0x1d, 0x2a, 0xba, 0xa4, 0xa2, 0xa2, 0x0e, 0xff, 0x59, 0x83, 0x95, 0xc3, 0x24, 0xe2,
+	0xde, 0xb8, 0xec, 0x00, 0x7d, 0x58, 0x9c, 0x8c, 0xbc, 0xe4, 0x55, 0x18, 0x8d, 0xe9, 0x08, 0x29,
+	0xec, 0x6c, 0x40, 0x33, 0x4e, 0xbc, 0x28, 0xe9, 0xd5, 0x25, 0x53, 0x05, 0x48, 0x0a, 0x93, 0x5e,
+	0x83, 0x28, 0x4c, 0x9c, 0x1e, 0xb4, 0x4f, 0xa2, 0xf0, 0x8d, 0x30, 0xd3, 0xa6, 0x5c, 0xd4, 0xa0,
+	0xc0, 0x0c, 0xe3, 0x5e, 0x4b, 0x61, 0x86, 0xb1, 0xa0, 0x97, 0x84, 0xe7, 0x3c, 0xe8, 0xb5, 0xe5,
+	0x92, 0x02, 0xd8, 0x5f, 0x6b, 0xd0, 0xd5, 0x32, 0xd2, 0x31, 0xe6, 0xd5, 0xcf, 0x5d, 0x58, 0x4a,
+	0x22, 0x6f, 0xc0, 0x27, 0x5e, 0x24, 0x76, 0xa9, 0x73, 0x98, 0x4b, 0xce, 0x6d, 0x00, 0x09, 0xe2,
+	0x11, 0x12, 0x2e, 0xcf, 0xd3, 0x71, 0x8d, 0x15, 0xf6, 0x0c, 0x36, 0xf6, 0x50, 0x84, 0x84, 0xff,
+	0x8e, 0x9f, 0x9c, 0x85, 0xe1, 0xb9, 0x56, 0xd7, 0x47, 0xd0, 0x7e, 0xa3, 0x56, 0x48, 0x96, 0xbe,
+	0x45, 0x16, 0xbd, 0x47, 0xa3, 0xb2, 0xe7, 0xb0, 0x59, 0xa0, 0x46, 0x07, 0xbb, 0x1e, 0xb9, 0xf7,
+	0x61, 0xe3, 0x13, 0x3e, 0xe2, 0x33, 0xc2, 0x65, 0x77, 0x59, 0x97, 0xb7, 0x7d, 0x13, 0x36, 0x0b,
+	0x78, 0x64, 0x7e, 0xeb, 0xb0, 0x4a, 0x4b, 0xfa, 0xb5, 0xb1, 0xcf, 0x60, 0x2d, 0x5b, 0x22, 0xe9,
+	0x7e, 0x02, 0x8b, 0xc4, 0x52, 0xbf, 0xb1, 0x2a, 0xf1, 0x52, 0x5c, 0xf6, 0x4b, 0x58, 0x53, 0xc7,
+	0x7d, 0x14, 0xa6, 0x0f, 0xe5, 0x1e, 0xd4, 0x4f, 0x42, 0x7d, 0x81, 0x5b, 0x16, 0x32, 0x02, 0x57,
+	0xa0, 0xb0, 0x43, 0x58, 0x37, 0x76, 0x93, 0x28, 0x57, 0xde, 0x9e, 0x19, 0xd5, 0x82, 0x69, 0x54,
+	0x0c, 0xd6, 0x94, 0x2a, 0x0c, 0x91, 0x8a, 0x8f, 0xe3, 0x2d, 0x58, 0x37, 0x70, 0x48, 0x55, 0xf8,
+	0x70, 0x11, 0x4c, 0xd5, 0xf4, 0x0b, 0x58, 0x56, 0x20, 0xc9, 0xf5, 0x21, 0x34, 0x90, 0xa9, 0x56,
+	0x4f, 0x99, 0x60, 0x12, 0x87, 0x85, 0xd0, 0x45, 0xc0, 0x7c, 0xf7, 0xa9, 0xac, 0x35, 0x43, 0xd6,
+	0xcc, 0xda, 0x17, 0xe6, 0xf6, 0x06, 0xf5, 0x9c, 0x37, 0xd8, 0x86, 0xd5, 0x94, 0x61, 0x89, 0x43,
+	0xf8, 0x47, 0x0d, 0x03, 0xc2, 0x64, 0x14, 0x7a, 0xc3, 0xcf, 0xf9, 0x45, 0x99, 0xeb, 0x75, 0xb6,
+	0x61, 0xd9, 0x1f, 0x22, 0x2b, 0x3f, 0xb9, 0x38, 0x3e, 0xe7, 0x17, 0x52, 0xb0, 0x65, 0x77, 0x49,
+	0xaf, 0xe1, 0x56, 0xe7, 0x01, 0xb4, 0x27, 0x11, 0xc7, 0x1f, 0x63, 0x14, 0x42, 0xe8, 0xe2, 0x96,
+	0x45, 0xec, 0x83, 0x88, 0x23, 0xae, 0xab, 0x31, 0x85, 0xab, 0x88, 0x38, 0xba, 0x97, 0x01, 0x97,
+	0xfe, 0x63, 0xd1, 0xd5, 0x20, 0xdb, 0xc5, 0x10, 0x64, 0x88, 0x45, 0xd2, 0xf7, 0x32, 0x26, 0xca,
+	0xca, 0x35, 0xc8, 0x1e, 0xc2, 0xda, 0x13, 0x9e, 0x0c, 0xce, 0xaa, 0x4e, 0x81, 0x7a, 0x12, 0x91,
+	0xe6, 0xd8, 0x4f, 0x9d, 0xb3, 0x00, 0xd1, 0x39, 0x3f, 0x85, 0x75, 0x63, 0x73, 0xfa, 0x34, 0x5b,
+	0x27, 0xd3, 0x60, 0x38, 0xe2, 0x64, 0x74, 0xef, 0x58, 0xce, 0x83, 0x1b, 0x1e, 0x49, 0x1c, 0x97,
+	0x70, 0xd9, 0x97, 0xb0, 0xf4, 0xb9, 0x3f, 0x38, 0xbf, 0x8e, 0x77, 0x15, 0xa1, 0x83, 0x7b, 0x71,
+	0x18, 0x90, 0x3b, 0x22, 0x88, 0xfd, 0x01, 0x96, 0x15, 0x49, 0x12, 0x0c, 0x8d, 0x26, 0x08, 0x87,
+	0x5c, 0xab, 0x40, 0x01, 0x82, 0x72, 0xcc, 0xe3, 0xd8, 0x47, 0x14, 0x8a, 0x09, 0x29, 0x2c, 0x7e,
+	0x1b, 0x84, 0xe3, 0x89, 0x30, 0x6d, 0x49, 0x7b, 0xd1, 0x4d, 0x61, 0x61, 0x23, 0x87, 0x84, 0x57,
+	0xf6, 0x2e, 0xd0, 0x35, 0x64, 0x28, 0x99, 0x6b, 0x48, 0xd9, 0x95, 0xbb, 0x06, 0xda, 0x96, 0x89,
+	0xc2, 0x1e, 0xc3, 0x86, 0xcb, 0x5f, 0xa3, 0x99, 0xeb, 0x9f, 0x4a, 0x14, 0xf5, 0x1d, 0x00, 0xda,
+	0xa3, 0xaf, 0xab, 0xee, 0x76, 0x68, 0x05, 0x6f, 0xec, 0x01, 0x6c, 0x16, 0xc8, 0x90, 0x5c, 0xe6,
+	0x51, 0x6b, 0x85, 0xa3, 0xfe, 0x46, 0xe4, 0x53, 0x03, 0xee, 0x4f, 0x92, 0xb8, 0x82, 0xed, 0x18,
+	0x29, 0x7a, 0xa7, 0x3c, 0xb3, 0x92, 0x0e, 0xad, 0x20, 0xdb, 0xbf, 0xd7, 0x44, 0x4a, 0xa5, 0x49,
+	0x10, 0xcb, 0x87, 0xf8, 0xfc, 0xa6, 0xe3, 0xb1, 0x17, 0x5d, 0x90, 0xa5, 0x6c, 0xdb, 0x12, 0x11,
+	0xb5, 0xeb, 0x50, 0x21, 0xba, 0x7a, 0x87, 0xd0, 0x63, 0x44, 0x04, 0x91, 0x5d, 0x99, 0x1e, 0x69,
+	0xb7, 0x9b, 0xe2, 0xb2, 0x3f, 0x42, 0xf7, 0x53, 0x4c, 0xcf, 0x42, 0xa4, 0x55, 0x72, 0x14, 0x84,
+	0x93, 0x90, 0x8e, 0x80, 0x5f, 0xc2, 0xbc, 0x4e, 0x38, 0x1a, 0x9a, 0x8e, 0x76, 0x04, 0x09, 0x73,
+	0x1a, 0xf9, 0x63, 0x3f, 0x91, 0x2f, 0x10, 0xcd, 0x49, 0x02, 0xf8, 0x24, 0x56, 0x53, 0xfa, 0xd9,
+	0x95, 0x93, 0x26, 0xaa, 0xae, 0xfc, 0xb9, 0x42, 0x71, 0x53, 0x5c, 0x76, 0x06, 0x2b, 0x2f, 0xce,
+	0xd0, 0x96, 0x87, 0x65, 0x92, 0xbe, 0x0d, 0x1d, 0x15, 0x95, 0x33, 0x9d, 0x2f, 0xaa, 0x85, 0xa7,
+	0x43, 0x21, 0x9e, 0xf7, 0x0a, 0xd3, 0x50, 0x92, 0x5a, 0x01, 0x25, 0x42, 0xff, 0x19, 0xba, 0x9a,
+	0x13, 0xc9, 0x7c, 0x1f, 0x5a, 0x94, 0x03, 0x94, 0x87, 0x57, 0x2d, 0x31, 0x61, 0x8a, 0x98, 0x2c,
+	0xbc, 0x90, 0xcf, 0xab, 0x6e, 0x44, 0x6f, 0xd2, 0xa8, 0xec, 0x11, 0xbc, 0xf5, 0x24, 0x1c, 0x8d,
+	0xc2, 0x37, 0xd7, 0x3f, 0x2b, 0xdb, 0x82, 0x8d, 0x3c, 0x0d, 0x8a, 0x41, 0x9f, 0xc0, 0xe6, 0x51,
+	0xf0, 0xea, 0xdb, 0x52, 0xef, 0xc1, 0x56, 0x91, 0x0a, 0xd1, 0x3f, 0xc7, 0xcc, 0x9b, 0x7b, 0x83,
+	0xe4, 0x7a, 0xaf, 0x42, 0x5c, 0x06, 0x1f, 0x87, 0x5f, 0xfb, 0xfa, 0x8a, 0x24, 0xa0, 0xdc, 0xd9,
+	0x38, 0x7c, 0xad, 0x5d, 0x3b, 0x41, 0xe8, 0x4d, 0x56, 0x88, 0x19, 0xdd, 0xd1, 0xcf, 0xa1, 0x13,
+	0x89, 0x05, 0xc3, 0x97, 0xbc, 0x6d, 0x7d, 0x03, 0x0a, 0xc7, 0xcd, 0xb0, 0xd9, 0x29, 0xac, 0x3e,
+	0x17, 0x21, 0xa8, 0xdc, 0x79, 0x19, 0x66, 0x4f, 0x99, 0x72, 0xd1, 0xec, 0xeb, 0x86, 0x05, 0x09,
+	0xec, 0x69, 0x20, 0x74, 0xa3, 0x85, 0x56, 0x90, 0x70, 0x81, 0x19, 0x23, 0xf3, 0x3d, 0x04, 0xc9,
+	0x25, 0x2e, 0x90, 0xb6, 0xb9, 0x29, 0x2e, 0x7b, 0x01, 0x70, 0x50, 0x5e, 0x86, 0x60, 0x90, 0x12,
+	0x25, 0x83, 0x11, 0xa4, 0x54, 0x05, 0x51, 0xb8, 0x84, 0x7a, 0xd1, 0x35, 0x61, 0x9e, 0x72, 0x60,
+	0x54, 0x29, 0x2f, 0xb1, 0x58, 0x0b, 0x26, 0xff, 0x7f, 0x36, 0xab, 0x58, 0xe4, 0x29, 0xba, 0xc4,
+	0xe8, 0x67, 0xb0, 0x82, 0x7c, 0x03, 0x3e, 0x9c, 0x97, 0x13, 0x8b, 0xa1, 0xab, 0x77, 0x92, 0x46,
+	0x7f, 0x04, 0xad, 0x20, 0x4c, 0xfc, 0x81, 0x0e, 0xb9, 0xb6, 0x14, 0xe2, 0x0b, 0x89, 0xe0, 0x12,
+	0x22, 0xe6, 0x4a, 0x0d, 0x94, 0xe6, 0x2a, 0x2f, 0x55, 0xe2, 0xb1, 0x7d, 0x11, 0xcb, 0x12, 0x22,
+	0x32, 0xaf, 0x6e, 0x1c, 0x91, 0xec, 0x0d, 0x2f, 0x48, 0x2b, 0xf2, 0x9b, 0x3d, 0x81, 0x75, 0x83,
+	0xe0, 0xb5, 0x0f, 0x22, 0x12, 0xd4, 0xc3, 0xc1, 0x19, 0x1f, 0x4e, 0x47, 0xa5, 0xaa, 0x44, 0xcb,
+	0x59, 0x37, 0x70, 0x88, 0xd7, 0xaf, 0x67, 0xdc, 0xf2, 0x8e, 0x2d, 0x12, 0xeb, 0x7d, 0xb3, 0xfe,
+	0x39, 0x80, 0x8d, 0xc7, 0x43, 0x3f, 0xb9, 0x8c, 0xbb, 0x73, 0x0b, 0x16, 0x65, 0xbe, 0x99, 0xe9,
+	0xa5, 0x2d, 0x61, 0xbb, 0x62, 0xcc, 0xac, 0xb4, 0x91, 0xcb, 0x4a, 0x5f, 0xc2, 0x66, 0x81, 0x1f,
+	0x9d, 0xe4, 0x57, 0xd0, 0x26, 0xa1, 0x48, 0x6d, 0x57, 0x3a, 0x88, 0xde, 0xc3, 0xf6, 0x60, 0x6b,
+	0xcf, 0x0b, 0x06, 0x7c, 0xf4, 0x2d, 0x4e, 0xc2, 0x6e, 0xc1, 0xcd, 0x19, 0x22, 0x64, 0xe9, 0x0f,
+	0x60, 0x9d, 0x78, 0xbe, 0x78, 0xf1, 0xec, 0x8a, 0x51, 0x17, 0x4b, 0x35, 0xc7, 0xdc, 0x44, 0x27,
+	0x5d, 0x83, 0x7a, 0x92, 0x8c, 0x28, 0x81, 0x13, 0x9f, 0xec, 0x53, 0xd8, 0x40, 0x33, 0x9a, 0x9b,
+	0xbe, 0xa6, 0x54, 0xcf, 0x28, 0x61, 0xd1, 0x57, 0xa0, 0x44, 0xf2, 0x3f, 0x86, 0xee, 0x73, 0x3e,
+	0x3e, 0x31, 0x3a, 0x38, 0x86, 0xa1, 0xd7, 0x72, 0x86, 0x8e, 0x59, 0xd4, 0xd8, 0x0b, 0x70, 0x7f,
+	0xa4, 0x92, 0x49, 0xcc, 0xa2, 0x34, 0x2c, 0xb2, 0xa8, 0x94, 0xcc, 0xf5, 0x1a, 0x3d, 0x3b, 0xb0,
+	0x8a, 0x85, 0x00, 0x5a, 0x43, 0xf6, 0x04, 0xf1, 0x18, 0xfe, 0x50, 0xed, 0xef, 0xb8, 0xe2, 0x13,
+	0x4b, 0xe6, 0xb5, 0x0c, 0x29, 0x8b, 0x14, 0x13, 0x5a, 0xab, 0x8a, 0x14, 0xe9, 0xbe, 0x0c, 0x9b,
+	0xfd, 0xb7, 0x06, 0x4d, 0x59, 0x34, 0xcd, 0x68, 0x14, 0x6d, 0x37, 0xb9, 0x98, 0x70, 0xd2, 0xa9,
+	0xfc, 0x16, 0x6b, 0xaf, 0xa2, 0x70, 0xac, 0xed, 0x59, 0x7c, 0x93, 0xe6, 0x1b, 0xa9, 0xe6, 0xb5,
+	0xcd, 0x37, 0x0d, 0x9b, 0xc7, 0xfa, 0x64, 0x20, 0x4b, 0xd7, 0xa1, 0xec, 0x72, 0x60, 0x7d, 0x42,
+	0xa0, 0x08, 0x2c, 0x94, 0x88, 0xa8, 0x5e, 0x87, 0x4e, 0x36, 0xfa, 0x46, 0x10, 0x59, 0x94, 0xa7,
+	0x4f, 0x61, 0x59, 0x2c, 0x44, 0x7e, 0x18, 0x61, 0x85, 0xd5, 0xeb, 0x50, 0x30, 0x27, 0x58, 0x70,
+	0xe2, 0x7f, 0x9a, 0xf8, 0x78, 0xbe, 0x1e, 0x28, 0x4e, 0x04, 0x32, 0xac, 0x48, 0x45, 0xd7, 0xcb,
+	0x76, 0xce, 0xc0, 0x1b, 0xa7, 0xe7, 0x14, 0xdf, 0x02, 0xf7, 0x88, 0x1a, 0x33, 0x97, 0xe2, 0x7e,
+	0x09, 0xad, 0x3d, 0xcc, 0x74, 0x82, 0xf9, 0x8a, 0x1a, 0xcc, 0x48, 0xfc, 0xf8, 0x38, 0x0c, 0x46,
+	0x7e, 0x90, 0xd6, 0x1e, 0x7e, 0xbc, 0x2f, 0x61, 0xf6, 0xaf, 0x1a, 0xb4, 0xa9, 0x7b, 0x50, 0xec,
+	0x5d, 0x08, 0x8b, 0x98, 0x46, 0x23, 0xa2, 0x27, 0x3e, 0x85, 0x0a, 0x63, 0x8e, 0xfa, 0x4c, 0x74,
+	0x02, 0xab, 0x20, 0xb1, 0x2e, 0x5f, 0x6f, 0x8c, 0x97, 0x23, 0x14, 0x48, 0x90, 0x88, 0xf0, 0xaa,
+	0x3d, 0xd8, 0x94, 0xcb, 0x0a, 0x10, 0xd8, 0x22, 0x7b, 0xc0, 0xb4, 0xa4, 0xa5, 0x22, 0xbc, 0x82,
+	0xcc, 0xab, 0x6b, 0xe7, 0xae, 0x8e, 0xfd, 0xa5, 0x06, 0x75, 0x2c, 0xa3, 0xaf, 0xa2, 0x24, 0x2a,
+	0x3f, 0xf0, 0xad, 0x0c, 0x55, 0x19, 0xdc, 0x71, 0x53, 0x58, 0x34, 0xa5, 0x26, 0x5e, 0x1c, 0x27,
+	0x67, 0x51, 0x38, 0x3d, 0x3d, 0xa3, 0x04, 0xc3, 0x5c, 0x32, 0x65, 0x68, 0xe6, 0x65, 0xf8, 0x29,
+	0xb4, 0x54, 0xed, 0x6c, 0xcb, 0xcd, 0x26, 0xd3, 0x93, 0x91, 0x3f, 0x30, 0x0a, 0xf3, 0x8e, 0x5a,
+	0x41, 0x74, 0x4c, 0x89, 0x3b, 0x69, 0x91, 0x6a, 0x16, 0xc0, 0x35, 0xb3, 0x00, 0xbe, 0x4a, 0x7d,
+	0x8f, 0x21, 0x4d, 0xd5, 0xda, 0x52, 0xfb, 0x95, 0xe5, 0x3d, 0x21, 0x8a, 0x66, 0x63, 0x9b, 0xea,
+	0xb3, 0x99, 0xeb, 0xad, 0xb2, 0x19, 0xd5, 0x50, 0xac, 0xdb, 0x1a, 0x8a, 0x0d, 0x5b, 0x43, 0xb1,
+	0x99, 0x36, 0x14, 0x2b, 0xdf, 0x1f, 0x5d, 0x7b, 0xdb, 0xbc, 0x76, 0x0c, 0xa9, 0x6d, 0x2a, 0xae,
+	0xca, 0xb5, 0x23, 0x0c, 0x2f, 0xf1, 0x92, 0x69, 0xac, 0xd3, 0x01, 0x05, 0x09, 0x6e, 0xd3, 0xc9,
+	0x50, 0x72, 0x53, 0xfe, 0x57, 0x83, 0xec, 0xdf, 0x35, 0xe8, 0xe6, 0x2b, 0xbe, 0x42, 0x5e, 0x55,
+	0x2b, 0xe6, 0xd0, 0xa5, 0x39, 0x47, 0xc6, 0xbc, 0x9e, 0x63, 0x2e, 0x5b, 0x47, 0x89, 0x37, 0xd2,
+	0x15, 0x90, 0x04, 0x9c, 0x77, 0xa0, 0x33, 0xe4, 0x23, 0x3c, 0x58, 0x94, 0xda, 0x50, 0xb6, 0x20,
+	0x2c, 0x56, 0xe6, 0xb6, 0x4a, 0x37, 0xf2, 0x9b, 0xfd, 0x07, 0x2f, 0x89, 0x82, 0xc5, 0xdc, 0x6d,
+	0xd6, 0x3b, 0xb0, 0x24, 0xca, 0x9f, 0x8b, 0xe3, 0x41, 0x38, 0x0d, 0x74, 0x2b, 0x1a, 0xe4, 0xd2,
+	0x9e, 0x58, 0x11, 0x87, 0x1e, 0x79, 0x71, 0x72, 0x2c, 0x97, 0x48, 0x49, 0x1d, 0xb1, 0xe2, 0x8a,
+	0x85, 0x7c, 0xe6, 0xdf, 0x98, 0x2b, 0xf3, 0x3f, 0x80, 0x45, 0xbd, 0x9c, 0xd5, 0x1f, 0x35, 0xb3,
+	0xfe, 0xc0, 0x55, 0x53, 0x2c, 0x05, 0xa8, 0x8e, 0x13, 0xee, 0xa3, 0x3b, 0x93, 0x1d, 0x27, 0x09,
+	0xb2, 0x6f, 0x84, 0x1e, 0xa4, 0xe7, 0x9d, 0x31, 0xd6, 0x79, 0x1b, 0x72, 0xa8, 0xe7, 0x73, 0x3f,
+	0xd0, 0xd9, 0xb3, 0xfc, 0x4e, 0x75, 0xaf, 0x9e, 0x7d, 0x83, 0xaa, 0x8a, 0x96, 0xca, 0x02, 0xd3,
+	0x60, 0x52, 0x33, 0x82, 0x89, 0x0e, 0x42, 0x0b, 0x46, 0x10, 0x2a, 0x37, 0xb9, 0xaf, 0x8c, 0xfc,
+	0xf1, 0xba, 0xf7, 0x59, 0x3a, 0x56, 0x78, 0x08, 0x8b, 0x3a, 0xa8, 0x56, 0x3e, 0x13, 0xf2, 0xf3,
+	0x2a, 0x65, 0x20, 0xe8, 0xfe, 0xdf, 0xfa, 0xd0, 0xd8, 0x43, 0x5e, 0xce, 0x91, 0xb8, 0x33, 0x35,
+	0x54, 0x72, 0x98, 0xf5, 0x9e, 0x73, 0x63, 0xa9, 0xfe, 0x4e, 0x25, 0x0e, 0x65, 0x35, 0x37, 0x9c,
+	0xaf, 0x00, 0xb2, 0x69, 0x95, 0xf3, 0xae, 0x2d, 0xf9, 0x28, 0x4e, 0xbc, 0xfa, 0xef, 0x5d, 0x82,
+	0x95, 0x12, 0x7f, 0x06, 0x4d, 0x39, 0xd4, 0x72, 0xee, 0x94, 0x24, 0x35, 0x3a, 0x99, 0xea, 0xdf,
+	0x2d, 0x47, 0x30, 0xa9, 0xc9, 0x11, 0x96, 0x95, 0x9a, 0x39, 0xfc, 0xb2, 0x52, 0xcb, 0x4d, 0xbf,
+	0x90, 0xda, 0x53, 0x68, 0x88, 0xc9, 0x94, 0x73, 0xdb, 0x82, 0x6b, 0x4c, 0xba, 0xfa, 0x77, 0x4a,
+	0x7f, 0x4f, 0x49, 0x3d, 0x81, 0xfa, 0xfe, 0x14, 0xdf, 0xab, 0x05, 0x33, 0x1b, 0x76, 0xf5, 0x6f,
+	0x97, 0xfd, 0x6c, 0x8a, 0x24, 0xda, 0xcd, 0x56, 0x91, 0x8c, 0xc6, 0xb7, 0x55, 0x24, 0xb3, 0x4f,
+	0x8d, 0xa4, 0x30, 0xdf, 0x50, 0x53, 0x20, 0xc7, 0xa6, 0x8b, 0xdc, 0x10, 0xab, 0xbf, 0x5d, 0x81,
+	0xa1, 0x09, 0xfe, 0xb0, 0xe6, 0x0c, 0x61, 0x25, 0x37, 0x86, 0x71, 0x3e, 0xb0, 0xec, 0xb3, 0x8d,
+	0x7d, 0xfa, 0xf7, 0x2e, 0x47, 0x4c, 0x05, 0x47, 0x2e, 0xb9, 0xa9, 0x8b, 0x95, 0x8b, 0x6d, 0x7e,
+	0x63, 0xe5, 0x62, 0x1f, 0xe0, 0xdc, 0x10, 0x8f, 0x49, 0xcf, 0x6b, 0xac, 0x8f, 0xa9, 0x30, 0xdf,
+	0xb1, 0x3e, 0xa6, 0xe2, 0xc0, 0x07, 0xc9, 0xfe, 0x1e, 0x3a, 0xe9, 0xf0, 0xc5, 0xd9, 0x29, 0x3d,
+	0x75, 0x36, 0x45, 0xe9, 0xbf, 0x5b, 0x8d, 0x64, 0x52, 0x4e, 0xa7, 0x2b, 0x56, 0xca, 0xc5, 0xf9,
+	0x8c, 0x95, 0xf2, 0xec, 0x80, 0x46, 0x1a, 0x9d, 0x98, 0xc9, 0x58, 0x8d, 0xce, 0x98, 0xdd, 0x58,
+	0x8d, 0xce, 0x1c, 0xe6, 0x20, 0x29, 0x17, 0xda, 0x34, 0x31, 0x71, 0xb6, 0xed, 0xd8, 0xa6, 0x15,
+	0xb3, 0x2a, 0x94, 0x9c, 0x7f, 0x4a, 0x47, 0x19, 0x76, 0xff, 0x54, 0x1c, 0xc0, 0xd8, 0xfd, 0xd3,
+	0xcc, 0x3c, 0x44, 0x69, 0x35, 0x1d, 0x5d, 0x58, 0xb5, 0x5a, 0x9c, 0x8a, 0x58, 0xb5, 0x3a, 0x33,
+	0xfd, 0x50, 0x5a, 0x15, 0x63, 0x07, 0xab, 0x56, 0x8d, 0x11, 0x87, 0x55, 0xab, 0xe6, 0xbc, 0x42,
+	0xd9, 0xaa, 0x1e, 0x20, 0x58, 0x6d, 0xb5, 0x30, 0x80, 0xb0, 0xda, 0x6a, 0x71, 0x02, 0xa1, 0x1e,
+	0x5a, 0x6e, 0x08, 0x60, 0x7d, 0x68, 0xb6, 0x69, 0x83, 0xf5, 0xa1, 0x59, 0xe7, 0x09, 0x4a, 0x78,
+	0xdd, 0xf2, 0x2f, 0x89, 0x5a, 0xb9, 0x91, 0x42, 0x49, 0xd4, 0xca, 0xcf, 0x0c, 0x94, 0xa5, 0x51,
+	0x83, 0xdd, 0x6a, 0x69, 0xf9, 0xe6, 0xbe, 0xd5, 0xd2, 0x0a, 0xfd, 0x79, 0xa4, 0xb9, 0x0f, 0x2d,
+	0xd5, 0xd9, 0xb5, 0xba, 0xcc, 0x5c, 0xeb, 0xd8, 0xea, 0x32, 0x0b, 0x6d, 0xe1, 0x1b, 0x8e, 0x07,
+	0xcb, 0x66, 0x43, 0xda, 0x79, 0xdf, 0x66, 0x3b, 0xb3, 0x7d, 0xe9, 0xfe, 0x07, 0x97, 0xe2, 0xa5,
+	0x2c, 0x4e, 0xa1, 0x9b, 0xef, 0x4a, 0x3b, 0xf7, 0xac, 0xb1, 0xd9, 0xd2, 0xfe, 0xee, 0x7f, 0xf7,
+	0x0a, 0x98, 0xb9, 0xd8, 0x2b, 0x52, 0x3d, 0x7b, 0xec, 0x35, 0xda, 0xdf, 0xf6, 0xd8, 0x6b, 0xb6,
+	0xac, 0x95, 0x55, 0xe8, 0x86, 0xb0, 0xd5, 0x2a, 0x0a, 0x6d, 0x69, 0xab, 0x55, 0x14, 0x3b, 0xca,
+	0x2a, 0x0e, 0x1f, 0x60, 0x44, 0xb7, 0xc5, 0xe1, 0xac, 0x67, 0x6c, 0x8d, 0xc3, 0x07, 0xb9, 0x78,
+	0x2e, 0xd2, 0x16, 0xd1, 0xa6, 0xb5, 0xa7, 0x2d, 0x46, 0x63, 0xd8, 0x9e, 0xb6, 0xe4, 0x3a, 0xbc,
+	0xd2, 0xae, 0x54, 0xa7, 0xd6, 0x6a, 0x57, 0xb9, 0xf6, 0xaf, 0xd5, 0xae, 0xf2, 0x6d, 0x5e, 0xe5,
+	0xb5, 0xd2, 0xa6, 0xa9, 0x63, 0x7f, 0xed, 0xf9, 0x1e, 0xad, 0xd5, 0x6b, 0xcd, 0xf4, 0x5d, 0x89,
+	0xb2, 0x4e, 0x83, 0x9d, 0xca, 0xfe, 0x61, 0x25, 0xe5, 0x99, 0xe6, 0x9f, 0xf4, 0x36, 0xb9, 0xb6,
+	0xa5, 0xd5, 0xdb, 0xd8, 0x1a, 0xa9, 0x56, 0x6f, 0x63, 0xed, 0x80, 0x22, 0x97, 0xaf, 0x61, 0xb5,
+	0xd0, 0x7f, 0x74, 0x6c, 0x56, 0x6e, 0x6f, 0x74, 0xf6, 0x3f, 0xbc, 0x0a, 0xaa, 0x19, 0x98, 0xb2,
+	0x36, 0xa1, 0x35, 0x30, 0xcd, 0xf4, 0x23, 0xad, 0x81, 0xc9, 0xd2, 0x6b, 0x94, 0xea, 0xca, 0xb5,
+	0x21, 0xad, 0xea, 0xb2, 0xb5, 0x3c, 0xad, 0xea, 0xb2, 0x77, 0x34, 0xa5, 0x17, 0xa5, 0x66, 0xa4,
+	0xd5, 0x8b, 0xe6, 0xfb, 0x9d, 0x7d, 0x56, 0x85, 0x62, 0x3e, 0xed, 0xb4, 0xd8, 0x61, 0x55, 0xed,
+	0xc5, 0x8a, 0xa7, 0x5d, 0x6c, 0x5d, 0xb2, 0x1b, 0x27, 0x2d, 0xf9, 0x3f, 0xbf, 0x07, 0xff, 0x03,
+	0x8c, 0x96, 0x4f, 0xe7, 0xfb, 0x27, 0x00, 0x00,
 }
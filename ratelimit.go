@@ -0,0 +1,269 @@
+package gochat
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/errors"
+)
+
+// Limit 令牌桶参数: 每秒补充Rate个令牌，最多积累Burst个
+// Rate为0表示不限制
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Limits struct {
+	// 每个用户发送频率
+	User Limit
+	// 每个房间接收频率(所有成员合计)
+	Room Limit
+	// 每个用户按事件类型的发送频率，如"candidate"可以放宽
+	Event map[string]Limit
+
+	// MuteWindow内违规MuteAfter次后禁言MuteDuration，MuteAfter为0时不禁言
+	MuteAfter    int
+	MuteWindow   time.Duration
+	MuteDuration time.Duration
+}
+
+var DefaultLimits = Limits{
+	User: Limit{Rate: 5, Burst: 10},
+	Room: Limit{Rate: 20, Burst: 40},
+	Event: map[string]Limit{
+		"candidate": {Rate: 50, Burst: 100},
+		"receipt":   {Rate: 20, Burst: 50},
+		"reaction":  {Rate: 10, Burst: 30},
+	},
+	MuteAfter:    10,
+	MuteWindow:   time.Minute,
+	MuteDuration: 5 * time.Minute,
+}
+
+// RateLimitError 超出频率限制，Body为json，可直接作为"error"事件内容下发
+type RateLimitError struct {
+	Code       string  `json:"code"`
+	Scope      string  `json:"scope"`       // user|room|event|muted
+	RetryAfter float64 `json:"retry_after"` // 秒
+	Muted      bool    `json:"muted"`
+}
+
+func (e *RateLimitError) Error() string {
+	b, _ := json.Marshal(e)
+	return string(b)
+}
+
+// ParseRateLimitError 从rpc返回的错误中解析频率限制错误
+func ParseRateLimitError(err error) (*RateLimitError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	e := errors.Parse(err.Error())
+	if e.Code != 429 {
+		return nil, false
+	}
+	rl := &RateLimitError{}
+	if err := json.Unmarshal([]byte(e.Detail), rl); err != nil {
+		return nil, false
+	}
+	return rl, true
+}
+
+// errorDetail 取出rpc错误的内容，本地调用时为原始错误信息
+func errorDetail(err error) string {
+	return errors.Parse(err.Error()).Detail
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limitCheck struct {
+	scope string
+	key   string
+	limit Limit
+}
+
+type violation struct {
+	count int
+	first time.Time
+}
+
+// RateLimiter 进程内令牌桶限流
+type RateLimiter struct {
+	mu         sync.Mutex
+	limits     Limits
+	buckets    map[string]*bucket
+	violations map[string]*violation
+	mutes      map[string]time.Time
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// Copy 深拷贝，修改副本的Event不影响原值
+func (l Limits) Copy() Limits {
+	c := l
+	c.Event = make(map[string]Limit, len(l.Event))
+	for k, v := range l.Event {
+		c.Event[k] = v
+	}
+	return c
+}
+
+// ParseEventLimits 解析按事件类型的频率限制，格式为 type=rate:burst，多个以逗号分隔
+// 如 "candidate=50:100,receipt=20:50"，省略burst时为rate的2倍
+func ParseEventLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, item := range splitIds(s) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("invalid event limit %q", item)
+		}
+		parts := strings.SplitN(kv[1], ":", 2)
+		rate, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid event limit %q", item)
+		}
+		burst := int(rate*2) + 1
+		if len(parts) == 2 {
+			if burst, err = strconv.Atoi(parts[1]); err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid event limit %q", item)
+			}
+		}
+		limits[kv[0]] = Limit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
+
+func NewRateLimiter(limits Limits) *RateLimiter {
+	return &RateLimiter{
+		limits:     limits.Copy(),
+		buckets:    make(map[string]*bucket),
+		violations: make(map[string]*violation),
+		mutes:      make(map[string]time.Time),
+		lastSweep:  time.Now(),
+		now:        time.Now,
+	}
+}
+
+// Allow 检查uid向roomId发送eventType类型事件是否超限，超限时返回*RateLimitError
+// 私聊时roomId为空
+func (r *RateLimiter) Allow(uid, roomId, eventType string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	// 禁言中
+	if until, ok := r.mutes[uid]; ok {
+		if now.Before(until) {
+			return &RateLimitError{
+				Code:       "rate_limited",
+				Scope:      "muted",
+				RetryAfter: until.Sub(now).Seconds(),
+				Muted:      true,
+			}
+		}
+		delete(r.mutes, uid)
+	}
+
+	checks := []limitCheck{
+		{"user", "user:" + uid, r.limits.User},
+		{"event", "event:" + eventType + ":" + uid, r.limits.Event[eventType]},
+	}
+	if len(roomId) > 0 {
+		checks = append(checks, limitCheck{"room", "room:" + roomId, r.limits.Room})
+	}
+
+	// 先全部检查再扣除，避免某一项超限时其它桶被白白消耗
+	for _, c := range checks {
+		if wait := r.wait(c.key, c.limit, now); wait > 0 {
+			return r.violate(uid, c.scope, wait, now)
+		}
+	}
+	for _, c := range checks {
+		if c.limit.Rate > 0 {
+			r.buckets[c.key].tokens--
+		}
+	}
+	return nil
+}
+
+// wait 补充令牌，返回需要等待的时间，0表示有可用令牌
+func (r *RateLimiter) wait(key string, limit Limit, now time.Time) time.Duration {
+	if limit.Rate <= 0 {
+		return 0
+	}
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		r.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+func (r *RateLimiter) violate(uid, scope string, wait time.Duration, now time.Time) error {
+	err := &RateLimitError{
+		Code:       "rate_limited",
+		Scope:      scope,
+		RetryAfter: wait.Seconds(),
+	}
+	// 房间的令牌桶由所有成员共用，超限不计入当前发送者的违规次数
+	if r.limits.MuteAfter <= 0 || scope == "room" {
+		return err
+	}
+
+	v, ok := r.violations[uid]
+	if !ok || now.Sub(v.first) > r.limits.MuteWindow {
+		v = &violation{first: now}
+		r.violations[uid] = v
+	}
+	v.count++
+	if v.count >= r.limits.MuteAfter {
+		delete(r.violations, uid)
+		r.mutes[uid] = now.Add(r.limits.MuteDuration)
+		err.Scope = "muted"
+		err.Muted = true
+		err.RetryAfter = r.limits.MuteDuration.Seconds()
+	}
+	return err
+}
+
+// sweep 定期清理已回满的令牌桶及过期的违规记录
+func (r *RateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+	r.lastSweep = now
+	for key, b := range r.buckets {
+		if now.Sub(b.last) > time.Minute {
+			delete(r.buckets, key)
+		}
+	}
+	for uid, v := range r.violations {
+		if now.Sub(v.first) > r.limits.MuteWindow {
+			delete(r.violations, uid)
+		}
+	}
+}
+
+// rateLimitError 转换为rpc错误，code为429
+func rateLimitError(service string, err error) error {
+	if _, ok := err.(*RateLimitError); !ok {
+		return err
+	}
+	return errors.New(service, err.Error(), 429)
+}
@@ -0,0 +1,66 @@
+package gochat_test
+
+import (
+	"testing"
+	"time"
+
+	gochat "github.com/laoqiu/go-chat"
+)
+
+// 房间超限不计入违规，只有用户及事件超限会导致禁言
+func TestRateLimitRoomNotMuted(t *testing.T) {
+	l := gochat.NewRateLimiter(gochat.Limits{
+		Room:         gochat.Limit{Rate: 0.001, Burst: 1},
+		MuteAfter:    2,
+		MuteWindow:   time.Minute,
+		MuteDuration: time.Minute,
+	})
+	if err := l.Allow("alice", "r1", "message"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		err := l.Allow("bob", "r1", "message")
+		rl, ok := err.(*gochat.RateLimitError)
+		if !ok || rl.Scope != "room" || rl.Muted {
+			t.Fatalf("Allow %d: err = %v, want room limit", i, err)
+		}
+	}
+	// 私聊不受房间限制，bob未被禁言
+	if err := l.Allow("bob", "", "message"); err != nil {
+		t.Fatalf("Allow direct: %v", err)
+	}
+}
+
+func TestRateLimitUserMuted(t *testing.T) {
+	l := gochat.NewRateLimiter(gochat.Limits{
+		User:         gochat.Limit{Rate: 0.001, Burst: 1},
+		MuteAfter:    2,
+		MuteWindow:   time.Minute,
+		MuteDuration: time.Minute,
+	})
+	l.Allow("bob", "", "message")
+	l.Allow("bob", "", "message")
+	err := l.Allow("bob", "", "message")
+	if rl, ok := err.(*gochat.RateLimitError); !ok || !rl.Muted {
+		t.Fatalf("Allow: err = %v, want muted", err)
+	}
+}
+
+func TestLimitsCopy(t *testing.T) {
+	limits := gochat.DefaultLimits.Copy()
+	limits.Event["candidate"] = gochat.Limit{Rate: 1, Burst: 1}
+	if gochat.DefaultLimits.Event["candidate"].Rate == 1 {
+		t.Fatal("Copy shares the Event map with DefaultLimits")
+	}
+
+	events, err := gochat.ParseEventLimits("candidate=50:100, receipt=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events["candidate"] != (gochat.Limit{Rate: 50, Burst: 100}) || events["receipt"] != (gochat.Limit{Rate: 20, Burst: 41}) {
+		t.Fatalf("ParseEventLimits: got %v", events)
+	}
+	if _, err := gochat.ParseEventLimits("candidate=fast"); err == nil {
+		t.Fatal("ParseEventLimits: invalid rate accepted")
+	}
+}
@@ -0,0 +1,171 @@
+package gochat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+const (
+	// 表情变化，只推送给在线连接，Body为ReactionUpdate
+	ReactionEvent = "reaction"
+)
+
+var ErrEmoji = errors.New("无效的表情")
+
+// ReactionUpdate 表情变化后推送的内容
+type ReactionUpdate struct {
+	MessageId string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+	Remove    bool   `json:"remove,omitempty"`
+	Count     int64  `json:"count"` // 变化后该表情的数量
+}
+
+// validEmoji 表情为不含空白的短字符串，不限制具体字符
+func validEmoji(emoji string) bool {
+	return len(emoji) > 0 && len(emoji) <= 32 && !strings.ContainsAny(emoji, " \t\r\n")
+}
+
+func (r *historyRepo) React(eventId, uid, emoji string) (bool, error) {
+	result, err := r.db.Exec(r.db.Rebind(r.dialect.insertIgnore("message_reactions",
+		[]string{"event_id", "user_id", "emoji"})), eventId, uid, emoji)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *historyRepo) Unreact(eventId, uid, emoji string) (bool, error) {
+	result, err := r.db.Exec(r.db.Rebind(`
+		DELETE FROM message_reactions WHERE event_id = ? AND user_id = ? AND emoji = ?
+		`), eventId, uid, emoji)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *historyRepo) Reactions(eventIds []string, uid string) (map[string][]*proto.Reaction, error) {
+	reactions := make(map[string][]*proto.Reaction)
+	if len(eventIds) == 0 {
+		return reactions, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT event_id, emoji, COUNT(*) AS count, SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS reacted
+		FROM message_reactions WHERE event_id IN (?)
+		GROUP BY event_id, emoji ORDER BY MIN(id)
+		`, uid, eventIds)
+	if err != nil {
+		return nil, err
+	}
+	rows := []struct {
+		EventId string `db:"event_id"`
+		Emoji   string `db:"emoji"`
+		Count   int64  `db:"count"`
+		Reacted int64  `db:"reacted"`
+	}{}
+	if err := r.db.Select(&rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		reactions[row.EventId] = append(reactions[row.EventId], &proto.Reaction{
+			Emoji:   row.Emoji,
+			Count:   row.Count,
+			Reacted: row.Reacted > 0,
+		})
+	}
+	return reactions, nil
+}
+
+// React 添加或取消表情，变化只推送给在线的会话成员，不进入持久化队列
+func (h *Handler) React(ctx context.Context, req *proto.ReactRequest, rsp *proto.ReactResponse) error {
+	if !validEmoji(req.Emoji) {
+		return ErrEmoji
+	}
+	msg, err := h.message(req.Id, req.MessageId)
+	if err != nil {
+		return err
+	}
+	if h.opts.RateLimiter != nil {
+		if err := h.opts.RateLimiter.Allow(req.Id, msg.RoomId, ReactionEvent); err != nil {
+			h.opts.Metrics.error("rate_limited")
+			return rateLimitError(h.service, err)
+		}
+	}
+
+	var changed bool
+	if req.Remove {
+		changed, err = h.opts.History.Unreact(msg.EventId, req.Id, req.Emoji)
+	} else {
+		changed, err = h.opts.History.React(msg.EventId, req.Id, req.Emoji)
+	}
+	if err != nil {
+		return err
+	}
+	reactions, err := h.opts.History.Reactions([]string{msg.EventId}, req.Id)
+	if err != nil {
+		return err
+	}
+	rsp.Reactions = reactions[msg.EventId]
+	if !changed {
+		return nil
+	}
+
+	update := &ReactionUpdate{MessageId: msg.EventId, Emoji: req.Emoji, Remove: req.Remove}
+	for _, r := range rsp.Reactions {
+		if r.Emoji == req.Emoji {
+			update.Count = r.Count
+		}
+	}
+	users := []string{msg.Sender, msg.Recipient}
+	if len(msg.RoomId) > 0 {
+		members, err := h.repo.Members(msg.RoomId, false)
+		if err != nil {
+			return err
+		}
+		users = users[:0]
+		for _, m := range members {
+			users = append(users, m.Id)
+		}
+	}
+	body, _ := json.Marshal(update)
+	h.ephemeral(ctx, users, &proto.Event{
+		Type:    ReactionEvent,
+		From:    req.Id,
+		To:      msg.Recipient,
+		Body:    string(body),
+		Created: time.Now().Unix(),
+	})
+	return nil
+}
+
+// withReactions 为历史消息附上表情汇总
+func (h *Handler) withReactions(uid string, msgs []*proto.Message) error {
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.Event.Id)
+	}
+	reactions, err := h.opts.History.Reactions(ids, uid)
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		m.Reactions = reactions[m.Event.Id]
+	}
+	return nil
+}
+
+// ephemeral 通过Hub推送给各节点上users的在线连接，失败只记录日志
+func (h *Handler) ephemeral(ctx context.Context, users []string, event *proto.Event) {
+	body, _ := json.Marshal(&EphemeralMessage{Users: users, Event: event})
+	if err := h.publish(ctx, ephemeralTopic(h.service), body); err != nil {
+		h.opts.Logger.Warn("ephemeral publish failed", F("type", event.Type), ErrField(err))
+	}
+}
@@ -0,0 +1,219 @@
+package gochat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+const (
+	// 接收者上报已读，Body为消息id，多条以逗号分隔
+	ReceiptEvent = "receipt"
+	// 推送给发送者的消息状态，Body为ReceiptSummary
+	StatusEvent = "status"
+)
+
+// 接收者状态，只前进不后退
+const (
+	ReceiptSent = iota + 1
+	ReceiptDelivered
+	ReceiptRead
+)
+
+var receiptStatus = map[int]string{
+	ReceiptSent:      "sent",
+	ReceiptDelivered: "delivered",
+	ReceiptRead:      "read",
+}
+
+// 需要跟踪状态的消息类型
+var receiptEvents = []string{"message", EncryptedEvent}
+
+// Receipt 消息在一个接收者上的状态
+type Receipt struct {
+	MessageId string    `db:"message_id"`
+	RoomId    string    `db:"room_id"`
+	Sender    string    `db:"sender"`
+	UserId    string    `db:"user_id"`
+	Status    int       `db:"status"`
+	Updated   time.Time `db:"updated"`
+}
+
+func (r *Receipt) ToProto() *proto.Receipt {
+	return &proto.Receipt{
+		UserId:  r.UserId,
+		Status:  receiptStatus[r.Status],
+		Updated: r.Updated.Unix(),
+	}
+}
+
+// summarize 汇总各接收者的状态，receipts不能为空
+func summarize(receipts []*Receipt) *proto.ReceiptSummary {
+	summary := &proto.ReceiptSummary{
+		MessageId: receipts[0].MessageId,
+		RoomId:    receipts[0].RoomId,
+		Total:     int64(len(receipts)),
+	}
+	status := ReceiptRead
+	for _, r := range receipts {
+		if r.Status >= ReceiptDelivered {
+			summary.Delivered++
+		}
+		if r.Status == ReceiptRead {
+			summary.Read++
+		}
+		if r.Status < status {
+			status = r.Status
+		}
+	}
+	summary.Status = receiptStatus[status]
+	return summary
+}
+
+type ReceiptRepository interface {
+	// 记录消息已发送给各接收者
+	Sent(messageId, roomId, sender string, recipients []string) error
+	// 更新接收者的状态，返回状态是否前进
+	Mark(messageId, uid string, status int) (bool, error)
+	// 消息所有接收者的状态，不存在时返回ErrNotFound
+	Receipts(messageId string) ([]*Receipt, error)
+}
+
+func NewReceiptRepo(db *sqlx.DB) *receiptRepo {
+	return &receiptRepo{
+		db:      db,
+		dialect: DialectOf(db),
+	}
+}
+
+type receiptRepo struct {
+	db      *sqlx.DB
+	dialect Dialect
+}
+
+func (r *receiptRepo) Sent(messageId, roomId, sender string, recipients []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := tx.Rebind(r.dialect.insertIgnore("message_receipts",
+		[]string{"message_id", "room_id", "sender", "user_id", "status", "updated"}))
+	now := time.Now()
+	for _, uid := range recipients {
+		if _, err := tx.Exec(query, messageId, roomId, sender, uid, ReceiptSent, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *receiptRepo) Mark(messageId, uid string, status int) (bool, error) {
+	result, err := r.db.Exec(r.db.Rebind(`
+		UPDATE message_receipts SET status = ?, updated = ? WHERE message_id = ? AND user_id = ? AND status < ?
+		`), status, time.Now(), messageId, uid, status)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *receiptRepo) Receipts(messageId string) ([]*Receipt, error) {
+	receipts := []*Receipt{}
+	if err := r.db.Select(&receipts, r.db.Rebind(`
+		SELECT message_id, room_id, sender, user_id, status, updated FROM message_receipts
+		WHERE message_id = ? ORDER BY user_id
+		`), messageId); err != nil {
+		return nil, err
+	}
+	if len(receipts) == 0 {
+		return nil, ErrNotFound
+	}
+	return receipts, nil
+}
+
+// Receipts 查询自己发送的消息在各接收者上的状态
+func (h *Handler) Receipts(ctx context.Context, req *proto.ReceiptsRequest, rsp *proto.ReceiptsResponse) error {
+	if h.opts.Receipts == nil {
+		return errors.New("消息回执未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	receipts, err := h.opts.Receipts.Receipts(req.MessageId)
+	if err != nil {
+		return err
+	}
+	if receipts[0].Sender != req.Id {
+		return ErrNotFound
+	}
+	rsp.Summary = summarize(receipts)
+	for _, r := range receipts {
+		rsp.Receipts = append(rsp.Receipts, r.ToProto())
+	}
+	return nil
+}
+
+// sent 消息投递到各接收者的队列后记录为已发送
+func (h *Handler) sent(event *proto.Event, roomId string, recipients []string) {
+	if h.opts.Receipts == nil || !in(receiptEvents, event.Type) || len(recipients) == 0 {
+		return
+	}
+	if err := h.opts.Receipts.Sent(event.Id, roomId, event.From, recipients); err != nil {
+		h.opts.Logger.Warn("receipt sent failed", EventIdField(event.Id), RoomField(roomId), ErrField(err))
+	}
+}
+
+// delivered Conn推送给在线stream后记录为已送达
+func (h *Handler) delivered(ctx context.Context, uid string, event *proto.Event) {
+	if !in(receiptEvents, event.Type) {
+		return
+	}
+	h.mark(ctx, uid, event.Id, ReceiptDelivered)
+}
+
+// read 处理接收者上报的已读回执
+func (h *Handler) read(ctx context.Context, uid, body string) error {
+	for _, id := range splitIds(body) {
+		if err := h.mark(ctx, uid, id, ReceiptRead); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mark 状态前进时通知发送者汇总后的状态
+func (h *Handler) mark(ctx context.Context, uid, messageId string, status int) error {
+	changed, err := h.opts.Receipts.Mark(messageId, uid, status)
+	if err != nil {
+		h.opts.Logger.Warn("receipt mark failed", UserField(uid), EventIdField(messageId), F("status", receiptStatus[status]), ErrField(err))
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	receipts, err := h.opts.Receipts.Receipts(messageId)
+	if err != nil {
+		return err
+	}
+	summary, _ := json.Marshal(summarize(receipts))
+	sender := receipts[0].Sender
+	event, _ := json.Marshal(&proto.Event{
+		Id:      newEventId(),
+		Type:    StatusEvent,
+		To:      sender,
+		Body:    string(summary),
+		Created: time.Now().Unix(),
+	})
+	if err := h.publish(ctx, h.service+"."+sender, event); err != nil {
+		h.opts.Logger.Warn("status publish failed", UserField(sender), EventIdField(messageId), ErrField(err))
+		return err
+	}
+	return nil
+}
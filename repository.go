@@ -2,25 +2,27 @@ package gochat
 
 import (
 	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	proto "github.com/laoqiu/go-chat/proto"
-	"github.com/laoqiu/sqlxt"
 )
 
-var (
-	Debug = true
-)
+// ErrNotFound 用户或房间不存在，所有Repository实现统一返回此错误
+var ErrNotFound = errors.New("记录不存在")
 
 type Repository interface {
 	NewTx() (*sqlx.Tx, error)
-	// 查询用户已登录客户端
+	// 查询用户在平台上的登录状态，未登录过时返回空的Client
 	AvailableClient(uid, platform string) (*proto.Client, error)
 	// 房间列表
 	RequestRooms(uid string) ([]*proto.Room, error)
 	// 好友列表
 	RequestUsers(uid string) ([]*proto.User, error)
-	// 查询用户
+	// 查询用户，不存在时返回ErrNotFound
 	GetUser(id string) (*proto.User, error)
 	// 注册用户
 	CreateUser(user *proto.User) error
@@ -29,34 +31,66 @@ type Repository interface {
 	// 注销用户
 	DeleteUser(id string) error
 	// TODO 按关键字搜索房间
-	// TODO 获取房间信息
+	// 获取房间信息，不存在时返回ErrNotFound
 	GetRoom(id string) (*proto.Room, error)
-	// TODO 创建房间(群聊)
+	// 创建房间(群聊)，id由仓库生成
 	CreateRoom(room *proto.Room) error
-	// TODO 更新部分群组信息
+	// 更新群组名称，不存在时返回ErrNotFound
 	UpdateRoom(room *proto.Room) error
-	// TODO 删除房间
+	// 删除房间及其成员
 	DeleteRoom(roomId string) error
+	// 房间公告，未设置时Body为空，房间不存在时返回ErrNotFound
+	Notice(roomId string) (*proto.Notice, error)
+	// 设置房间公告，房间不存在时返回ErrNotFound
+	SetNotice(roomId string, notice *proto.Notice) error
 	// 成员列表
 	Members(roomId string, onlyManager bool) ([]*proto.User, error)
-	// TODO 加入房间
+	// 加入房间，房间不存在时返回ErrNotFound
 	Join(uid, roomId string) error
-	// TODO 退出房间
+	// 退出房间
 	Out(uid, roomId string) error
 	// 上线
 	Online(uid, platform string) error
 	// 下线
 	Offline(uid, platform string) error
+	// uids中任一平台在线的用户
+	OnlineUsers(uids []string) ([]string, error)
+	// 记录登录信息，login.Id由仓库生成
+	LogLogin(login *Login) error
+	// 最近的登录记录，按时间倒序
+	Logins(uid string, limit int) ([]*Login, error)
+	// 查询登录记录，不存在时返回ErrNotFound
+	GetLogin(id int64) (*Login, error)
 }
 
-func NewChatRepo(db *sqlx.DB) *chatRepo {
+func NewChatRepo(db *sqlx.DB, logger Logger) *chatRepo {
 	return &chatRepo{
-		db: db,
+		db:      db,
+		dialect: DialectOf(db),
+		log:     loggerOrDefault(logger),
 	}
 }
 
 type chatRepo struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	dialect Dialect
+	log     Logger
+}
+
+// notFound 将sql.ErrNoRows转换为ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// q 按方言转换占位符，日志级别为debug时输出sql，可在运行时切换
+func (r *chatRepo) q(query string) string {
+	if r.log.Enabled(LevelDebug) {
+		r.log.Debug("sql", F("query", strings.Join(strings.Fields(query), " ")))
+	}
+	return r.db.Rebind(query)
 }
 
 func (r *chatRepo) NewTx() (*sqlx.Tx, error) {
@@ -65,9 +99,9 @@ func (r *chatRepo) NewTx() (*sqlx.Tx, error) {
 
 func (r *chatRepo) AvailableClient(uid, platform string) (*proto.Client, error) {
 	client := &proto.Client{}
-	if err := r.db.Get(client, `
-		SELECT id, platform, is_online FROM user_status WHERE id = ? AND platform = ?
-		`, uid, platform); err != nil && err != sql.ErrNoRows {
+	if err := r.db.Get(client, r.q(`
+		SELECT user_id AS id, platform, is_online FROM user_status WHERE user_id = ? AND platform = ?
+		`), uid, platform); err != nil && err != sql.ErrNoRows {
 		return client, err
 	}
 	return client, nil
@@ -75,92 +109,195 @@ func (r *chatRepo) AvailableClient(uid, platform string) (*proto.Client, error)
 
 func (r *chatRepo) RequestRooms(uid string) ([]*proto.Room, error) {
 	rooms := []*proto.Room{}
-	err := r.db.Select(&rooms, `
+	err := r.db.Select(&rooms, r.q(`
 		SELECT g.id, g.name FROM chatgroup_members AS gm JOIN chatgroup AS g ON g.id = gm.group_id Where gm.member = ?
-	`, uid)
+	`), uid)
 	return rooms, err
 }
 
 func (r *chatRepo) RequestUsers(uid string) ([]*proto.User, error) {
 	users := []*proto.User{}
-	err := r.db.Select(&users, `SELECT id, name FROM users`)
+	err := r.db.Select(&users, r.q(`SELECT id, name FROM users`))
 	return users, err
 }
 
 func (r *chatRepo) CreateUser(user *proto.User) error {
-	_, err := sqlxt.New(r.db, sqlxt.Table("users"), Debug).InsertIgnore(user)
+	_, err := r.db.Exec(r.q(r.dialect.insertIgnore("users", []string{"id", "name"})), user.Id, user.Name)
 	return err
 }
 
 func (r *chatRepo) UpdateUser(user *proto.User) error {
-	_, err := sqlxt.New(r.db, sqlxt.Table("users"), Debug).InsertOnDuplicateUpdate(user)
+	_, err := r.db.Exec(r.q(r.dialect.upsert("users",
+		[]string{"id", "name"}, []string{"id"}, []string{"name"},
+	)), user.Id, user.Name)
 	return err
 }
 
 func (r *chatRepo) DeleteUser(id string) error {
-	_, err := sqlxt.New(r.db, sqlxt.Table("users").Where("id = ?", id), Debug).Delete()
+	_, err := r.db.Exec(r.q(`DELETE FROM users WHERE id = ?`), id)
 	return err
 }
 
 func (r *chatRepo) GetUser(id string) (*proto.User, error) {
 	user := &proto.User{}
-	err := sqlxt.New(r.db, sqlxt.Table("users").Fields("id", "name"), Debug).Get(user)
-	return user, err
+	err := r.db.Get(user, r.q(`SELECT id, name FROM users WHERE id = ?`), id)
+	return user, notFound(err)
 }
 
 func (r *chatRepo) GetRoom(id string) (*proto.Room, error) {
 	room := &proto.Room{}
-	err := sqlxt.New(r.db, sqlxt.Table("chatgroup").Where("id = ?", id), Debug).Get(room)
-	return room, err
+	err := r.db.Get(room, r.q(`SELECT id, name FROM chatgroup WHERE id = ?`), id)
+	return room, notFound(err)
 }
 
 func (r *chatRepo) CreateRoom(room *proto.Room) error {
+	id, err := insertId(r.db, r.q(`INSERT INTO chatgroup (name, owner) VALUES (?, ?)`), room.Name, "")
+	if err != nil {
+		return err
+	}
+	room.Id = strconv.FormatInt(id, 10)
 	return nil
 }
 
 func (r *chatRepo) UpdateRoom(room *proto.Room) error {
-	return nil
+	if _, err := r.GetRoom(room.Id); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(r.q(`
+		UPDATE chatgroup SET name = ?, updated = ? WHERE id = ?
+		`), room.Name, time.Now(), room.Id)
+	return err
+}
+
+func (r *chatRepo) Notice(roomId string) (*proto.Notice, error) {
+	row := struct {
+		Notice  sql.NullString `db:"notice"`
+		By      sql.NullString `db:"notice_by"`
+		Updated sql.NullInt64  `db:"notice_updated"`
+	}{}
+	if err := r.db.Get(&row, r.q(`
+		SELECT notice, notice_by, notice_updated FROM chatgroup WHERE id = ?
+		`), roomId); err != nil {
+		return &proto.Notice{}, notFound(err)
+	}
+	return &proto.Notice{Body: row.Notice.String, From: row.By.String, Updated: row.Updated.Int64}, nil
+}
+
+func (r *chatRepo) SetNotice(roomId string, notice *proto.Notice) error {
+	if _, err := r.GetRoom(roomId); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(r.q(`
+		UPDATE chatgroup SET notice = ?, notice_by = ?, notice_updated = ? WHERE id = ?
+		`), notice.Body, notice.From, notice.Updated, roomId)
+	return err
 }
 
 func (r *chatRepo) DeleteRoom(roomId string) error {
-	return nil
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(r.q(`DELETE FROM chatgroup_members WHERE group_id = ?`), roomId); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(r.q(`DELETE FROM chatgroup WHERE id = ?`), roomId); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *chatRepo) Members(roomId string, onlyManager bool) ([]*proto.User, error) {
 	users := []*proto.User{}
-	query := sqlxt.Table("chatgroup_members").Fields("users.id", "users.name").
-		Join("users", "users.id = chatgroup_members.member").
-		Where("group_id = ?", roomId)
+	query := `
+		SELECT users.id, users.name FROM chatgroup_members JOIN users ON users.id = chatgroup_members.member
+		WHERE chatgroup_members.group_id = ?`
+	args := []interface{}{roomId}
 	if onlyManager {
-		query = query.Where("chatgroup_members.is_manager = 1")
+		query += ` AND chatgroup_members.is_manager = ?`
+		args = append(args, true)
 	}
-	err := sqlxt.New(r.db, query, Debug).All(&users)
+	err := r.db.Select(&users, r.q(query), args...)
 	return users, err
 }
 
 func (r *chatRepo) Join(uid, roomId string) error {
-	return nil
+	if _, err := r.GetRoom(roomId); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(r.q(r.dialect.insertIgnore("chatgroup_members", []string{"group_id", "member"})), roomId, uid)
+	return err
 }
 
 func (r *chatRepo) Out(uid, roomId string) error {
-	return nil
+	_, err := r.db.Exec(r.q(`
+		DELETE FROM chatgroup_members WHERE group_id = ? AND member = ?
+		`), roomId, uid)
+	return err
 }
 
 func (r *chatRepo) Online(uid, platform string) error {
-	if _, err := r.db.Exec(`
-		INSERT INTO user_status (user_id, platform, is_online) VALUES (?, ?, 1) 
-		ON DUPLICATE KEY UPDATE is_online = 1
-		`, uid, platform); err != nil {
+	if _, err := r.db.Exec(r.q(r.dialect.upsert("user_status",
+		[]string{"user_id", "platform", "is_online", "updated"},
+		[]string{"user_id", "platform"},
+		[]string{"is_online", "updated"},
+	)), uid, platform, true, time.Now()); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (r *chatRepo) Offline(uid, platform string) error {
-	if _, err := r.db.Exec(`
-		UPDATE user_status SET is_online = 0 WHERE user_id = ? AND platform = ?
-		`, uid, platform); err != nil {
+	if _, err := r.db.Exec(r.q(`
+		UPDATE user_status SET is_online = ?, updated = ? WHERE user_id = ? AND platform = ?
+		`), false, time.Now(), uid, platform); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *chatRepo) OnlineUsers(uids []string) ([]string, error) {
+	users := []string{}
+	if len(uids) == 0 {
+		return users, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT DISTINCT user_id FROM user_status WHERE is_online = ? AND user_id IN (?) ORDER BY user_id
+		`, true, uids)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Select(&users, r.q(query), args...)
+	return users, err
+}
+
+func (r *chatRepo) LogLogin(login *Login) error {
+	login.Created = time.Now()
+	id, err := insertId(r.db, r.q(`
+		INSERT INTO user_login_logs (user_id, platform, ip, browser_info, os_info, created) VALUES (?, ?, ?, ?, ?, ?)
+		`), login.UserId, login.Platform, login.Ip, login.Browser, login.Os, login.Created)
+	if err != nil {
+		return err
+	}
+	login.Id = id
+	return nil
+}
+
+func (r *chatRepo) Logins(uid string, limit int) ([]*Login, error) {
+	logins := []*Login{}
+	err := r.db.Select(&logins, r.q(`
+		SELECT id, user_id, platform, ip, browser_info, os_info, created FROM user_login_logs
+		WHERE user_id = ? ORDER BY id DESC LIMIT ?
+		`), uid, limit)
+	return logins, err
+}
+
+func (r *chatRepo) GetLogin(id int64) (*Login, error) {
+	login := &Login{}
+	err := r.db.Get(login, r.q(`
+		SELECT id, user_id, platform, ip, browser_info, os_info, created FROM user_login_logs WHERE id = ?
+		`), id)
+	return login, notFound(err)
+}
@@ -0,0 +1,310 @@
+package gochat
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var ErrMemTx = errors.New("内存仓库不支持事务")
+
+// NewMemRepo 内存仓库，用于测试及嵌入式部署，重启后数据丢失
+// 查询语义与chatRepo一致
+func NewMemRepo() *memRepo {
+	return &memRepo{
+		users:  make(map[string]string),
+		rooms:  make(map[string]*memRoom),
+		status: make(map[string]map[string]bool),
+	}
+}
+
+type memRoom struct {
+	name    string
+	members map[string]bool // 成员 -> 是否管理员
+	notice  proto.Notice
+}
+
+type memRepo struct {
+	sync.RWMutex
+	users  map[string]string // id -> name
+	rooms  map[string]*memRoom
+	status map[string]map[string]bool // user -> platform -> online
+	logins []*Login
+	lastId int64
+}
+
+func (r *memRepo) NewTx() (*sqlx.Tx, error) {
+	return nil, ErrMemTx
+}
+
+func (r *memRepo) AvailableClient(uid, platform string) (*proto.Client, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	client := &proto.Client{}
+	if online, ok := r.status[uid][platform]; ok {
+		client.Id = uid
+		client.Platform = platform
+		client.IsOnline = online
+	}
+	return client, nil
+}
+
+func (r *memRepo) RequestRooms(uid string) ([]*proto.Room, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	rooms := []*proto.Room{}
+	for id, room := range r.rooms {
+		if _, ok := room.members[uid]; ok {
+			rooms = append(rooms, &proto.Room{Id: id, Name: room.name})
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].Id < rooms[j].Id })
+	return rooms, nil
+}
+
+func (r *memRepo) RequestUsers(uid string) ([]*proto.User, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	users := []*proto.User{}
+	for id, name := range r.users {
+		users = append(users, &proto.User{Id: id, Name: name})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users, nil
+}
+
+func (r *memRepo) GetUser(id string) (*proto.User, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	name, ok := r.users[id]
+	if !ok {
+		return &proto.User{}, ErrNotFound
+	}
+	return &proto.User{Id: id, Name: name}, nil
+}
+
+func (r *memRepo) CreateUser(user *proto.User) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.users[user.Id]; !ok {
+		r.users[user.Id] = user.Name
+	}
+	return nil
+}
+
+func (r *memRepo) UpdateUser(user *proto.User) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.users[user.Id] = user.Name
+	return nil
+}
+
+func (r *memRepo) DeleteUser(id string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memRepo) GetRoom(id string) (*proto.Room, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	room, ok := r.rooms[id]
+	if !ok {
+		return &proto.Room{}, ErrNotFound
+	}
+	return &proto.Room{Id: id, Name: room.name}, nil
+}
+
+func (r *memRepo) CreateRoom(room *proto.Room) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.lastId++
+	room.Id = strconv.FormatInt(r.lastId, 10)
+	r.rooms[room.Id] = &memRoom{
+		name:    room.Name,
+		members: make(map[string]bool),
+	}
+	return nil
+}
+
+func (r *memRepo) UpdateRoom(room *proto.Room) error {
+	r.Lock()
+	defer r.Unlock()
+
+	current, ok := r.rooms[room.Id]
+	if !ok {
+		return ErrNotFound
+	}
+	current.name = room.Name
+	return nil
+}
+
+func (r *memRepo) Notice(roomId string) (*proto.Notice, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	room, ok := r.rooms[roomId]
+	if !ok {
+		return &proto.Notice{}, ErrNotFound
+	}
+	notice := room.notice
+	return &notice, nil
+}
+
+func (r *memRepo) SetNotice(roomId string, notice *proto.Notice) error {
+	r.Lock()
+	defer r.Unlock()
+
+	room, ok := r.rooms[roomId]
+	if !ok {
+		return ErrNotFound
+	}
+	room.notice = *notice
+	return nil
+}
+
+func (r *memRepo) DeleteRoom(roomId string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.rooms, roomId)
+	return nil
+}
+
+// Members 与chatRepo一致，已注销的用户不在成员列表中
+func (r *memRepo) Members(roomId string, onlyManager bool) ([]*proto.User, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	users := []*proto.User{}
+	room, ok := r.rooms[roomId]
+	if !ok {
+		return users, nil
+	}
+	for id, manager := range room.members {
+		name, ok := r.users[id]
+		if !ok || (onlyManager && !manager) {
+			continue
+		}
+		users = append(users, &proto.User{Id: id, Name: name})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users, nil
+}
+
+func (r *memRepo) Join(uid, roomId string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	room, ok := r.rooms[roomId]
+	if !ok {
+		return ErrNotFound
+	}
+	if _, ok := room.members[uid]; !ok {
+		room.members[uid] = false
+	}
+	return nil
+}
+
+func (r *memRepo) Out(uid, roomId string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if room, ok := r.rooms[roomId]; ok {
+		delete(room.members, uid)
+	}
+	return nil
+}
+
+func (r *memRepo) Online(uid, platform string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.status[uid] == nil {
+		r.status[uid] = make(map[string]bool)
+	}
+	r.status[uid][platform] = true
+	return nil
+}
+
+// Offline 与chatRepo一致，未上线过的平台不记录
+func (r *memRepo) Offline(uid, platform string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.status[uid][platform]; ok {
+		r.status[uid][platform] = false
+	}
+	return nil
+}
+
+func (r *memRepo) OnlineUsers(uids []string) ([]string, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	users := []string{}
+	for _, uid := range uids {
+		for _, online := range r.status[uid] {
+			if online {
+				users = append(users, uid)
+				break
+			}
+		}
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+func (r *memRepo) LogLogin(login *Login) error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.lastId++
+	login.Id = r.lastId
+	login.Created = time.Now()
+	l := *login
+	r.logins = append(r.logins, &l)
+	return nil
+}
+
+func (r *memRepo) Logins(uid string, limit int) ([]*Login, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	logins := []*Login{}
+	for i := len(r.logins) - 1; i >= 0 && len(logins) < limit; i-- {
+		if r.logins[i].UserId == uid {
+			l := *r.logins[i]
+			logins = append(logins, &l)
+		}
+	}
+	return logins, nil
+}
+
+func (r *memRepo) GetLogin(id int64) (*Login, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for _, l := range r.logins {
+		if l.Id == id {
+			login := *l
+			return &login, nil
+		}
+	}
+	return nil, ErrNotFound
+}
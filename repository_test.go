@@ -0,0 +1,85 @@
+package gochat_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	gochat "github.com/laoqiu/go-chat"
+	"github.com/laoqiu/go-chat/repotest"
+	"github.com/laoqiu/sqlxt"
+
+	// sqlite及postgres驱动，mysql由sqlxt导入
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLite(t *testing.T) {
+	db := sqlx.MustConnect("sqlite", ":memory:")
+	// :memory:每个连接是独立的库
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	repotest.Run(t, db)
+}
+
+// mysql及postgres需指定空库，如
+// GOCHAT_MYSQL_DSN="root:root@tcp(localhost:3306)/chat_test?parseTime=true"
+// GOCHAT_POSTGRES_DSN="postgres://postgres@localhost/chat_test?sslmode=disable"
+func TestMySQL(t *testing.T) {
+	testDialect(t, "mysql", "GOCHAT_MYSQL_DSN")
+}
+
+func TestPostgres(t *testing.T) {
+	testDialect(t, "pgx", "GOCHAT_POSTGRES_DSN")
+}
+
+func testDialect(t *testing.T, driver, env string) {
+	dsn := os.Getenv(env)
+	if len(dsn) == 0 {
+		t.Skipf("%s not set", env)
+	}
+	db, err := gochat.Connect(sqlxt.Driver(driver), sqlxt.URI(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repotest.Run(t, db)
+}
+
+// TestMySQLMigrateResume mysql的DDL不在事务中，中断后重新执行迁移应跳过已生效的语句
+func TestMySQLMigrateResume(t *testing.T) {
+	dsn := os.Getenv("GOCHAT_MYSQL_DSN")
+	if len(dsn) == 0 {
+		t.Skip("GOCHAT_MYSQL_DSN not set")
+	}
+	db, err := gochat.Connect(sqlxt.Driver("mysql"), sqlxt.URI(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	m := gochat.NewMigrator(db, gochat.Migrations[gochat.MySQL], nil)
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟v13在ALTER之后、建表之前中断
+	db.MustExec(`DROP TABLE IF EXISTS message_ttl`)
+	db.MustExec(`DELETE FROM schema_migrations WHERE version = 13`)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("resume up: %v", err)
+	}
+	// 模拟v11的Down在删除部分列后中断
+	if err := m.Down(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	db.MustExec(`INSERT INTO schema_migrations (version, name) VALUES (11, 'room_pins')`)
+	db.MustExec(`ALTER TABLE chatgroup ADD COLUMN notice_updated BIGINT DEFAULT 0`)
+	if err := m.Down(ctx, 1); err != nil {
+		t.Fatalf("resume down: %v", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
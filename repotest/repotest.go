@@ -0,0 +1,807 @@
+// Package repotest Repository一致性测试，各数据库方言及内存仓库共用
+//
+// 在驱动对应的测试中传入空库调用Run，例如sqlite:
+//
+//	db := sqlx.MustConnect("sqlite", ":memory:")
+//	db.SetMaxOpenConns(1)
+//	repotest.Run(t, db)
+//
+// 内存仓库只运行Repository部分:
+//
+//	repotest.Repository(t, gochat.NewMemRepo())
+//
+// Sessions验证同一平台只保留一个连接:
+//
+//	repotest.Sessions(t, gochat.NewMemRepo())
+package repotest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// Run 执行迁移后依次运行各仓库的测试
+func Run(t *testing.T, db *sqlx.DB) {
+	dialect := gochat.DialectOf(db)
+	if err := gochat.NewMigrator(db, gochat.Migrations[dialect], nil).Up(context.Background()); err != nil {
+		t.Fatalf("%s migrate: %v", dialect, err)
+	}
+
+	Repository(t, gochat.NewChatRepo(db, nil))
+	t.Run("Sessions", func(t *testing.T) { Sessions(t, gochat.NewChatRepo(db, nil)) })
+	t.Run("Webhooks", func(t *testing.T) { testWebhooks(t, db) })
+	t.Run("Bots", func(t *testing.T) { testBots(t, db) })
+	t.Run("Keys", func(t *testing.T) { testKeys(t, db) })
+	t.Run("Receipts", func(t *testing.T) { testReceipts(t, db) })
+	t.Run("History", func(t *testing.T) { testHistory(t, db) })
+	t.Run("Reactions", func(t *testing.T) { testReactions(t, db) })
+	t.Run("Pins", func(t *testing.T) { testPins(t, db) })
+	t.Run("TTL", func(t *testing.T) { testTTL(t, db) })
+	t.Run("Mentions", func(t *testing.T) { testMentions(t, db) })
+	t.Run("Schedules", func(t *testing.T) { testSchedules(t, db) })
+}
+
+// Repository 所有Repository实现都需通过的测试，repo应为空
+func Repository(t *testing.T, repo gochat.Repository) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, repo) })
+	t.Run("Rooms", func(t *testing.T) { testRooms(t, repo) })
+	t.Run("Members", func(t *testing.T) { testMembers(t, repo) })
+	t.Run("Status", func(t *testing.T) { testStatus(t, repo) })
+	t.Run("Logins", func(t *testing.T) { testLogins(t, repo) })
+}
+
+func testUsers(t *testing.T, repo gochat.Repository) {
+	if err := repo.CreateUser(&proto.User{Id: "u1", Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	// 重复注册忽略
+	if err := repo.CreateUser(&proto.User{Id: "u1", Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if name := userName(t, repo, "u1"); name != "alice" {
+		t.Fatalf("CreateUser duplicate: name = %q, want alice", name)
+	}
+
+	if err := repo.UpdateUser(&proto.User{Id: "u1", Name: "carol"}); err != nil {
+		t.Fatal(err)
+	}
+	if name := userName(t, repo, "u1"); name != "carol" {
+		t.Fatalf("UpdateUser: name = %q, want carol", name)
+	}
+	// 不存在时插入
+	if err := repo.UpdateUser(&proto.User{Id: "u2", Name: "dave"}); err != nil {
+		t.Fatal(err)
+	}
+	if name := userName(t, repo, "u2"); name != "dave" {
+		t.Fatalf("UpdateUser insert: name = %q, want dave", name)
+	}
+
+	users, err := repo.RequestUsers("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("RequestUsers: got %d, want 2", len(users))
+	}
+
+	if err := repo.DeleteUser("u2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetUser("u2"); err != gochat.ErrNotFound {
+		t.Fatalf("GetUser deleted: err = %v, want ErrNotFound", err)
+	}
+	// 其它用户存在时也不能查到未注册的用户
+	if _, err := repo.GetUser("unknown"); err != gochat.ErrNotFound {
+		t.Fatalf("GetUser unknown: err = %v, want ErrNotFound", err)
+	}
+}
+
+func userName(t *testing.T, repo gochat.Repository, id string) string {
+	user, err := repo.GetUser(id)
+	if err == gochat.ErrNotFound {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Id != id {
+		t.Fatalf("GetUser(%q): got user %q", id, user.Id)
+	}
+	return user.Name
+}
+
+func testRooms(t *testing.T, repo gochat.Repository) {
+	room := &proto.Room{Name: "room"}
+	if err := repo.CreateRoom(room); err != nil {
+		t.Fatal(err)
+	}
+	if room.Id == "" {
+		t.Fatal("CreateRoom: id not set")
+	}
+	if got, err := repo.GetRoom(room.Id); err != nil || got.Name != "room" {
+		t.Fatalf("GetRoom: got %v, %v", got, err)
+	}
+
+	room.Name = "renamed"
+	if err := repo.UpdateRoom(room); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.GetRoom(room.Id); err != nil || got.Name != "renamed" {
+		t.Fatalf("UpdateRoom: got %v, %v", got, err)
+	}
+
+	if notice, err := repo.Notice(room.Id); err != nil || notice.Body != "" {
+		t.Fatalf("Notice unset: got %v, %v", notice, err)
+	}
+	if err := repo.SetNotice(room.Id, &proto.Notice{Body: "公告", From: "m1", Updated: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if notice, err := repo.Notice(room.Id); err != nil || notice.Body != "公告" || notice.From != "m1" || notice.Updated != 100 {
+		t.Fatalf("Notice: got %v, %v", notice, err)
+	}
+
+	if err := repo.DeleteRoom(room.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetRoom(room.Id); err != gochat.ErrNotFound {
+		t.Fatalf("GetRoom deleted: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdateRoom(room); err != gochat.ErrNotFound {
+		t.Fatalf("UpdateRoom deleted: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.SetNotice(room.Id, &proto.Notice{}); err != gochat.ErrNotFound {
+		t.Fatalf("SetNotice deleted: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.Join("r1", room.Id); err != gochat.ErrNotFound {
+		t.Fatalf("Join deleted: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testMembers(t *testing.T, repo gochat.Repository) {
+	for _, u := range []*proto.User{{Id: "m1", Name: "first"}, {Id: "m2", Name: "second"}} {
+		if err := repo.CreateUser(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	room := &proto.Room{Name: "members"}
+	if err := repo.CreateRoom(room); err != nil {
+		t.Fatal(err)
+	}
+	for _, uid := range []string{"m1", "m2", "m2"} {
+		if err := repo.Join(uid, room.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rooms, err := repo.RequestRooms("m2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rooms) != 1 || rooms[0].Id != room.Id {
+		t.Fatalf("RequestRooms: got %v", rooms)
+	}
+
+	members, err := repo.Members(room.Id, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("Members: got %d, want 2", len(members))
+	}
+	// 加入时不是管理员
+	managers, err := repo.Members(room.Id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(managers) != 0 {
+		t.Fatalf("Members onlyManager: got %v", managers)
+	}
+
+	if err := repo.Out("m2", room.Id); err != nil {
+		t.Fatal(err)
+	}
+	if rooms, err := repo.RequestRooms("m2"); err != nil || len(rooms) != 0 {
+		t.Fatalf("Out: rooms %v, %v", rooms, err)
+	}
+	// 注销的用户不在成员列表中
+	if err := repo.DeleteUser("m1"); err != nil {
+		t.Fatal(err)
+	}
+	if members, err := repo.Members(room.Id, false); err != nil || len(members) != 0 {
+		t.Fatalf("Members after DeleteUser: got %v, %v", members, err)
+	}
+}
+
+func testStatus(t *testing.T, repo gochat.Repository) {
+	client := func(uid, platform string) *proto.Client {
+		c, err := repo.AvailableClient(uid, platform)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	if c := client("s1", "web"); c.IsOnline || c.Id != "" {
+		t.Fatalf("AvailableClient never online: got %v", c)
+	}
+	if err := repo.Online("s1", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if c := client("s1", "web"); !c.IsOnline || c.Id != "s1" || c.Platform != "web" {
+		t.Fatalf("Online: got %v", c)
+	}
+	// 其它用户及平台不受影响
+	if c := client("s2", "web"); c.IsOnline {
+		t.Fatalf("Online other user: got %v", c)
+	}
+	if c := client("s1", "mobile"); c.IsOnline {
+		t.Fatalf("Online other platform: got %v", c)
+	}
+
+	if err := repo.Offline("s1", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if c := client("s1", "web"); c.IsOnline {
+		t.Fatalf("Offline: got %v", c)
+	}
+	// 再次上线走冲突更新
+	if err := repo.Online("s1", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if c := client("s1", "web"); !c.IsOnline {
+		t.Fatalf("Online again: got %v", c)
+	}
+
+	// 任一平台在线即在线
+	if err := repo.Online("s2", "mobile"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Online("s3", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Offline("s3", "web"); err != nil {
+		t.Fatal(err)
+	}
+	users, err := repo.OnlineUsers([]string{"s3", "s2", "s1", "s4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0] != "s1" || users[1] != "s2" {
+		t.Fatalf("OnlineUsers: got %v", users)
+	}
+}
+
+func testLogins(t *testing.T, repo gochat.Repository) {
+	if _, err := repo.GetLogin(1 << 30); err != gochat.ErrNotFound {
+		t.Fatalf("GetLogin unknown: err = %v, want ErrNotFound", err)
+	}
+	for _, platform := range []string{"web", "mobile", "web"} {
+		login := &gochat.Login{UserId: "l1", Platform: platform, Ip: "2001:db8::1", Browser: "Chrome 120", Os: "macOS 10.15"}
+		if err := repo.LogLogin(login); err != nil {
+			t.Fatal(err)
+		}
+		if login.Id == 0 {
+			t.Fatalf("LogLogin: id not set")
+		}
+	}
+	if err := repo.LogLogin(&gochat.Login{UserId: "l2", Platform: "web", Ip: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	logins, err := repo.Logins("l1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logins) != 2 || logins[0].Platform != "web" || logins[1].Platform != "mobile" || logins[0].Id < logins[1].Id {
+		t.Fatalf("Logins: got %v", logins)
+	}
+	login, err := repo.GetLogin(logins[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.UserId != "l1" || login.Ip != "2001:db8::1" || login.Browser != "Chrome 120" || login.Os != "macOS 10.15" || login.Created.IsZero() {
+		t.Fatalf("GetLogin: got %+v", login)
+	}
+}
+
+func testWebhooks(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewWebhookRepo(db)
+
+	hook := &gochat.Webhook{Url: "http://localhost/hook", Secret: "secret", Events: "message", Active: true}
+	if err := repo.CreateWebhook(hook); err != nil {
+		t.Fatal(err)
+	}
+	if hook.Id == 0 || hook.Created.IsZero() {
+		t.Fatalf("CreateWebhook: id or created not set: %+v", hook)
+	}
+	if err := repo.CreateWebhook(&gochat.Webhook{Url: "http://localhost/off", Secret: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, err := repo.Webhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 1 || hooks[0].Id != hook.Id {
+		t.Fatalf("Webhooks: got %v", hooks)
+	}
+
+	if err := repo.LogDelivery(&gochat.WebhookDelivery{
+		WebhookId: hook.Id, DeliveryId: "d1", EventType: "message", Attempt: 1, StatusCode: 200,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeadLetter(hook.Id, "d2", []byte(`{}`), "timeout"); err != nil {
+		t.Fatal(err)
+	}
+	// 超长的错误信息截断后写入
+	if err := repo.DeadLetter(hook.Id, "d3", []byte(`{}`), strings.Repeat("x", 1000)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.DeleteWebhook(hook.Id); err != nil {
+		t.Fatal(err)
+	}
+	if hooks, err := repo.Webhooks(); err != nil || len(hooks) != 0 {
+		t.Fatalf("DeleteWebhook: got %v, %v", hooks, err)
+	}
+}
+
+func testBots(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewBotRepo(db)
+
+	if err := gochat.NewChatRepo(db, nil).CreateUser(&proto.User{Id: "b1", Name: "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateBot(&gochat.Bot{Id: "b1", Commands: []string{"echo"}}, "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	bot, err := repo.BotByToken("hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Name != "echo" || len(bot.Commands) != 1 {
+		t.Fatalf("BotByToken: got %+v", bot)
+	}
+	if _, err := repo.BotByToken("other"); err != gochat.ErrBotToken {
+		t.Fatalf("BotByToken unknown: err = %v, want ErrBotToken", err)
+	}
+
+	bot, err = repo.BotByCommand("echo")
+	if err != nil || bot == nil || bot.Id != "b1" {
+		t.Fatalf("BotByCommand: got %+v, %v", bot, err)
+	}
+	if bot, err := repo.BotByCommand("none"); err != nil || bot != nil {
+		t.Fatalf("BotByCommand unknown: got %+v, %v", bot, err)
+	}
+
+	if bot, err := repo.BotById("b1"); err != nil || bot == nil || bot.Name != "echo" {
+		t.Fatalf("BotById: got %+v, %v", bot, err)
+	}
+	if bot, err := repo.BotById("u1"); err != nil || bot != nil {
+		t.Fatalf("BotById user: got %+v, %v", bot, err)
+	}
+
+	if err := repo.DeleteBot("b1"); err != nil {
+		t.Fatal(err)
+	}
+	if bots, err := repo.Bots(); err != nil || len(bots) != 0 {
+		t.Fatalf("DeleteBot: got %v, %v", bots, err)
+	}
+}
+
+func testKeys(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewKeyRepo(db)
+
+	if _, err := repo.IdentityKey("k1"); err != gochat.ErrNoIdentityKey {
+		t.Fatalf("IdentityKey: err = %v, want ErrNoIdentityKey", err)
+	}
+	if err := repo.SetIdentityKey("k1", []byte("identity")); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := repo.IdentityKey("k1"); err != nil || string(key) != "identity" {
+		t.Fatalf("IdentityKey: got %q, %v", key, err)
+	}
+
+	if err := repo.AddPreKeys("k1", []*proto.PreKey{
+		{Id: "p1", PublicKey: []byte("one")},
+		{Id: "p2", PublicKey: []byte("two")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := repo.PreKeyCount("k1"); err != nil || n != 2 {
+		t.Fatalf("PreKeyCount: got %d, %v", n, err)
+	}
+
+	k, err := repo.TakePreKey("k1")
+	if err != nil || k == nil || k.Id != "p1" {
+		t.Fatalf("TakePreKey: got %v, %v", k, err)
+	}
+	if n, _ := repo.PreKeyCount("k1"); n != 1 {
+		t.Fatalf("TakePreKey: remaining %d, want 1", n)
+	}
+
+	// 更换身份密钥清空prekey
+	if err := repo.SetIdentityKey("k1", []byte("rotated")); err != nil {
+		t.Fatal(err)
+	}
+	if k, err := repo.TakePreKey("k1"); err != nil || k != nil {
+		t.Fatalf("TakePreKey after rotate: got %v, %v", k, err)
+	}
+}
+
+func testReceipts(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewReceiptRepo(db)
+
+	if _, err := repo.Receipts("m1"); err != gochat.ErrNotFound {
+		t.Fatalf("Receipts unknown: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.Sent("m1", "r1", "a", []string{"b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	// 重复投递不重置状态
+	if err := repo.Sent("m1", "r1", "a", []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mark := func(uid string, status int, want bool) {
+		changed, err := repo.Mark("m1", uid, status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != want {
+			t.Fatalf("Mark %s %d: changed = %v, want %v", uid, status, changed, want)
+		}
+	}
+	mark("b", gochat.ReceiptDelivered, true)
+	mark("b", gochat.ReceiptRead, true)
+	// 状态不后退
+	mark("b", gochat.ReceiptDelivered, false)
+	mark("c", gochat.ReceiptDelivered, true)
+	// 非接收者
+	mark("d", gochat.ReceiptRead, false)
+
+	receipts, err := repo.Receipts("m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != 2 || receipts[0].UserId != "b" || receipts[0].Status != gochat.ReceiptRead ||
+		receipts[1].Status != gochat.ReceiptDelivered || receipts[0].Sender != "a" || receipts[0].RoomId != "r1" {
+		t.Fatalf("Receipts: got %+v", receipts)
+	}
+}
+
+func testHistory(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewHistoryRepo(db)
+
+	save := func(id, parent string, created int64) {
+		if err := repo.Save(&gochat.Message{
+			EventId: id, Type: "message", Conversation: "h1", RoomId: "h1", Sender: "a",
+			Recipient: "h1/", Body: "body " + id, ParentId: parent, Created: created,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("p1", "", 100)
+	save("p2", "", 101)
+	save("p3", "", 102)
+	save("c1", "p1", 103)
+	save("c2", "p1", 104)
+	// 重复保存不重复计数
+	save("c2", "p1", 104)
+
+	if _, err := repo.Get("unknown"); err != gochat.ErrNotFound {
+		t.Fatalf("Get unknown: err = %v, want ErrNotFound", err)
+	}
+	parent, err := repo.Get("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent.ReplyCount != 2 || parent.LastReply != 104 || parent.Body != "body p1" {
+		t.Fatalf("Get: got %+v", parent)
+	}
+
+	// 历史不含回复，按时间正序分页
+	msgs, err := repo.History("h1", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].EventId != "p2" || msgs[1].EventId != "p3" {
+		t.Fatalf("History: got %+v", msgs)
+	}
+	msgs, err = repo.History("h1", "p2", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].EventId != "p1" {
+		t.Fatalf("History before: got %+v", msgs)
+	}
+
+	replies, err := repo.Replies("p1", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != 2 || replies[0].EventId != "c1" || replies[1].EventId != "c2" {
+		t.Fatalf("Replies: got %+v", replies)
+	}
+	if replies, _ = repo.Replies("p1", "c1", 10); len(replies) != 1 || replies[0].EventId != "c2" {
+		t.Fatalf("Replies after: got %+v", replies)
+	}
+
+	for _, uid := range []string{"b", "a", "b"} {
+		if err := repo.Follow("p1", uid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Unfollow("p1", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if users, err := repo.Followers("p1"); err != nil || len(users) != 1 || users[0] != "a" {
+		t.Fatalf("Followers: got %v, %v", users, err)
+	}
+}
+
+func testReactions(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewHistoryRepo(db)
+	for _, id := range []string{"rp1", "rp2"} {
+		if err := repo.Save(&gochat.Message{
+			EventId: id, Type: "message", Conversation: "r", RoomId: "r", Sender: "a",
+			Recipient: "r/", Body: id, Created: 100,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	react := func(uid, emoji string, remove, want bool) {
+		var (
+			changed bool
+			err     error
+		)
+		if remove {
+			changed, err = repo.Unreact("rp1", uid, emoji)
+		} else {
+			changed, err = repo.React("rp1", uid, emoji)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != want {
+			t.Fatalf("React %s %s remove=%v: changed = %v, want %v", uid, emoji, remove, changed, want)
+		}
+	}
+	react("a", "👍", false, true)
+	react("b", "👍", false, true)
+	react("a", "👍", false, false)
+	react("b", "🎉", false, true)
+	react("b", "🎉", true, true)
+	react("b", "🎉", true, false)
+	react("b", "❤️", false, true)
+
+	reactions, err := repo.Reactions([]string{"rp1", "rp2"}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reactions["rp1"]
+	if len(got) != 2 || got[0].Emoji != "👍" || got[0].Count != 2 || !got[0].Reacted ||
+		got[1].Emoji != "❤️" || got[1].Count != 1 || got[1].Reacted || len(reactions["rp2"]) != 0 {
+		t.Fatalf("Reactions: got %v", reactions)
+	}
+}
+
+func testPins(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewHistoryRepo(db)
+	for _, id := range []string{"pp1", "pp2", "pp3"} {
+		if err := repo.Save(&gochat.Message{
+			EventId: id, Type: "message", Conversation: "p", RoomId: "p", Sender: "a",
+			Recipient: "p/", Body: id, Created: 100,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 置顶需要房间存在，锁定房间行保证不超过上限
+	room := &proto.Room{Name: "pins"}
+	if err := gochat.NewChatRepo(db, nil).CreateRoom(room); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Pin("0", "pp1", "a", 2); err != gochat.ErrNotFound {
+		t.Fatalf("Pin unknown room: err = %v, want ErrNotFound", err)
+	}
+	for _, id := range []string{"pp1", "pp2", "pp1"} {
+		if _, err := repo.Pin(room.Id, id, "a", 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if changed, err := repo.Pin(room.Id, "pp3", "a", 2); err != gochat.ErrTooManyPins || changed {
+		t.Fatalf("Pin over max: got %v, %v, want ErrTooManyPins", changed, err)
+	}
+	if changed, err := repo.Unpin(room.Id, "pp3"); err != nil || changed {
+		t.Fatalf("Unpin not pinned: got %v, %v", changed, err)
+	}
+	if pins, err := repo.Pins(room.Id); err != nil || len(pins) != 2 || pins[0].EventId != "pp2" || pins[1].EventId != "pp1" {
+		t.Fatalf("Pins: got %+v, %v", pins, err)
+	}
+	if changed, err := repo.Unpin(room.Id, "pp2"); err != nil || !changed {
+		t.Fatalf("Unpin: got %v, %v", changed, err)
+	}
+	if pins, err := repo.Pins(room.Id); err != nil || len(pins) != 1 || pins[0].EventId != "pp1" {
+		t.Fatalf("Pins after unpin: got %+v, %v", pins, err)
+	}
+}
+
+func testTTL(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewHistoryRepo(db)
+
+	if ttl, err := repo.TTL("t1"); err != nil || ttl != 0 {
+		t.Fatalf("TTL unset: got %v, %v", ttl, err)
+	}
+	for _, ttl := range []int64{60, 3600} {
+		if err := repo.SetTTL("t1", ttl, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ttl, err := repo.TTL("t1"); err != nil || ttl != 3600 {
+		t.Fatalf("TTL: got %v, %v", ttl, err)
+	}
+	if err := repo.SetTTL("t1", 0, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, _ := repo.TTL("t1"); ttl != 0 {
+		t.Fatalf("TTL off: got %v", ttl)
+	}
+
+	now := time.Now().Unix()
+	for id, expires := range map[string]int64{"x1": now - 10, "x2": now + 3600} {
+		if err := repo.Save(&gochat.Message{
+			EventId: id, Type: "message", Conversation: "x", RoomId: "x", Sender: "a",
+			Recipient: "x/", Body: id, Created: now - 20, Expires: expires,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 清理前已过期的也不再返回
+	if _, err := repo.Get("x1"); err != gochat.ErrNotFound {
+		t.Fatalf("Get expired: err = %v, want ErrNotFound", err)
+	}
+	if msgs, _ := repo.History("x", "", 10); len(msgs) != 1 || msgs[0].EventId != "x2" {
+		t.Fatalf("History expired: got %+v", msgs)
+	}
+	expired, err := repo.Expired(now, 10)
+	if err != nil || len(expired) != 1 || expired[0].EventId != "x1" {
+		t.Fatalf("Expired: got %+v, %v", expired, err)
+	}
+	if ok, err := repo.Expire("x1"); err != nil || !ok {
+		t.Fatalf("Expire: got %v, %v", ok, err)
+	}
+	if ok, err := repo.Expire("x1"); err != nil || ok {
+		t.Fatalf("Expire twice: got %v, %v", ok, err)
+	}
+	if expired, _ := repo.Expired(now, 10); len(expired) != 0 {
+		t.Fatalf("Expired after expire: got %+v", expired)
+	}
+}
+
+func testMentions(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewMentionRepo(db)
+
+	mention := func(uid, eventId, kind string) *gochat.Mention {
+		return &gochat.Mention{UserId: uid, EventId: eventId, RoomId: "r1", Sender: "a", Body: "@" + uid, Kind: kind, Created: 100}
+	}
+	if err := repo.AddMentions([]*gochat.Mention{
+		mention("b", "e1", gochat.MentionUser),
+		mention("b", "e2", gochat.MentionAll),
+		mention("c", "e2", gochat.MentionAll),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// 重复记录忽略
+	if err := repo.AddMentions([]*gochat.Mention{mention("b", "e1", gochat.MentionUser)}); err != nil {
+		t.Fatal(err)
+	}
+
+	mentions, err := repo.Mentions("b", 0, 10, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mentions) != 2 || mentions[0].EventId != "e2" || mentions[0].Kind != gochat.MentionAll || mentions[1].EventId != "e1" {
+		t.Fatalf("Mentions: got %+v", mentions)
+	}
+	if older, _ := repo.Mentions("b", mentions[0].Id, 10, false); len(older) != 1 || older[0].EventId != "e1" {
+		t.Fatalf("Mentions before: got %+v", older)
+	}
+
+	if err := repo.ReadMention("b", "e2"); err != nil {
+		t.Fatal(err)
+	}
+	unread, err := repo.Mentions("b", 0, 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unread) != 1 || unread[0].EventId != "e1" || unread[0].IsRead {
+		t.Fatalf("Mentions unread: got %+v", unread)
+	}
+	// 其他用户不受影响
+	if others, _ := repo.Mentions("c", 0, 10, true); len(others) != 1 {
+		t.Fatalf("Mentions other user: got %+v", others)
+	}
+
+	if err := repo.DeleteMentions("e2"); err != nil {
+		t.Fatal(err)
+	}
+	if others, _ := repo.Mentions("c", 0, 10, false); len(others) != 0 {
+		t.Fatalf("DeleteMentions: got %+v", others)
+	}
+}
+
+func testSchedules(t *testing.T, db *sqlx.DB) {
+	repo := gochat.NewScheduleRepo(db)
+
+	for _, s := range []*gochat.Scheduled{
+		{EventId: "s1", Sender: "a", Event: `{"body":"1"}`, SendAt: 200, Created: 100},
+		{EventId: "s2", Sender: "a", Event: `{"body":"2"}`, SendAt: 100, Created: 100},
+		{EventId: "s3", Sender: "b", Event: `{"body":"3"}`, SendAt: 300, Created: 100},
+	} {
+		if err := repo.Schedule(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := repo.Scheduled("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].EventId != "s2" || list[1].EventId != "s1" {
+		t.Fatalf("Scheduled: got %+v", list)
+	}
+
+	if err := repo.EditScheduled("b", "s1", `{"body":"x"}`, 150); err != gochat.ErrNotFound {
+		t.Fatalf("EditScheduled other sender: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.EditScheduled("a", "s1", `{"body":"edited"}`, 150); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CancelScheduled("a", "s2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CancelScheduled("a", "s2"); err != gochat.ErrNotFound {
+		t.Fatalf("CancelScheduled twice: err = %v, want ErrNotFound", err)
+	}
+
+	due, err := repo.Due(200, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].EventId != "s1" {
+		t.Fatalf("Due: got %+v", due)
+	}
+	// 只有一个节点领取成功
+	claimed, err := repo.Claim(due[0].Id, 200, 0)
+	if err != nil || claimed == nil || claimed.Event != `{"body":"edited"}` {
+		t.Fatalf("Claim: got %+v, %v", claimed, err)
+	}
+	if again, err := repo.Claim(due[0].Id, 200, 0); err != nil || again != nil {
+		t.Fatalf("Claim twice: got %+v, %v", again, err)
+	}
+	if err := repo.CancelScheduled("a", "s1"); err != gochat.ErrNotFound {
+		t.Fatalf("CancelScheduled claimed: err = %v, want ErrNotFound", err)
+	}
+	if due, _ := repo.Due(200, 0, 10); len(due) != 0 {
+		t.Fatalf("Due claimed: got %+v", due)
+	}
+	// 领取超时后可以重新领取
+	if due, _ := repo.Due(300, 250, 10); len(due) != 2 {
+		t.Fatalf("Due stale: got %+v", due)
+	}
+	if err := repo.Done(claimed.Id); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := repo.Due(300, 250, 10); len(due) != 1 || due[0].EventId != "s3" {
+		t.Fatalf("Due done: got %+v", due)
+	}
+}
@@ -0,0 +1,214 @@
+package repotest
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+	"github.com/micro/go-micro/broker"
+	"github.com/micro/go-micro/broker/memory"
+)
+
+// Sessions 同一用户每个平台只保留一个连接，新连接踢掉旧连接，repo中不应已有用户alice
+func Sessions(t *testing.T, repo gochat.Repository) {
+	hubBroker := memory.NewBroker()
+	if err := hubBroker.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer hubBroker.Disconnect()
+
+	hub := gochat.NewHub("test", hubBroker, nil)
+	sub, err := hub.Subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	cli := gochat.NewLocalService(gochat.NewHandler("test", repo, hub, hubBroker,
+		gochat.WithConnBroker(func(string) (broker.Broker, error) {
+			return memory.NewBroker(), nil
+		}),
+		gochat.WithNodes(func() (int, error) { return 1, nil }),
+	))
+	if err := repo.CreateUser(&proto.User{Id: "alice", Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func(uid, platform string) proto.Chat_StreamService {
+		stream, err := cli.Stream(context.Background(), &proto.StreamRequest{Id: uid, Platform: platform})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stream
+	}
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		stream := open("nobody", "web")
+		defer stream.Close()
+		if _, err := stream.Recv(); err != gochat.ErrNotFound {
+			t.Fatalf("Recv: err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Kick", func(t *testing.T) {
+		first := open("alice", "web")
+		defer first.Close()
+		waitOnline(t, repo, "alice", "web")
+
+		other := open("alice", "mobile")
+		defer other.Close()
+		waitOnline(t, repo, "alice", "mobile")
+
+		second := open("alice", "web")
+		defer second.Close()
+
+		// 旧连接先收到下线原因再结束
+		event, err := waitEvent(first, 10*time.Second)
+		if err != nil {
+			t.Fatalf("first web stream: %v", err)
+		}
+		if event.Type != gochat.KickedEvent || event.Body != gochat.KickDuplicateLogin {
+			t.Fatalf("first web stream: got %v, want kicked %s", event, gochat.KickDuplicateLogin)
+		}
+		if _, err := waitEvent(first, 10*time.Second); err != io.EOF {
+			t.Fatalf("first web stream: err = %v, want io.EOF", err)
+		}
+		// 新连接上线后状态不被旧连接的下线覆盖
+		waitOnline(t, repo, "alice", "web")
+		time.Sleep(100 * time.Millisecond)
+		if c, err := repo.AvailableClient("alice", "web"); err != nil || !c.IsOnline {
+			t.Fatalf("second web stream: got %v, %v", c, err)
+		}
+		// 其它平台的连接不受影响
+		if c, err := repo.AvailableClient("alice", "mobile"); err != nil || !c.IsOnline {
+			t.Fatalf("mobile stream after web login: got %v, %v", c, err)
+		}
+		if rsp, err := other.Recv(); err != nil || rsp.Event.Type != "heartbeat" {
+			t.Fatalf("mobile stream after web login: got %v, %v", rsp, err)
+		}
+
+		// 管理员下线并等待确认，只有mobile连接在线
+		rsp, err := cli.Kick(context.Background(), &proto.KickRequest{Id: "alice", Platform: "mobile"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rsp.Complete || rsp.Nodes != 1 || rsp.Sessions != 1 {
+			t.Fatalf("Kick: got %v", rsp)
+		}
+		event, err = waitEvent(other, 10*time.Second)
+		if err != nil || event.Type != gochat.KickedEvent || event.Body != gochat.KickAdmin {
+			t.Fatalf("mobile stream: got %v, %v", event, err)
+		}
+		if _, err := cli.Kick(context.Background(), &proto.KickRequest{Id: "alice", Reason: "unknown"}); err != gochat.ErrKickReason {
+			t.Fatalf("Kick unknown reason: err = %v, want ErrKickReason", err)
+		}
+
+		// 新连接仍在线，能收到心跳
+		heartbeat, err := second.Recv()
+		if err != nil {
+			t.Fatalf("second web stream: %v", err)
+		}
+		if heartbeat.Event.Type != "heartbeat" {
+			t.Fatalf("second web stream: got %v", heartbeat.Event)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		stream, err := cli.Stream(context.Background(), &proto.StreamRequest{
+			Id: "alice", Platform: "desktop", Ip: "10.0.0.1", Browser: "Firefox 128", Os: "Linux",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stream.Close()
+		waitOnline(t, repo, "alice", "desktop")
+
+		rsp, err := cli.Sessions(context.Background(), &proto.SessionsRequest{Id: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var current *proto.Session
+		for _, s := range rsp.Sessions {
+			if s.Platform == "desktop" {
+				current = s
+				break
+			}
+		}
+		if current == nil || !current.Active || current.Ip != "10.0.0.1" || current.Browser != "Firefox 128" || current.Os != "Linux" {
+			t.Fatalf("Sessions: got %v", rsp.Sessions)
+		}
+
+		// 只能下线自己的会话
+		if _, err := cli.RevokeSession(context.Background(), &proto.RevokeSessionRequest{Id: "bob", SessionId: current.Id}); err != gochat.ErrNotFound {
+			t.Fatalf("RevokeSession other user: err = %v, want ErrNotFound", err)
+		}
+		revoked, err := cli.RevokeSession(context.Background(), &proto.RevokeSessionRequest{Id: "alice", SessionId: current.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !revoked.Complete {
+			t.Fatalf("RevokeSession: got %v", revoked)
+		}
+		event, err := waitEvent(stream, 10*time.Second)
+		if err != nil || event.Type != gochat.KickedEvent || event.Body != gochat.KickRevoked {
+			t.Fatalf("desktop stream: got %v, %v", event, err)
+		}
+
+		waitStatus(t, repo, "alice", "desktop", false)
+		if _, err := cli.RevokeSession(context.Background(), &proto.RevokeSessionRequest{Id: "alice", SessionId: current.Id}); err != gochat.ErrSessionInactive {
+			t.Fatalf("RevokeSession inactive: err = %v, want ErrSessionInactive", err)
+		}
+	})
+}
+
+func waitOnline(t *testing.T, repo gochat.Repository, uid, platform string) {
+	waitStatus(t, repo, uid, platform, true)
+}
+
+func waitStatus(t *testing.T, repo gochat.Repository, uid, platform string, online bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c, err := repo.AvailableClient(uid, platform)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.IsOnline == online {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s %s online = %v, want %v", uid, platform, c.IsOnline, online)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitEvent 跳过心跳读取下一个事件
+func waitEvent(stream proto.Chat_StreamService, timeout time.Duration) (*proto.Event, error) {
+	type result struct {
+		event *proto.Event
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		for {
+			rsp, err := stream.Recv()
+			if err != nil {
+				done <- result{nil, err}
+				return
+			}
+			if rsp.Event.Type != "heartbeat" {
+				done <- result{rsp.Event, nil}
+				return
+			}
+		}
+	}()
+	select {
+	case r := <-done:
+		return r.event, r.err
+	case <-time.After(timeout):
+		return nil, context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,346 @@
+package gochat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var (
+	ErrScheduleType = errors.New("该类型的消息不能定时发送")
+	ErrTooScheduled = errors.New("待发送的定时消息已达上限")
+
+	// 每个用户最多待发送的定时消息数
+	MaxScheduled = 100
+)
+
+// 可以定时发送的消息类型
+var scheduledEvents = []string{"message", EncryptedEvent}
+
+// scheduledKey Scheduler发送时标记在context上，已在定时时限流过
+type scheduledKey struct{}
+
+// Scheduled 定时消息，对应scheduled_messages
+type Scheduled struct {
+	Id        int64  `db:"id"`
+	EventId   string `db:"event_id"`
+	Sender    string `db:"sender"`
+	Event     string `db:"event"` // proto.Event的json，Created在发送时设置
+	SendAt    int64  `db:"send_at"`
+	ClaimedAt int64  `db:"claimed_at"`
+	Created   int64  `db:"created"`
+}
+
+func (s *Scheduled) ToProto() *proto.ScheduledMessage {
+	event := &proto.Event{}
+	json.Unmarshal([]byte(s.Event), event)
+	return &proto.ScheduledMessage{Event: event, SendAt: s.SendAt}
+}
+
+type ScheduleRepository interface {
+	// 保存定时消息
+	Schedule(s *Scheduled) error
+	// 用户待发送的定时消息，按发送时间正序
+	Scheduled(sender string) ([]*Scheduled, error)
+	// 修改待发送的消息，已发送或不存在时返回ErrNotFound
+	EditScheduled(sender, eventId, event string, sendAt int64) error
+	// 取消待发送的消息，已发送或不存在时返回ErrNotFound
+	CancelScheduled(sender, eventId string) error
+	// 到期的消息，包括领取时间早于stale的，即领取的节点未完成发送
+	Due(now, stale int64, limit int) ([]*Scheduled, error)
+	// 领取消息，返回领取时的内容，已被其他节点领取时返回nil
+	Claim(id, now, stale int64) (*Scheduled, error)
+	// 发送后删除
+	Done(id int64) error
+}
+
+func NewScheduleRepo(db *sqlx.DB) *scheduleRepo {
+	return &scheduleRepo{db: db}
+}
+
+type scheduleRepo struct {
+	db *sqlx.DB
+}
+
+const scheduledColumns = `id, event_id, sender, event, send_at, claimed_at, created`
+
+func (r *scheduleRepo) Schedule(s *Scheduled) error {
+	_, err := r.db.Exec(r.db.Rebind(`
+		INSERT INTO scheduled_messages (event_id, sender, event, send_at, created) VALUES (?, ?, ?, ?, ?)
+		`), s.EventId, s.Sender, s.Event, s.SendAt, s.Created)
+	return err
+}
+
+func (r *scheduleRepo) Scheduled(sender string) ([]*Scheduled, error) {
+	list := []*Scheduled{}
+	err := r.db.Select(&list, r.db.Rebind(`
+		SELECT `+scheduledColumns+` FROM scheduled_messages WHERE sender = ? AND claimed_at = 0 ORDER BY send_at, id
+		`), sender)
+	return list, err
+}
+
+func (r *scheduleRepo) EditScheduled(sender, eventId, event string, sendAt int64) error {
+	result, err := r.db.Exec(r.db.Rebind(`
+		UPDATE scheduled_messages SET event = ?, send_at = ? WHERE event_id = ? AND sender = ? AND claimed_at = 0
+		`), event, sendAt, eventId, sender)
+	return affected(result, err)
+}
+
+func (r *scheduleRepo) CancelScheduled(sender, eventId string) error {
+	result, err := r.db.Exec(r.db.Rebind(`
+		DELETE FROM scheduled_messages WHERE event_id = ? AND sender = ? AND claimed_at = 0
+		`), eventId, sender)
+	return affected(result, err)
+}
+
+func (r *scheduleRepo) Due(now, stale int64, limit int) ([]*Scheduled, error) {
+	list := []*Scheduled{}
+	err := r.db.Select(&list, r.db.Rebind(`
+		SELECT `+scheduledColumns+` FROM scheduled_messages
+		WHERE send_at <= ? AND (claimed_at = 0 OR claimed_at < ?) ORDER BY send_at, id LIMIT ?
+		`), now, stale, limit)
+	return list, err
+}
+
+func (r *scheduleRepo) Claim(id, now, stale int64) (*Scheduled, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 条件更新保证只有一个节点领取成功
+	result, err := tx.Exec(tx.Rebind(`
+		UPDATE scheduled_messages SET claimed_at = ? WHERE id = ? AND (claimed_at = 0 OR claimed_at < ?)
+		`), now, id, stale)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return nil, err
+	}
+	s := &Scheduled{}
+	if err := tx.Get(s, tx.Rebind(`SELECT `+scheduledColumns+` FROM scheduled_messages WHERE id = ?`), id); err != nil {
+		return nil, err
+	}
+	return s, tx.Commit()
+}
+
+func (r *scheduleRepo) Done(id int64) error {
+	_, err := r.db.Exec(r.db.Rebind(`DELETE FROM scheduled_messages WHERE id = ?`), id)
+	return err
+}
+
+// affected 没有记录被修改时返回ErrNotFound
+func affected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Scheduled 用户待发送的定时消息
+func (h *Handler) Scheduled(ctx context.Context, req *proto.ScheduledRequest, rsp *proto.ScheduledResponse) error {
+	if h.opts.Schedules == nil {
+		return errors.New("定时消息未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	list, err := h.opts.Schedules.Scheduled(req.Id)
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		rsp.Messages = append(rsp.Messages, s.ToProto())
+	}
+	return nil
+}
+
+// EditScheduled 修改定时消息的内容或发送时间，已发送的不能修改
+func (h *Handler) EditScheduled(ctx context.Context, req *proto.EditScheduledRequest, rsp *proto.EditScheduledResponse) error {
+	if h.opts.Schedules == nil {
+		return errors.New("定时消息未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	list, err := h.opts.Schedules.Scheduled(req.Id)
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s.EventId != req.EventId {
+			continue
+		}
+		msg := s.ToProto()
+		if len(req.Body) > 0 {
+			msg.Event.Body = req.Body
+		}
+		if req.SendAt > 0 {
+			msg.SendAt = req.SendAt
+		}
+		event, _ := json.Marshal(msg.Event)
+		if err := h.opts.Schedules.EditScheduled(req.Id, req.EventId, string(event), msg.SendAt); err != nil {
+			return err
+		}
+		rsp.Message = msg
+		return nil
+	}
+	return ErrNotFound
+}
+
+// CancelScheduled 取消定时消息，已发送的不能取消
+func (h *Handler) CancelScheduled(ctx context.Context, req *proto.CancelScheduledRequest, rsp *proto.CancelScheduledResponse) error {
+	if h.opts.Schedules == nil {
+		return errors.New("定时消息未启用")
+	}
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	return h.opts.Schedules.CancelScheduled(req.Id, req.EventId)
+}
+
+// schedule 保存定时消息，到期后由Scheduler经Send发送
+func (h *Handler) schedule(req *proto.SendRequest, rsp *proto.SendResponse) error {
+	if h.opts.Schedules == nil {
+		return errors.New("定时消息未启用")
+	}
+	if !in(scheduledEvents, req.Event.Type) {
+		return ErrScheduleType
+	}
+	if len(req.Event.From) == 0 {
+		return errors.New("from is required")
+	}
+	list, err := h.opts.Schedules.Scheduled(req.Event.From)
+	if err != nil {
+		return err
+	}
+	if len(list) >= MaxScheduled {
+		return ErrTooScheduled
+	}
+	req.Event.Created = 0
+	event, _ := json.Marshal(req.Event)
+	if err := h.opts.Schedules.Schedule(&Scheduled{
+		EventId: req.Event.Id,
+		Sender:  req.Event.From,
+		Event:   string(event),
+		SendAt:  req.SendAt,
+		Created: time.Now().Unix(),
+	}); err != nil {
+		return err
+	}
+	rsp.Id = req.Event.Id
+	return nil
+}
+
+type SchedulerOption func(*Scheduler)
+
+// SchedulerInterval 检查到期消息的间隔，默认1秒
+func SchedulerInterval(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.interval = d
+	}
+}
+
+// SchedulerLease 节点领取后未完成发送，超过lease后由其他节点重新发送，默认1分钟
+func SchedulerLease(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.lease = d
+	}
+}
+
+// Scheduler 定时发送到期的消息，多个节点同时运行时每条消息只由领取成功的节点发送
+type Scheduler struct {
+	h        *Handler
+	interval time.Duration
+	lease    time.Duration
+	batch    int
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// NewScheduler 启动Scheduler，Handler未启用定时消息时不执行
+func NewScheduler(h *Handler, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		h:        h,
+		interval: time.Second,
+		lease:    time.Minute,
+		batch:    100,
+		done:     make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	if h.opts.Schedules != nil {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return s
+}
+
+func (s *Scheduler) Close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *Scheduler) tick() {
+	repo := s.h.opts.Schedules
+	now := time.Now()
+	stale := now.Add(-s.lease).Unix()
+	due, err := repo.Due(now.Unix(), stale, s.batch)
+	if err != nil {
+		s.h.opts.Logger.Warn("scheduled query failed", ErrField(err))
+		return
+	}
+	for _, d := range due {
+		claimed, err := repo.Claim(d.Id, now.Unix(), stale)
+		if err != nil {
+			s.h.opts.Logger.Warn("scheduled claim failed", EventIdField(d.EventId), ErrField(err))
+			continue
+		}
+		if claimed == nil {
+			continue
+		}
+		s.send(claimed)
+	}
+}
+
+// send 经Handler.Send发送，失败只记录日志，不重试
+func (s *Scheduler) send(claimed *Scheduled) {
+	msg := claimed.ToProto()
+	ctx := context.WithValue(context.Background(), scheduledKey{}, true)
+	if err := s.h.Send(ctx, &proto.SendRequest{Event: msg.Event}, &proto.SendResponse{}); err != nil {
+		s.h.opts.Logger.Warn("scheduled send failed", UserField(claimed.Sender), EventIdField(claimed.EventId), ErrField(err))
+	}
+	if err := s.h.opts.Schedules.Done(claimed.Id); err != nil {
+		s.h.opts.Logger.Warn("scheduled done failed", EventIdField(claimed.EventId), ErrField(err))
+	}
+}
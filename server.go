@@ -0,0 +1,201 @@
+package gochat
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+	"github.com/micro/go-micro/broker"
+	"github.com/micro/go-micro/broker/memory"
+)
+
+type ServerOptions struct {
+	// 服务名，用作消息主题前缀
+	Name string
+	// 数据库，为空时使用内存仓库且不启用webhook、机器人、端到端加密、消息回执、历史、提及收件箱及定时消息
+	DB *sqlx.DB
+	// 日志，为空时使用DefaultLogger
+	Logger Logger
+	// prometheus指标，为空时不记录
+	Metrics *Metrics
+	// 额外的Handler选项，如频率限制及内容过滤
+	HandlerOptions []Option
+	// websocket网关选项，如permessage-deflate压缩
+	WebsocketOptions []WebsocketOption
+	// REST接口的token，名称为key，为空时不启用REST接口
+	APITokens map[string]string
+}
+
+type ServerOption func(*ServerOptions)
+
+// ServerName 设置服务名
+func ServerName(name string) ServerOption {
+	return func(o *ServerOptions) {
+		o.Name = name
+	}
+}
+
+// ServerDB 使用数据库存储，启动时执行迁移，sqlite可实现单文件部署
+func ServerDB(db *sqlx.DB) ServerOption {
+	return func(o *ServerOptions) {
+		o.DB = db
+	}
+}
+
+// ServerLogger 设置日志
+func ServerLogger(l Logger) ServerOption {
+	return func(o *ServerOptions) {
+		o.Logger = l
+	}
+}
+
+// ServerMetrics 启用prometheus指标
+func ServerMetrics(m *Metrics) ServerOption {
+	return func(o *ServerOptions) {
+		o.Metrics = m
+	}
+}
+
+// ServerHandlerOptions 追加Handler选项
+func ServerHandlerOptions(opts ...Option) ServerOption {
+	return func(o *ServerOptions) {
+		o.HandlerOptions = append(o.HandlerOptions, opts...)
+	}
+}
+
+// ServerWebsocketOptions 追加websocket网关选项
+func ServerWebsocketOptions(opts ...WebsocketOption) ServerOption {
+	return func(o *ServerOptions) {
+		o.WebsocketOptions = append(o.WebsocketOptions, opts...)
+	}
+}
+
+// ServerAPIToken 添加REST接口的token，挂载在APIPrefix下
+func ServerAPIToken(name, token string) ServerOption {
+	return func(o *ServerOptions) {
+		if o.APITokens == nil {
+			o.APITokens = make(map[string]string)
+		}
+		o.APITokens[name] = token
+	}
+}
+
+// Server 嵌入模式，在一个进程内运行Handler、Hub及websocket网关
+// 不需要micro registry、MySQL及nats-streaming，消息不持久化
+type Server struct {
+	opts      ServerOptions
+	broker    broker.Broker
+	sub       broker.Subscriber
+	webhooks  *Webhooks
+	scheduler *Scheduler
+	sweeper   *Sweeper
+	cli       proto.ChatService
+	mux       *http.ServeMux
+	done      chan struct{}
+}
+
+func NewServer(opts ...ServerOption) (*Server, error) {
+	o := ServerOptions{
+		Name: "go.micro.srv.chat",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.Logger = loggerOrDefault(o.Logger)
+
+	s := &Server{
+		opts:   o,
+		broker: memory.NewBroker(),
+		mux:    http.NewServeMux(),
+		done:   make(chan struct{}),
+	}
+	if err := s.broker.Connect(); err != nil {
+		return nil, err
+	}
+
+	handlerOpts := []Option{
+		WithLogger(o.Logger),
+		WithMetrics(o.Metrics),
+		WithConnBroker(func(string) (broker.Broker, error) {
+			return &sharedBroker{Broker: s.broker}, nil
+		}),
+		// 单节点
+		WithNodes(func() (int, error) { return 1, nil }),
+	}
+
+	var repo Repository = NewMemRepo()
+	if o.DB != nil {
+		if err := NewMigrator(o.DB, Migrations[DialectOf(o.DB)], o.Logger).Up(context.Background()); err != nil {
+			s.broker.Disconnect()
+			return nil, err
+		}
+		repo = NewChatRepo(o.DB, o.Logger)
+		s.webhooks = NewWebhooks(NewWebhookRepo(o.DB), WebhookLogger(o.Logger))
+		handlerOpts = append(handlerOpts,
+			WithWebhooks(s.webhooks),
+			WithBots(NewBotRepo(o.DB)),
+			WithKeys(NewKeyRepo(o.DB)),
+			WithReceipts(NewReceiptRepo(o.DB)),
+			WithHistory(NewHistoryRepo(o.DB)),
+			WithMentions(NewMentionRepo(o.DB)),
+			WithSchedules(NewScheduleRepo(o.DB)),
+		)
+	}
+	handlerOpts = append(handlerOpts, o.HandlerOptions...)
+
+	hub := NewHub(o.Name, s.broker, o.Logger)
+	sub, err := hub.Subscribe()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.sub = sub
+
+	h := NewHandler(o.Name, repo, hub, s.broker, handlerOpts...)
+	s.scheduler = NewScheduler(h)
+	s.sweeper = NewSweeper(h)
+	s.cli = NewLocalService(h)
+	s.mux.HandleFunc("/stream", NewWebsocketHandler(s.cli, o.Logger, o.Metrics, o.WebsocketOptions...))
+	// 不支持websocket时的备用传输
+	s.mux.HandleFunc("/sse", NewSSEHandler(s.cli, o.Logger, o.Metrics))
+	s.mux.HandleFunc("/poll", NewPollHandler(s.cli, o.Logger, o.Metrics, PollDone(s.done)))
+	s.mux.HandleFunc("/send", NewSendHandler(s.cli, o.Logger))
+	if len(o.APITokens) > 0 {
+		apiOpts := []APIOption{APILogger(o.Logger)}
+		for name, token := range o.APITokens {
+			apiOpts = append(apiOpts, APIToken(name, token))
+		}
+		s.mux.Handle(APIPrefix+"/", NewAPIHandler(s.cli, apiOpts...))
+	}
+	if o.DB != nil {
+		s.mux.HandleFunc("/bot/send", NewBotHandler(s.cli))
+	}
+	return s, nil
+}
+
+// Client 进程内的ChatService，用于注册用户、管理房间等
+func (s *Server) Client() proto.ChatService {
+	return s.cli
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) Close() error {
+	close(s.done)
+	if s.sub != nil {
+		s.sub.Unsubscribe()
+	}
+	if s.scheduler != nil {
+		s.scheduler.Close()
+	}
+	if s.sweeper != nil {
+		s.sweeper.Close()
+	}
+	if s.webhooks != nil {
+		s.webhooks.Close()
+	}
+	return s.broker.Disconnect()
+}
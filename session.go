@@ -0,0 +1,243 @@
+package gochat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+var (
+	ErrSessionInactive = errors.New("会话已不在线")
+
+	// 会话列表返回的最近登录记录数
+	SessionsLimit = 20
+)
+
+// Login 登录记录，对应user_login_logs
+type Login struct {
+	Id       int64     `db:"id"`
+	UserId   string    `db:"user_id"`
+	Platform string    `db:"platform"`
+	Ip       string    `db:"ip"`
+	Browser  string    `db:"browser_info"`
+	Os       string    `db:"os_info"`
+	Created  time.Time `db:"created"`
+}
+
+func (l *Login) ToProto() *proto.Session {
+	return &proto.Session{
+		Id:       l.Id,
+		Platform: l.Platform,
+		Ip:       l.Ip,
+		Browser:  l.Browser,
+		Os:       l.Os,
+		Created:  l.Created.Unix(),
+	}
+}
+
+// Sessions 最近的登录记录，每个平台最新一条且在线的为当前会话
+func (h *Handler) Sessions(ctx context.Context, req *proto.SessionsRequest, rsp *proto.SessionsResponse) error {
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	sessions, err := h.sessions(req.Id)
+	if err != nil {
+		return err
+	}
+	rsp.Sessions = sessions
+	return nil
+}
+
+// RevokeSession 强制下线用户自己的某个当前会话
+func (h *Handler) RevokeSession(ctx context.Context, req *proto.RevokeSessionRequest, rsp *proto.RevokeSessionResponse) error {
+	if len(req.Id) == 0 {
+		return errors.New("id is required")
+	}
+	login, err := h.repo.GetLogin(req.SessionId)
+	if err != nil {
+		return err
+	}
+	// 不暴露其他用户的登录记录
+	if login.UserId != req.Id {
+		return ErrNotFound
+	}
+	sessions, err := h.sessions(req.Id)
+	if err != nil {
+		return err
+	}
+	active := false
+	for _, s := range sessions {
+		if s.Id == login.Id {
+			active = s.Active
+		}
+	}
+	if !active {
+		return ErrSessionInactive
+	}
+
+	acks, expected, err := h.kickAndWait(ctx, req.Id, login.Platform, KickRevoked)
+	if err != nil {
+		return err
+	}
+	rsp.Complete = expected > 0 && len(acks) >= expected
+	h.opts.Logger.Info("session revoked", UserField(req.Id), PlatformField(login.Platform),
+		F("session", login.Id), F("complete", rsp.Complete))
+	return nil
+}
+
+func (h *Handler) sessions(uid string) ([]*proto.Session, error) {
+	logins, err := h.repo.Logins(uid, SessionsLimit)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []*proto.Session{}
+	seen := make(map[string]bool)
+	for _, login := range logins {
+		session := login.ToProto()
+		// 按id倒序，每个平台只有最新的记录可能在线
+		if !seen[login.Platform] {
+			seen[login.Platform] = true
+			client, err := h.repo.AvailableClient(uid, login.Platform)
+			if err != nil {
+				return nil, err
+			}
+			session.Active = client.IsOnline
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// logLogin 记录登录信息，失败不影响登录
+func (h *Handler) logLogin(req *proto.StreamRequest) {
+	login := &Login{
+		UserId:   req.Id,
+		Platform: req.Platform,
+		Ip:       req.Ip,
+		Browser:  req.Browser,
+		Os:       req.Os,
+	}
+	if err := h.repo.LogLogin(login); err != nil {
+		h.opts.Logger.Warn("login log failed", UserField(req.Id), PlatformField(req.Platform), ErrField(err))
+	}
+}
+
+// TrustedProxies 可信的反向代理，只有RemoteAddr在其中时才读取X-Real-IP及X-Forwarded-For
+// 为空时不信任任何代理头，启动时通过TrustProxies设置
+var TrustedProxies []*net.IPNet
+
+// TrustProxies 设置可信代理，参数为CIDR或单个IP，如 "10.0.0.0/8"、"127.0.0.1"
+func TrustProxies(proxies ...string) error {
+	nets := []*net.IPNet{}
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return fmt.Errorf("无效的代理地址: %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return err
+		}
+		nets = append(nets, n)
+	}
+	TrustedProxies = nets
+	return nil
+}
+
+func trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range TrustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP 客户端地址，RemoteAddr为可信代理时取代理设置的头
+// X-Forwarded-For从右向左取第一个不可信的地址，左侧的值可由客户端伪造
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !trustedProxy(host) {
+		return host
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); len(ip) > 0 {
+		return ip
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if len(ip) > 0 && !trustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+	return host
+}
+
+type uaRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// 顺序有关: Edge、Opera的UA中也包含Chrome，Chrome的UA中也包含Safari
+var (
+	browserRules = []uaRule{
+		{"WeChat", regexp.MustCompile(`MicroMessenger/(\d+)`)},
+		{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/(\d+)`)},
+		{"Opera", regexp.MustCompile(`(?:OPR|Opera)/(\d+)`)},
+		{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+		{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+		{"Safari", regexp.MustCompile(`Version/(\d+).*Safari/`)},
+		{"IE", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)(\d+)`)},
+	}
+	osRules = []uaRule{
+		{"Windows", regexp.MustCompile(`Windows NT (\d+\.\d+)`)},
+		{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*OS (\d+)`)},
+		{"Android", regexp.MustCompile(`Android (\d+)`)},
+		{"macOS", regexp.MustCompile(`Mac OS X (\d+[._]\d+)`)},
+		{"Linux", regexp.MustCompile(`Linux()`)},
+	}
+)
+
+// parseUserAgent 从User-Agent中取浏览器及操作系统，无法识别时为空
+func parseUserAgent(ua string) (browser, os string) {
+	return matchUA(browserRules, ua), matchUA(osRules, ua)
+}
+
+func matchUA(rules []uaRule, ua string) string {
+	for _, rule := range rules {
+		m := rule.re.FindStringSubmatch(ua)
+		if m == nil {
+			continue
+		}
+		if len(m[1]) == 0 {
+			return rule.name
+		}
+		return rule.name + " " + strings.Replace(m[1], "_", ".", -1)
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package gochat_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	gochat "github.com/laoqiu/go-chat"
+)
+
+func TestClientIP(t *testing.T) {
+	if err := gochat.TrustProxies("10.0.0.0/8", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	defer gochat.TrustProxies()
+
+	cases := []struct {
+		remote    string
+		realIP    string
+		forwarded string
+		want      string
+	}{
+		// 不可信来源的代理头被忽略
+		{"1.2.3.4:5000", "9.9.9.9", "", "1.2.3.4"},
+		{"1.2.3.4:5000", "", "9.9.9.9", "1.2.3.4"},
+		// 可信代理
+		{"10.0.0.1:5000", "5.6.7.8", "", "5.6.7.8"},
+		{"127.0.0.1:5000", "", "5.6.7.8", "5.6.7.8"},
+		// 客户端伪造的最左侧地址被跳过
+		{"10.0.0.1:5000", "", "9.9.9.9, 5.6.7.8, 10.0.0.2", "5.6.7.8"},
+		{"10.0.0.1:5000", "", "", "10.0.0.1"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/stream", nil)
+		r.RemoteAddr = c.remote
+		if len(c.realIP) > 0 {
+			r.Header.Set("X-Real-IP", c.realIP)
+		}
+		if len(c.forwarded) > 0 {
+			r.Header.Set("X-Forwarded-For", c.forwarded)
+		}
+		if ip := gochat.ClientIP(r); ip != c.want {
+			t.Errorf("clientIP(%+v) = %s, want %s", c, ip, c.want)
+		}
+	}
+
+	if err := gochat.TrustProxies("not-an-ip"); err == nil {
+		t.Fatal("TrustProxies: invalid address accepted")
+	}
+}
@@ -0,0 +1,503 @@
+package gochat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// SSE、长轮询及http发送，用于代理不支持websocket的客户端
+// 认证内容与websocket登录事件的body相同，通过X-Chat-Auth头或auth参数传入
+
+var (
+	// 长轮询默认及最大等待时间
+	PollTimeout    = 25 * time.Second
+	MaxPollTimeout = 60 * time.Second
+	// 长轮询会话空闲超过此时间后关闭stream
+	PollIdle = time.Minute
+	// 长轮询会话最多缓存的事件数，超过时丢弃最早的
+	PollBuffer = 1024
+)
+
+// httpAuth 取出http请求中的认证内容并认证
+func httpAuth(r *http.Request) (*AuthBody, error) {
+	auth := r.Header.Get("X-Chat-Auth")
+	if len(auth) == 0 {
+		auth = r.URL.Query().Get("auth")
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("auth is required")
+	}
+	body, err := authenticate(auth)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Id) == 0 {
+		return nil, errors.New("id is required")
+	}
+	return body, nil
+}
+
+// eventCursor SSE的事件id及长轮询的游标，格式为"时间-事件id"
+type eventCursor struct {
+	created int64
+	id      string
+}
+
+func newCursor(e *proto.Event) eventCursor {
+	created := e.Created
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+	return eventCursor{created: created, id: e.Id}
+}
+
+func parseCursor(s string) eventCursor {
+	i := strings.Index(s, "-")
+	if i < 0 {
+		return eventCursor{}
+	}
+	created, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil {
+		return eventCursor{}
+	}
+	return eventCursor{created: created, id: s[i+1:]}
+}
+
+func (c eventCursor) String() string {
+	if len(c.id) == 0 {
+		return ""
+	}
+	return strconv.FormatInt(c.created, 10) + "-" + c.id
+}
+
+// skip 续传时stream从游标所在的秒开始推送，跳过游标及之前的事件
+func (c *eventCursor) skip(e *proto.Event) bool {
+	if len(c.id) == 0 {
+		return false
+	}
+	if e.Created > c.created {
+		c.id = ""
+		return false
+	}
+	if e.Id == c.id {
+		c.id = ""
+	}
+	return true
+}
+
+// httpStreamRequest 有游标时从游标的时间开始接收，master平台使用持久队列不受影响
+func httpStreamRequest(r *http.Request, auth *AuthBody, cursor eventCursor) *proto.StreamRequest {
+	browser, os := parseUserAgent(r.UserAgent())
+	start := auth.Start
+	if cursor.created > 0 {
+		start = cursor.created
+	}
+	return &proto.StreamRequest{
+		Id:       auth.Id,
+		Platform: auth.Platform,
+		Start:    start,
+		Ip:       clientIP(r),
+		Browser:  browser,
+		Os:       os,
+		Token:    streamToken(auth),
+	}
+}
+
+// recvEvents 读取stream中需要推送的事件，stream结束时关闭返回的channel
+func recvEvents(ctx context.Context, stream proto.Chat_StreamService, cursor eventCursor, log Logger) <-chan *proto.Event {
+	ch := make(chan *proto.Event)
+	go func() {
+		defer close(ch)
+		for {
+			rsp, err := stream.Recv()
+			if err != nil {
+				log.Info("stream closed", ErrField(err))
+				return
+			}
+			if rsp.Event.Type == "heartbeat" || !forwarded(rsp.Event) || cursor.skip(rsp.Event) {
+				continue
+			}
+			log.Debug("stream event", EventField(rsp.Event))
+			select {
+			case ch <- rsp.Event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// NewSSEHandler 以Server-Sent Events推送stream中的事件，data与websocket下发的json相同
+// 断线后浏览器带上Last-Event-ID自动续传
+func NewSSEHandler(cli proto.ChatService, logger Logger, metrics *Metrics) func(http.ResponseWriter, *http.Request) {
+	logger = loggerOrDefault(logger)
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		auth, err := httpAuth(r)
+		if err != nil {
+			logger.Warn("sse login failed", ErrField(err))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log := logger.With(UserField(auth.Id), PlatformField(auth.Platform))
+
+		last := r.Header.Get("Last-Event-ID")
+		if len(last) == 0 {
+			last = r.URL.Query().Get("last_event_id")
+		}
+		cursor := parseCursor(last)
+		stream, err := cli.Stream(context.Background(), httpStreamRequest(r, auth, cursor))
+		if err != nil {
+			log.Error("stream failed", ErrField(err))
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer stream.Close()
+		metrics.connected(auth.Platform, 1)
+		defer metrics.connected(auth.Platform, -1)
+
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		// 关闭nginx缓冲
+		header.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		// 断线后3秒重连
+		fmt.Fprint(w, "retry: 3000\n\n")
+		flusher.Flush()
+
+		events := recvEvents(r.Context(), stream, cursor, log)
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					log.Info("server closed connection")
+					return
+				}
+				b, _ := json.Marshal(event)
+				if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", newCursor(event), event.Type, b); err != nil {
+					log.Warn("sse write failed", ErrField(err))
+					return
+				}
+				flusher.Flush()
+			case <-ticker.C:
+				// 心跳，注释行客户端会忽略
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// PollResponse 长轮询的返回，下次请求带上cursor确认已收到的事件
+type PollResponse struct {
+	Events []*proto.Event `json:"events"`
+	Cursor string         `json:"cursor"`
+}
+
+// pollSession 长轮询在两次请求之间保持stream，未确认的事件留在缓存中重发
+type pollSession struct {
+	stream proto.Chat_StreamService
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	events []*proto.Event
+	notify chan struct{} // 有新事件或stream结束时关闭
+	closed bool
+	seen   time.Time
+}
+
+func (s *pollSession) add(e *proto.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	if len(s.events) > PollBuffer {
+		s.events = s.events[len(s.events)-PollBuffer:]
+	}
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// ack 丢弃游标及之前的事件，返回剩余事件及等待新事件的channel
+func (s *pollSession) ack(cursor eventCursor) ([]*proto.Event, <-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen = time.Now()
+	if len(cursor.id) > 0 {
+		for i, e := range s.events {
+			if e.Id == cursor.id {
+				s.events = s.events[i+1:]
+				break
+			}
+		}
+	}
+	return append([]*proto.Event{}, s.events...), s.notify, s.closed
+}
+
+func (s *pollSession) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *pollSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.notify)
+	}
+}
+
+type pollSessions struct {
+	cli     proto.ChatService
+	log     Logger
+	metrics *Metrics
+	done    <-chan struct{}
+
+	mu       sync.Mutex
+	sessions map[string]*pollSession  // 用户id/平台
+	opening  map[string]chan struct{} // 正在打开stream的会话，打开后关闭
+}
+
+// get 取得会话，不存在或stream已结束时从游标处重新打开stream
+// Stream是远程调用，在锁外执行; 同一会话同时只打开一个stream，否则新的stream会踢掉旧的
+func (p *pollSessions) get(r *http.Request, auth *AuthBody, cursor eventCursor) (*pollSession, error) {
+	key := auth.Id + "/" + auth.Platform
+	p.mu.Lock()
+	for {
+		if s, ok := p.sessions[key]; ok && !s.isClosed() {
+			p.mu.Unlock()
+			return s, nil
+		}
+		opening, ok := p.opening[key]
+		if !ok {
+			break
+		}
+		p.mu.Unlock()
+		select {
+		case <-opening:
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+		p.mu.Lock()
+	}
+	opening := make(chan struct{})
+	p.opening[key] = opening
+	p.mu.Unlock()
+
+	stream, err := p.cli.Stream(context.Background(), httpStreamRequest(r, auth, cursor))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.opening, key)
+	close(opening)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &pollSession{
+		stream: stream,
+		cancel: cancel,
+		notify: make(chan struct{}),
+		seen:   time.Now(),
+	}
+	p.sessions[key] = s
+	p.metrics.connected(auth.Platform, 1)
+
+	log := p.log.With(UserField(auth.Id), PlatformField(auth.Platform))
+	go func() {
+		defer p.metrics.connected(auth.Platform, -1)
+		for e := range recvEvents(ctx, stream, cursor, log) {
+			s.add(e)
+		}
+		s.close()
+		p.mu.Lock()
+		if p.sessions[key] == s {
+			delete(p.sessions, key)
+		}
+		p.mu.Unlock()
+	}()
+	return s, nil
+}
+
+// reap 关闭空闲的会话，stream结束后由Handler下线; done关闭时关闭所有会话并退出
+func (p *pollSessions) reap() {
+	ticker := time.NewTicker(PollIdle / 2)
+	defer ticker.Stop()
+	for {
+		stop := false
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			stop = true
+		}
+		idle := []*pollSession{}
+		p.mu.Lock()
+		for _, s := range p.sessions {
+			s.mu.Lock()
+			if stop || time.Since(s.seen) > PollIdle {
+				idle = append(idle, s)
+			}
+			s.mu.Unlock()
+		}
+		p.mu.Unlock()
+		for _, s := range idle {
+			s.cancel()
+			s.stream.Close()
+		}
+		if stop {
+			return
+		}
+	}
+}
+
+type PollOption func(*pollSessions)
+
+// PollDone done关闭时停止清理空闲会话的goroutine并关闭所有会话
+func PollDone(done <-chan struct{}) PollOption {
+	return func(p *pollSessions) {
+		p.done = done
+	}
+}
+
+// NewPollHandler 长轮询，用于不支持SSE的旧客户端
+// 没有新事件时最多等待timeout秒，返回的事件在下次请求带上cursor前会重复返回
+// 会话保存在网关内存中，多个网关时需要按用户保持会话，切换网关后从cursor处续传
+// 未设置PollDone时清理会话的goroutine一直运行
+func NewPollHandler(cli proto.ChatService, logger Logger, metrics *Metrics, opts ...PollOption) func(http.ResponseWriter, *http.Request) {
+	p := &pollSessions{
+		cli:      cli,
+		log:      loggerOrDefault(logger),
+		metrics:  metrics,
+		sessions: make(map[string]*pollSession),
+		opening:  make(map[string]chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.reap()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth, err := httpAuth(r)
+		if err != nil {
+			p.log.Warn("poll login failed", ErrField(err))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		last := query.Get("cursor")
+		if len(last) == 0 {
+			last = r.Header.Get("Last-Event-ID")
+		}
+		cursor := parseCursor(last)
+		timeout := PollTimeout
+		if t, err := strconv.Atoi(query.Get("timeout")); err == nil && t >= 0 {
+			timeout = time.Duration(t) * time.Second
+			if timeout > MaxPollTimeout {
+				timeout = MaxPollTimeout
+			}
+		}
+
+		s, err := p.get(r, auth, cursor)
+		if err != nil {
+			p.log.Error("stream failed", UserField(auth.Id), ErrField(err))
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		events, notify, closed := s.ack(cursor)
+	wait:
+		for len(events) == 0 && !closed {
+			select {
+			case <-notify:
+				// 稍等片刻合并同时到达的事件
+				time.Sleep(10 * time.Millisecond)
+				events, notify, closed = s.ack(eventCursor{})
+			case <-timer.C:
+				break wait
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		rsp := &PollResponse{Events: events, Cursor: cursor.String()}
+		if len(events) > 0 {
+			rsp.Cursor = newCursor(events[len(events)-1]).String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		json.NewEncoder(w).Encode(rsp)
+	}
+}
+
+// NewSendHandler http发送事件，body与websocket发送的事件相同，from由认证内容设置
+func NewSendHandler(cli proto.ChatService, logger Logger) func(http.ResponseWriter, *http.Request) {
+	logger = loggerOrDefault(logger)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		auth, err := httpAuth(r)
+		if err != nil {
+			logger.Warn("send login failed", ErrField(err))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+		event := &proto.Event{}
+		if err := json.NewDecoder(r.Body).Decode(event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !in(AcceptEvent, event.Type) {
+			http.Error(w, "不能接受的消息类型", http.StatusBadRequest)
+			return
+		}
+		event.From = auth.Id
+
+		// 机器人以token经BotSend发送，其他平台以机器人id发送时由服务端拒绝
+		id, err := sendEvent(context.Background(), cli, streamToken(auth), &proto.SendRequest{Event: event})
+		if err != nil {
+			if rl, ok := ParseRateLimitError(err); ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rl.RetryAfter))))
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(rl)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id": id})
+	}
+}
@@ -0,0 +1,199 @@
+package gochat
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	proto "github.com/laoqiu/go-chat/proto"
+	"github.com/micro/go-micro/metadata"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/laoqiu/go-chat"
+
+// SpanExporter trace导出接口，可使用otel的各类exporter或MemoryExporter
+type SpanExporter = sdktrace.SpanExporter
+
+// InitTracing 创建TracerProvider并设置为全局，同时使用w3c trace context传播
+// 退出前需调用返回值的Shutdown
+func InitTracing(service string, exporter SpanExporter) *sdktrace.TracerProvider {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return tp
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+func eventAttributes(event *proto.Event) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("chat.event.id", event.Id),
+		attribute.String("chat.event.type", event.Type),
+		attribute.String("chat.event.from", event.From),
+		attribute.String("chat.event.to", event.To),
+	)
+}
+
+// endSpan 记录错误并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// headerCarrier go-micro metadata及broker消息头，rpc传输后key的大小写可能改变
+type headerCarrier map[string]string
+
+func (c headerCarrier) Get(key string) string {
+	if v, ok := c[key]; ok {
+		return v
+	}
+	for k, v := range c {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// streamCarrier StreamResponse中的trace字段
+type streamCarrier struct {
+	rsp *proto.StreamResponse
+}
+
+func (c streamCarrier) Get(key string) string {
+	switch key {
+	case "traceparent":
+		return c.rsp.Traceparent
+	case "tracestate":
+		return c.rsp.Tracestate
+	}
+	return ""
+}
+
+func (c streamCarrier) Set(key, value string) {
+	switch key {
+	case "traceparent":
+		c.rsp.Traceparent = value
+	case "tracestate":
+		c.rsp.Tracestate = value
+	}
+}
+
+func (c streamCarrier) Keys() []string {
+	return []string{"traceparent", "tracestate"}
+}
+
+// contextWithTrace 将trace上下文写入rpc的metadata
+func contextWithTrace(ctx context.Context) context.Context {
+	md := metadata.Metadata{}
+	if old, ok := metadata.FromContext(ctx); ok {
+		for k, v := range old {
+			md[k] = v
+		}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(md))
+	return metadata.NewContext(ctx, md)
+}
+
+// traceFromContext 从rpc的metadata中取出trace上下文
+func traceFromContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier(md))
+}
+
+// MemoryExporter 保存在内存中的exporter，用于测试及查看单条消息的完整路径
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+func (e *MemoryExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *MemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans 已导出的所有span
+func (e *MemoryExporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spans := make([]sdktrace.ReadOnlySpan, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// Trace 按开始时间排序返回一个trace的所有span
+func (e *MemoryExporter) Trace(id trace.TraceID) []sdktrace.ReadOnlySpan {
+	spans := []sdktrace.ReadOnlySpan{}
+	for _, s := range e.Spans() {
+		if s.SpanContext().TraceID() == id {
+			spans = append(spans, s)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].StartTime().Before(spans[j].StartTime())
+	})
+	return spans
+}
+
+// EventTrace 按事件id查找其所在的trace
+func (e *MemoryExporter) EventTrace(eventId string) []sdktrace.ReadOnlySpan {
+	for _, s := range e.Spans() {
+		for _, kv := range s.Attributes() {
+			if kv.Key == "chat.event.id" && kv.Value.AsString() == eventId {
+				return e.Trace(s.SpanContext().TraceID())
+			}
+		}
+	}
+	return nil
+}
+
+// Reset 清空已导出的span
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
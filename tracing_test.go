@@ -0,0 +1,93 @@
+package gochat_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// 一条消息从Send到推送给接收者的stream在同一个trace中
+func TestTraceSendToStream(t *testing.T) {
+	exporter := gochat.NewMemoryExporter()
+	tp := gochat.InitTracing("test", exporter)
+	defer tp.Shutdown(context.Background())
+
+	srv, err := gochat.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	cli := srv.Client()
+	for _, id := range []string{"alice", "bob"} {
+		if _, err := cli.Register(context.Background(), &proto.RegisterRequest{User: &proto.User{Id: id, Name: id}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream, err := cli.Stream(context.Background(), &proto.StreamRequest{Id: "bob", Platform: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rsp, err := cli.Presence(context.Background(), &proto.PresenceRequest{Ids: []string{"bob"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Presences[0].Online {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("bob not online")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sent, err := cli.Send(context.Background(), &proto.SendRequest{
+		Event: &proto.Event{Type: "message", From: "alice", To: "bob", Body: "hi"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received *proto.StreamResponse
+	for received == nil {
+		rsp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Event.Id == sent.Id {
+			received = rsp
+		}
+	}
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.EventTrace(sent.Id)
+	names := []string{}
+	for _, s := range spans {
+		names = append(names, s.Name())
+	}
+	for _, want := range []string{"Handler.Send", "broker.Publish", "Conn.Subscribe", "stream.Send"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("trace spans = %v, missing %s", names, want)
+		}
+	}
+	// 网关从StreamResponse中取得同一个trace
+	traceId := spans[0].SpanContext().TraceID().String()
+	if !strings.Contains(received.Traceparent, traceId) {
+		t.Fatalf("traceparent = %q, want trace %s", received.Traceparent, traceId)
+	}
+}
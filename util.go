@@ -2,6 +2,9 @@ package gochat
 
 import (
 	"strings"
+	"unicode/utf8"
+
+	uuid "github.com/satori/go.uuid"
 )
 
 func Map(f func(interface{}) string, items []interface{}) []string {
@@ -38,3 +41,28 @@ func remove(v []string, c string) []string {
 	}
 	return r
 }
+
+// splitIds 逗号分隔的id列表，忽略空白项
+func splitIds(s string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); len(id) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// truncate 截取前n个字符，用于写入定长字段
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// newEventId 服务端生成的事件id，去掉连字符的uuid
+func newEventId() string {
+	u1, _ := uuid.NewV4()
+	return strings.Replace(u1.String(), "-", "", -1)
+}
@@ -0,0 +1,480 @@
+package gochat
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	proto "github.com/laoqiu/go-chat/proto"
+	uuid "github.com/satori/go.uuid"
+)
+
+// 投递记录及死信error字段的长度
+const webhookErrorLength = 200
+
+const (
+	// 签名头，值为 sha256=<hex(hmac_sha256(secret, body))>
+	WebhookSignatureHeader = "X-Chat-Signature"
+	WebhookEventHeader     = "X-Chat-Event"
+	WebhookDeliveryHeader  = "X-Chat-Delivery"
+)
+
+// Webhook 已注册的回调地址
+type Webhook struct {
+	Id      int64     `db:"id"`
+	Url     string    `db:"url"`
+	Secret  string    `db:"secret"`
+	Events  string    `db:"events"` // 逗号分隔，为空时订阅全部
+	Rooms   string    `db:"rooms"`  // 逗号分隔，为空时订阅全部
+	Active  bool      `db:"active"`
+	Created time.Time `db:"created"`
+}
+
+// match 判断事件是否符合webhook的过滤条件
+func (w *Webhook) match(eventType, roomId string) bool {
+	if len(w.Events) > 0 && !in(strings.Split(w.Events, ","), eventType) {
+		return false
+	}
+	if len(w.Rooms) > 0 && !in(strings.Split(w.Rooms, ","), roomId) {
+		return false
+	}
+	return true
+}
+
+func (w *Webhook) ToProto() *proto.Webhook {
+	hook := &proto.Webhook{
+		Id:      w.Id,
+		Url:     w.Url,
+		Active:  w.Active,
+		Created: w.Created.Unix(),
+	}
+	if len(w.Events) > 0 {
+		hook.Events = strings.Split(w.Events, ",")
+	}
+	if len(w.Rooms) > 0 {
+		hook.Rooms = strings.Split(w.Rooms, ",")
+	}
+	return hook
+}
+
+// WebhookDelivery 单次投递记录
+type WebhookDelivery struct {
+	WebhookId  int64  `db:"webhook_id"`
+	DeliveryId string `db:"delivery_id"`
+	EventId    string `db:"event_id"`
+	EventType  string `db:"event_type"`
+	Attempt    int    `db:"attempt"`
+	StatusCode int    `db:"status_code"`
+	Error      string `db:"error"`
+}
+
+// WebhookPayload 投递给回调地址的内容
+type WebhookPayload struct {
+	Id      string       `json:"id"`
+	Type    string       `json:"type"`
+	Room    string       `json:"room,omitempty"`
+	Event   *proto.Event `json:"event"`
+	Created int64        `json:"created"`
+}
+
+type WebhookRepository interface {
+	// 注册webhook
+	CreateWebhook(hook *Webhook) error
+	// 删除webhook
+	DeleteWebhook(id int64) error
+	// 已启用的webhook列表
+	Webhooks() ([]*Webhook, error)
+	// 记录投递日志
+	LogDelivery(delivery *WebhookDelivery) error
+	// 投递失败进入死信
+	DeadLetter(webhookId int64, deliveryId string, payload []byte, reason string) error
+}
+
+func NewWebhookRepo(db *sqlx.DB) *webhookRepo {
+	return &webhookRepo{
+		db: db,
+	}
+}
+
+type webhookRepo struct {
+	db *sqlx.DB
+}
+
+func (r *webhookRepo) CreateWebhook(hook *Webhook) error {
+	id, err := insertId(r.db, `
+		INSERT INTO webhooks (url, secret, events, rooms, active) VALUES (?, ?, ?, ?, ?)
+		`, hook.Url, hook.Secret, hook.Events, hook.Rooms, hook.Active)
+	if err != nil {
+		return err
+	}
+	hook.Id = id
+	return r.db.Get(&hook.Created, r.db.Rebind(`SELECT created FROM webhooks WHERE id = ?`), id)
+}
+
+func (r *webhookRepo) DeleteWebhook(id int64) error {
+	_, err := r.db.Exec(r.db.Rebind(`DELETE FROM webhooks WHERE id = ?`), id)
+	return err
+}
+
+func (r *webhookRepo) Webhooks() ([]*Webhook, error) {
+	hooks := []*Webhook{}
+	err := r.db.Select(&hooks, r.db.Rebind(`
+		SELECT id, url, secret, events, rooms, active, created FROM webhooks WHERE active = ?
+		`), true)
+	return hooks, err
+}
+
+func (r *webhookRepo) LogDelivery(d *WebhookDelivery) error {
+	_, err := r.db.Exec(r.db.Rebind(`
+		INSERT INTO webhook_deliveries (webhook_id, delivery_id, event_id, event_type, attempt, status_code, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`), d.WebhookId, d.DeliveryId, d.EventId, d.EventType, d.Attempt, d.StatusCode, truncate(d.Error, webhookErrorLength))
+	return err
+}
+
+func (r *webhookRepo) DeadLetter(webhookId int64, deliveryId string, payload []byte, reason string) error {
+	_, err := r.db.Exec(r.db.Rebind(`
+		INSERT INTO webhook_dead_letters (webhook_id, delivery_id, payload, error) VALUES (?, ?, ?, ?)
+		`), webhookId, deliveryId, string(payload), truncate(reason, webhookErrorLength))
+	return err
+}
+
+type WebhookOption func(*Webhooks)
+
+// WebhookClient 设置投递使用的http.Client
+func WebhookClient(c *http.Client) WebhookOption {
+	return func(w *Webhooks) {
+		w.client = c
+	}
+}
+
+// WebhookRetries 设置最大投递次数(含首次)
+func WebhookRetries(n int) WebhookOption {
+	return func(w *Webhooks) {
+		w.attempts = n
+	}
+}
+
+// WebhookBackoff 设置首次重试等待时间，之后每次翻倍，最长不超过max
+func WebhookBackoff(base, max time.Duration) WebhookOption {
+	return func(w *Webhooks) {
+		w.backoff = base
+		w.maxBackoff = max
+	}
+}
+
+// WebhookLogger 设置日志
+func WebhookLogger(l Logger) WebhookOption {
+	return func(w *Webhooks) {
+		w.log = l
+	}
+}
+
+// WebhookCacheTTL 设置webhook列表的缓存时间，本节点增删时立即失效
+func WebhookCacheTTL(d time.Duration) WebhookOption {
+	return func(w *Webhooks) {
+		w.cacheTTL = d
+	}
+}
+
+// WebhookWorkers 设置并发投递数
+func WebhookWorkers(n int) WebhookOption {
+	return func(w *Webhooks) {
+		w.workers = n
+	}
+}
+
+type webhookJob struct {
+	hook    *Webhook
+	payload *WebhookPayload
+	body    []byte
+	attempt int    // 已投递次数
+	lastErr string // 最后一次错误
+}
+
+// Webhooks 负责把聊天事件签名后POST给已注册的回调地址
+type Webhooks struct {
+	repo       WebhookRepository
+	client     *http.Client
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	workers    int
+	cacheTTL   time.Duration
+	log        Logger
+
+	jobs chan *webhookJob
+	wg   sync.WaitGroup
+
+	// 等待重试的投递，重试由定时器重新入队，不占用worker
+	mu     sync.Mutex
+	closed bool
+	timers map[*webhookJob]*time.Timer
+
+	// webhook列表缓存
+	cmu      sync.Mutex
+	hooks    []*Webhook
+	cachedAt time.Time
+}
+
+func NewWebhooks(repo WebhookRepository, opts ...WebhookOption) *Webhooks {
+	w := &Webhooks{
+		repo:       repo,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		attempts:   5,
+		backoff:    time.Second,
+		maxBackoff: time.Minute,
+		workers:    4,
+		cacheTTL:   30 * time.Second,
+		timers:     make(map[*webhookJob]*time.Timer),
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	w.log = loggerOrDefault(w.log)
+	w.jobs = make(chan *webhookJob, 1024)
+	for i := 0; i < w.workers; i++ {
+		w.wg.Add(1)
+		go w.run()
+	}
+	return w
+}
+
+// Create 注册webhook并使缓存失效
+func (w *Webhooks) Create(hook *Webhook) error {
+	defer w.invalidate()
+	return w.repo.CreateWebhook(hook)
+}
+
+// Delete 删除webhook并使缓存失效
+func (w *Webhooks) Delete(id int64) error {
+	defer w.invalidate()
+	return w.repo.DeleteWebhook(id)
+}
+
+func (w *Webhooks) invalidate() {
+	w.cmu.Lock()
+	w.hooks = nil
+	w.cmu.Unlock()
+}
+
+// webhooks 已启用的webhook，缓存cacheTTL，避免每次发送都查询数据库
+func (w *Webhooks) webhooks() ([]*Webhook, error) {
+	w.cmu.Lock()
+	defer w.cmu.Unlock()
+	if w.hooks != nil && time.Since(w.cachedAt) < w.cacheTTL {
+		return w.hooks, nil
+	}
+	hooks, err := w.repo.Webhooks()
+	if err != nil {
+		return nil, err
+	}
+	w.hooks = hooks
+	w.cachedAt = time.Now()
+	return hooks, nil
+}
+
+// Dispatch 将事件投递给所有匹配的webhook，非阻塞
+func (w *Webhooks) Dispatch(roomId string, event *proto.Event) {
+	hooks, err := w.webhooks()
+	if err != nil {
+		w.log.Error("webhook list failed", ErrField(err))
+		return
+	}
+	for _, hook := range hooks {
+		if !hook.match(event.Type, roomId) {
+			continue
+		}
+		u1, _ := uuid.NewV4()
+		job := &webhookJob{
+			hook: hook,
+			payload: &WebhookPayload{
+				Id:      strings.Replace(u1.String(), "-", "", -1),
+				Type:    event.Type,
+				Room:    roomId,
+				Event:   event,
+				Created: time.Now().Unix(),
+			},
+		}
+		if !w.enqueue(job) {
+			// 队列已满直接进入死信，避免阻塞消息发送
+			w.log.Warn("webhook queue full", F("webhook", hook.Id), F("delivery", job.payload.Id))
+			w.deadLetter(job, "queue full")
+		}
+	}
+}
+
+// enqueue 非阻塞入队，队列已满或已关闭时返回false
+func (w *Webhooks) enqueue(job *webhookJob) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return false
+	}
+	select {
+	case w.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// retry 等待退避时间后重新入队
+func (w *Webhooks) retry(job *webhookJob) {
+	wait := w.backoff
+	for i := 1; i < job.attempt && wait < w.maxBackoff; i++ {
+		wait = wait * 2
+	}
+	if wait > w.maxBackoff {
+		wait = w.maxBackoff
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		w.deadLetter(job, "shutdown: "+job.lastErr)
+		return
+	}
+	w.timers[job] = time.AfterFunc(wait, func() {
+		w.mu.Lock()
+		delete(w.timers, job)
+		w.mu.Unlock()
+		if !w.enqueue(job) {
+			w.deadLetter(job, "retry dropped: "+job.lastErr)
+		}
+	})
+	w.mu.Unlock()
+}
+
+// Close 停止接收新事件，等待队列中的投递完成，等待重试的投递进入死信
+func (w *Webhooks) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		w.wg.Wait()
+		return
+	}
+	w.closed = true
+	pending := []*webhookJob{}
+	for job, t := range w.timers {
+		if t.Stop() {
+			pending = append(pending, job)
+		}
+	}
+	w.timers = nil
+	close(w.jobs)
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	for _, job := range pending {
+		w.deadLetter(job, "shutdown: "+job.lastErr)
+	}
+}
+
+func (w *Webhooks) deadLetter(job *webhookJob, reason string) {
+	body := job.body
+	if body == nil {
+		body, _ = json.Marshal(job.payload)
+	}
+	if err := w.repo.DeadLetter(job.hook.Id, job.payload.Id, body, reason); err != nil {
+		w.log.Error("webhook dead letter failed", F("webhook", job.hook.Id), ErrField(err))
+	}
+}
+
+func (w *Webhooks) run() {
+	defer w.wg.Done()
+	for job := range w.jobs {
+		w.deliver(job)
+	}
+}
+
+// deliver 投递一次，失败时交给retry，不在worker中等待
+func (w *Webhooks) deliver(job *webhookJob) {
+	if job.body == nil {
+		body, err := json.Marshal(job.payload)
+		if err != nil {
+			w.log.Error("webhook marshal failed", ErrField(err))
+			return
+		}
+		job.body = body
+	}
+
+	job.attempt++
+	code, err := w.post(job)
+	delivery := &WebhookDelivery{
+		WebhookId:  job.hook.Id,
+		DeliveryId: job.payload.Id,
+		EventId:    job.payload.Event.Id,
+		EventType:  job.payload.Type,
+		Attempt:    job.attempt,
+		StatusCode: code,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	if err := w.repo.LogDelivery(delivery); err != nil {
+		w.log.Error("webhook delivery log failed", F("webhook", job.hook.Id), ErrField(err))
+	}
+	if err == nil {
+		return
+	}
+	job.lastErr = err.Error()
+
+	if job.attempt < w.attempts {
+		w.retry(job)
+		return
+	}
+	w.log.Warn("webhook delivery failed", F("webhook", job.hook.Id), F("delivery", job.payload.Id), ErrField(err))
+	w.deadLetter(job, job.lastErr)
+}
+
+func (w *Webhooks) post(job *webhookJob) (int, error) {
+	body := job.body
+	req, err := http.NewRequest("POST", job.hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, job.payload.Type)
+	req.Header.Set(WebhookDeliveryHeader, job.payload.Id)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+Sign(job.hook.Secret, body))
+
+	rsp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, fmt.Errorf("unexpected status %d", rsp.StatusCode)
+	}
+	return rsp.StatusCode, nil
+}
+
+// Sign 计算webhook内容的HMAC-SHA256签名
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature 供接收方校验签名头
+func VerifySignature(secret string, body []byte, signature string) error {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return errors.New("invalid signature format")
+	}
+	expected := Sign(secret, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256="))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
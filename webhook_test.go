@@ -0,0 +1,218 @@
+package gochat_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gochat "github.com/laoqiu/go-chat"
+	proto "github.com/laoqiu/go-chat/proto"
+)
+
+// webhookRepo 内存WebhookRepository，记录投递及死信
+type webhookRepo struct {
+	mu         sync.Mutex
+	hooks      []*gochat.Webhook
+	lists      int
+	deliveries []*gochat.WebhookDelivery
+	dead       []string // 死信的错误信息
+}
+
+func (r *webhookRepo) CreateWebhook(hook *gochat.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook.Id = int64(len(r.hooks) + 1)
+	hook.Created = time.Now()
+	r.hooks = append(r.hooks, hook)
+	return nil
+}
+
+func (r *webhookRepo) DeleteWebhook(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hooks := []*gochat.Webhook{}
+	for _, hook := range r.hooks {
+		if hook.Id != id {
+			hooks = append(hooks, hook)
+		}
+	}
+	r.hooks = hooks
+	return nil
+}
+
+func (r *webhookRepo) Webhooks() ([]*gochat.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists++
+	return append([]*gochat.Webhook{}, r.hooks...), nil
+}
+
+func (r *webhookRepo) LogDelivery(d *gochat.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *webhookRepo) DeadLetter(webhookId int64, deliveryId string, payload []byte, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dead = append(r.dead, reason)
+	return nil
+}
+
+func (r *webhookRepo) counts() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.deliveries), len(r.dead)
+}
+
+func newTestWebhooks(repo *webhookRepo, attempts int) *gochat.Webhooks {
+	return gochat.NewWebhooks(repo,
+		gochat.WebhookRetries(attempts),
+		gochat.WebhookBackoff(10*time.Millisecond, 20*time.Millisecond),
+		gochat.WebhookWorkers(1),
+	)
+}
+
+func waitCounts(t *testing.T, repo *webhookRepo, deliveries, dead int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		d, n := repo.counts()
+		if d == deliveries && n == dead {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("deliveries = %d, dead letters = %d, want %d, %d", d, n, deliveries, dead)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	received := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(gochat.WebhookEventHeader) != "message" {
+			t.Errorf("event header = %q", r.Header.Get(gochat.WebhookEventHeader))
+		}
+		received <- gochat.VerifySignature("secret", body, r.Header.Get(gochat.WebhookSignatureHeader))
+	}))
+	defer srv.Close()
+
+	repo := &webhookRepo{}
+	w := newTestWebhooks(repo, 1)
+	defer w.Close()
+	if err := w.Create(&gochat.Webhook{Url: srv.URL, Secret: "secret", Active: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	w.Dispatch("r1", &proto.Event{Id: "e1", Type: "message", Body: "hi"})
+	select {
+	case err := <-received:
+		if err != nil {
+			t.Fatalf("VerifySignature: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	waitCounts(t, repo, 1, 0)
+
+	if err := gochat.VerifySignature("other", []byte("{}"), "sha256="+gochat.Sign("secret", []byte("{}"))); err == nil {
+		t.Fatal("VerifySignature: wrong secret accepted")
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 前两次失败
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	repo := &webhookRepo{}
+	w := newTestWebhooks(repo, 5)
+	defer w.Close()
+	w.Create(&gochat.Webhook{Url: srv.URL, Secret: "secret", Active: true})
+
+	w.Dispatch("", &proto.Event{Id: "e1", Type: "message"})
+	waitCounts(t, repo, 3, 0)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for i, d := range repo.deliveries {
+		if d.Attempt != i+1 {
+			t.Fatalf("delivery %d attempt = %d", i, d.Attempt)
+		}
+	}
+	if last := repo.deliveries[2]; last.StatusCode != http.StatusOK || len(last.Error) > 0 {
+		t.Fatalf("last delivery = %+v", last)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer dead.Close()
+	var healthy int32
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&healthy, 1)
+	}))
+	defer ok.Close()
+
+	repo := &webhookRepo{}
+	w := newTestWebhooks(repo, 3)
+	defer w.Close()
+	w.Create(&gochat.Webhook{Url: dead.URL, Secret: "secret", Active: true})
+	w.Create(&gochat.Webhook{Url: ok.URL, Secret: "secret", Active: true})
+
+	w.Dispatch("", &proto.Event{Id: "e1", Type: "message"})
+	// 失败的webhook等待重试时不占用worker，健康的webhook立即投递
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&healthy) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("healthy webhook not delivered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 3次失败 + 1次成功，失败的进入死信
+	waitCounts(t, repo, 4, 1)
+}
+
+func TestWebhookCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	repo := &webhookRepo{}
+	w := newTestWebhooks(repo, 1)
+	defer w.Close()
+	w.Create(&gochat.Webhook{Url: srv.URL, Secret: "secret", Active: true})
+
+	for i := 0; i < 3; i++ {
+		w.Dispatch("", &proto.Event{Type: "message"})
+	}
+	waitCounts(t, repo, 3, 0)
+	repo.mu.Lock()
+	lists := repo.lists
+	repo.mu.Unlock()
+	if lists != 1 {
+		t.Fatalf("Webhooks queried %d times, want 1", lists)
+	}
+
+	// 删除后缓存失效
+	if err := w.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	w.Dispatch("", &proto.Event{Type: "message"})
+	time.Sleep(50 * time.Millisecond)
+	waitCounts(t, repo, 3, 0)
+}
@@ -1,19 +1,20 @@
 package gochat
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	proto "github.com/laoqiu/go-chat/proto"
 	config "github.com/micro/go-config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -36,7 +37,7 @@ var (
 	upgrader = websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool { return true },
 	}
-	AcceptEvent = []string{"message", "notify", "receipt", "candidate", "sdp"}
+	AcceptEvent = []string{"message", "notify", "receipt", "candidate", "sdp", EncryptedEvent}
 )
 
 type AuthBody struct {
@@ -50,103 +51,196 @@ type connection struct {
 	id       string // 用户id
 	platform string
 	start    int64
+	token    string // 机器人token
 	send     chan *proto.Event
 	ws       *websocket.Conn
+	codec    frameCodec
 	cli      proto.ChatService
 	stream   proto.Chat_StreamService
+	log      Logger
+
+	// 等待writer写出的事件span
+	smu   sync.Mutex
+	spans map[*proto.Event]trace.Span
 }
 
-func NewWebsocketHandler(cli proto.ChatService) func(http.ResponseWriter, *http.Request) {
+// NewWebsocketHandler websocket网关，帧格式由子协议协商，见Subprotocols
+func NewWebsocketHandler(cli proto.ChatService, logger Logger, metrics *Metrics, opts ...WebsocketOption) func(http.ResponseWriter, *http.Request) {
+	logger = loggerOrDefault(logger)
+	o := websocketOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	up := newUpgrader(o)
 	return func(w http.ResponseWriter, r *http.Request) {
-		ws, err := upgrader.Upgrade(w, r, nil)
+		ws, err := up.Upgrade(w, r, nil)
 		if err != nil {
-			log.Println("Upgrade: ", err)
+			logger.Warn("websocket upgrade failed", ErrField(err))
 			return
 		}
 		defer ws.Close()
+		if o.compression {
+			// 未协商permessage-deflate时无效
+			if err := ws.SetCompressionLevel(o.level); err != nil {
+				logger.Warn("websocket compression level", ErrField(err))
+			}
+		}
 
 		// 初始化客户端
 		conn := &connection{
-			ws:   ws,
-			cli:  cli,
-			send: make(chan *proto.Event),
+			ws:    ws,
+			codec: codecFor(ws.Subprotocol()),
+			cli:   cli,
+			send:  make(chan *proto.Event),
+			log:   logger,
+			spans: make(map[*proto.Event]trace.Span),
 		}
 
 		if err := conn.login(); err != nil {
-			log.Println(err)
+			logger.Warn("websocket login failed", ErrField(err))
 			return
 		}
+		conn.log = logger.With(UserField(conn.id), PlatformField(conn.platform))
+		metrics.connected(conn.platform, 1)
+		defer metrics.connected(conn.platform, -1)
 
 		// stream
+		browser, os := parseUserAgent(r.UserAgent())
 		stream, err := cli.Stream(context.Background(), &proto.StreamRequest{
 			Id:       conn.id,
 			Platform: conn.platform,
 			Start:    conn.start,
+			Ip:       clientIP(r),
+			Browser:  browser,
+			Os:       os,
+			Token:    conn.token,
 		})
 		if err != nil {
-			fmt.Println("stream err", err)
+			conn.log.Error("stream failed", ErrField(err))
 			return
 		}
 		defer stream.Close()
 
-		go conn.subscribe(stream)
+		ready := make(chan bool, 1)
+		go conn.subscribe(stream, ready)
 		go conn.writer()
 
+		// Handler.Stream校验通过(收到第一个响应)后才处理客户端的事件
+		if !<-ready {
+			return
+		}
+
 		if err := conn.reader(); err != nil {
-			log.Println(err)
+			conn.log.Warn("websocket read failed", ErrField(err))
 		}
 	}
 }
 
 func (c *connection) login() error {
-	var (
-		event proto.Event
-		url   string
-	)
-	if err := c.ws.ReadJSON(&event); err != nil {
+	var event proto.Event
+	_, message, err := c.ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := c.codec.unmarshal(message, &event); err != nil {
+		return err
+	}
+
+	body, err := authenticate(event.Body)
+	if err != nil {
 		return err
 	}
 
+	// 登录成功
+	c.id = body.Id
+	c.platform = body.Platform
+	c.start = body.Start
+	c.token = streamToken(body)
+
+	return nil
+}
+
+// authenticate 将认证内容提交到配置的auth地址并解析，websocket及http传输共用
+func authenticate(auth string) (*AuthBody, error) {
+	var url string
 	config.Get("auth").Scan(&url)
 
-	if _, err := http.Post(url, "application/json", strings.NewReader(event.Body)); err != nil {
-		return errors.New("认证请求错误:" + err.Error())
+	if _, err := http.Post(url, "application/json", strings.NewReader(auth)); err != nil {
+		return nil, errors.New("认证请求错误:" + err.Error())
 	}
 
 	// 认证内容分析
 	body := &AuthBody{}
-	if len(event.Body) > 0 {
-		if err := json.Unmarshal([]byte(event.Body), body); err != nil {
-			return err
+	if len(auth) > 0 {
+		if err := json.Unmarshal([]byte(auth), body); err != nil {
+			return nil, err
 		}
 	}
+	return body, nil
+}
 
-	// 登录成功
-	c.id = body.Id
-	c.platform = body.Platform
-	c.start = body.Start
+// streamToken 机器人以BotPlatform连接时，认证内容中的password为机器人token
+func streamToken(auth *AuthBody) string {
+	if auth.Platform == BotPlatform {
+		return auth.Password
+	}
+	return ""
+}
 
-	return nil
+// sendEvent 机器人以token经BotSend发送，由服务端验证身份，其他用户直接Send
+func sendEvent(ctx context.Context, cli proto.ChatService, token string, req *proto.SendRequest) (string, error) {
+	if len(token) > 0 {
+		rsp, err := cli.BotSend(ctx, &proto.BotSendRequest{Token: token, Event: req.Event, SendAt: req.SendAt})
+		if err != nil {
+			return "", err
+		}
+		return rsp.Id, nil
+	}
+	rsp, err := cli.Send(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return rsp.Id, nil
 }
 
-func (c *connection) subscribe(stream proto.Chat_StreamService) {
+// subscribe 收到第一个响应时向ready发送true，stream在此之前结束时发送false
+func (c *connection) subscribe(stream proto.Chat_StreamService, ready chan<- bool) {
 	for {
 		rsp, err := stream.Recv()
 		if err != nil {
-			fmt.Println("stream recv err", err)
+			c.log.Info("stream closed", ErrField(err))
+			if ready != nil {
+				c.send <- errorEvent("", err)
+				ready <- false
+			}
 			close(c.send)
 			return
 		}
+		if ready != nil {
+			ready <- true
+			ready = nil
+		}
 		if rsp.Event.Type == "heartbeat" {
 			continue
 		}
-		fmt.Println("rsp.event ->", rsp.Event)
-		if in(AcceptEvent, rsp.Event.Type) {
+		c.log.Debug("stream event", EventField(rsp.Event))
+		if forwarded(rsp.Event) {
+			ctx := otel.GetTextMapPropagator().Extract(context.Background(), streamCarrier{rsp})
+			_, span := tracer().Start(ctx, "connection.writer", eventAttributes(rsp.Event))
+			c.smu.Lock()
+			c.spans[rsp.Event] = span
+			c.smu.Unlock()
 			c.send <- rsp.Event
 		}
 	}
 }
 
+// forwarded 需要推送给客户端的事件
+func forwarded(event *proto.Event) bool {
+	return in(AcceptEvent, event.Type) || event.Type == CommandEvent || event.Type == KickedEvent ||
+		event.Type == StatusEvent || event.Type == ReactionEvent || in(roomEvents, event.Type)
+}
+
 func (c *connection) reader() error {
 	// setting
 	c.ws.SetReadLimit(maxMessageSize)
@@ -159,20 +253,18 @@ func (c *connection) reader() error {
 		if err != nil {
 			// 判断客户端是否异常断开
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("IsUnexpectedCloseError: %v", err)
+				c.log.Info("websocket closed unexpectedly", ErrField(err))
 				break
 			}
 			return err
 		}
 
-		message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
-
 		event := proto.Event{}
-		if err := json.Unmarshal(message, &event); err != nil {
+		if err := c.codec.unmarshal(message, &event); err != nil {
 			return err
 		}
 
-		log.Println("websocket reader ->", event, c.id)
+		c.log.Debug("websocket event", EventField(&event))
 
 		if len(c.id) != 0 {
 			switch event.Type {
@@ -228,18 +320,270 @@ func (c *connection) reader() error {
 						Body: err.Error(),
 					}
 				}
-			case "message", "receipt", "candidate", "sdp":
+			case "upload_keys":
+				req := &proto.UploadKeysRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				rsp, err := c.cli.UploadKeys(context.Background(), req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "keys_uploaded",
+						Body: string(d),
+					}
+				}
+			case "fetch_keys":
+				rsp, err := c.cli.FetchKeys(context.Background(), &proto.FetchKeysRequest{
+					Id:     c.id,
+					UserId: event.To,
+				})
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp.Bundle)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "keys",
+						Body: string(d),
+					}
+				}
+			case "receipts":
+				rsp, err := c.cli.Receipts(context.Background(), &proto.ReceiptsRequest{
+					Id:        c.id,
+					MessageId: event.Body,
+				})
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "receipts",
+						Body: string(d),
+					}
+				}
+			case "history":
+				req := &proto.HistoryRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				rsp, err := c.cli.History(context.Background(), req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "history",
+						Body: string(d),
+					}
+				}
+			case "thread":
+				req := &proto.ThreadRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				rsp, err := c.cli.Thread(context.Background(), req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "thread",
+						Body: string(d),
+					}
+				}
+			case "follow_thread":
+				if _, err := c.cli.FollowThread(context.Background(), &proto.FollowThreadRequest{
+					Id:       c.id,
+					ParentId: event.Body,
+				}); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "unfollow_thread":
+				if _, err := c.cli.UnfollowThread(context.Background(), &proto.UnfollowThreadRequest{
+					Id:       c.id,
+					ParentId: event.Body,
+				}); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "react":
+				req := &proto.ReactRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				rsp, err := c.cli.React(context.Background(), req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp.Reactions)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "reactions",
+						Body: string(d),
+					}
+				}
+			case "mentions":
+				req := &proto.MentionsRequest{}
+				if len(event.Body) > 0 {
+					if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+						c.send <- errorEvent(event.Id, err)
+						break
+					}
+				}
+				req.Id = c.id
+				rsp, err := c.cli.Mentions(context.Background(), req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "mentions",
+						Body: string(d),
+					}
+				}
+			case "pin_message":
+				req := &proto.PinRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				if _, err := c.cli.Pin(context.Background(), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "unpin_message":
+				req := &proto.UnpinRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				if _, err := c.cli.Unpin(context.Background(), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "set_notice":
+				req := &proto.SetNoticeRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				if _, err := c.cli.SetNotice(context.Background(), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case PinnedEvent:
+				rsp, err := c.cli.Pinned(context.Background(), &proto.PinnedRequest{
+					Id:     c.id,
+					RoomId: event.Body,
+				})
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: PinnedEvent,
+						Body: string(d),
+					}
+				}
+			case "schedule":
+				// Body为SendRequest，event.from由网关设置
+				req := &proto.SendRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil || req.Event == nil {
+					c.send <- errorEvent(event.Id, errors.New("invalid schedule"))
+					break
+				}
+				req.Event.From = c.id
+				id, err := sendEvent(context.Background(), c.cli, c.token, req)
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "schedule",
+						Body: id,
+					}
+				}
+			case "scheduled":
+				rsp, err := c.cli.Scheduled(context.Background(), &proto.ScheduledRequest{Id: c.id})
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					d, _ := json.Marshal(rsp)
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "scheduled",
+						Body: string(d),
+					}
+				}
+			case "edit_scheduled":
+				req := &proto.EditScheduledRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				if _, err := c.cli.EditScheduled(context.Background(), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "cancel_scheduled":
+				if _, err := c.cli.CancelScheduled(context.Background(), &proto.CancelScheduledRequest{
+					Id:      c.id,
+					EventId: event.Body,
+				}); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "message_ttl":
+				rsp, err := c.cli.MessageTTL(context.Background(), &proto.MessageTTLRequest{
+					Id: c.id,
+					To: event.Body,
+				})
+				if err != nil {
+					c.send <- errorEvent(event.Id, err)
+				} else {
+					c.send <- &proto.Event{
+						Id:   event.Id,
+						Type: "message_ttl",
+						Body: strconv.FormatInt(rsp.Ttl, 10),
+					}
+				}
+			case "set_message_ttl":
+				req := &proto.SetMessageTTLRequest{}
+				if err := json.Unmarshal([]byte(event.Body), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+					break
+				}
+				req.Id = c.id
+				if _, err := c.cli.SetMessageTTL(context.Background(), req); err != nil {
+					c.send <- errorEvent(event.Id, err)
+				}
+			case "message", "receipt", "candidate", "sdp", EncryptedEvent:
 				// 重置From
 				event.From = c.id
 				// 发送
-				_, err := c.cli.Send(context.Background(), &proto.SendRequest{
+				ctx, span := tracer().Start(context.Background(), "websocket.reader",
+					trace.WithSpanKind(trace.SpanKindServer), eventAttributes(&event))
+				_, err := sendEvent(contextWithTrace(ctx), c.cli, c.token, &proto.SendRequest{
 					Event: &event,
 				})
+				endSpan(span, err)
 				if err != nil {
-					c.send <- &proto.Event{
-						Type: "error",
-						Body: err.Error(),
-					}
+					c.send <- errorEvent(event.Id, err)
 				} else {
 					c.send <- &proto.Event{
 						Id:   event.Id,
@@ -267,12 +611,13 @@ func (c *connection) writer() {
 			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
 				// 服务端断开连接
-				log.Println("服务端断开连接")
+				c.log.Info("server closed connection")
 				c.ws.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
-			//log.Println("websocket writejson ->", event)
-			if err := c.ws.WriteJSON(event); err != nil {
+			err := c.write(event)
+			c.endSpan(event, err)
+			if err != nil {
 				return
 			}
 		case <-ticker.C:
@@ -284,3 +629,40 @@ func (c *connection) writer() {
 		}
 	}
 }
+
+// write 按协商的格式写出事件，启用压缩时只压缩较大的帧
+func (c *connection) write(event *proto.Event) error {
+	messageType, data, err := c.codec.marshal(event)
+	if err != nil {
+		return err
+	}
+	c.ws.EnableWriteCompression(len(data) >= minCompressSize)
+	return c.ws.WriteMessage(messageType, data)
+}
+
+// endSpan 结束事件从stream到写出websocket的span
+func (c *connection) endSpan(event *proto.Event, err error) {
+	c.smu.Lock()
+	span, ok := c.spans[event]
+	delete(c.spans, event)
+	c.smu.Unlock()
+	if ok {
+		endSpan(span, err)
+	}
+}
+
+// errorEvent 频率限制错误下发retry_after等结构化内容，其它错误直接下发错误信息
+func errorEvent(id string, err error) *proto.Event {
+	if rl, ok := ParseRateLimitError(err); ok {
+		return &proto.Event{
+			Id:   id,
+			Type: "error",
+			Body: rl.Error(),
+		}
+	}
+	return &proto.Event{
+		Id:   id,
+		Type: "error",
+		Body: err.Error(),
+	}
+}